@@ -0,0 +1,365 @@
+package broadcast
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	telemetry "github.com/vwhitteron/gt-telemetry"
+	"github.com/vwhitteron/gt-telemetry/exporter"
+)
+
+const (
+	defaultRate      = 20
+	defaultMaxRate   = 60
+	defaultBuffer    = 120
+	clientQueueSize  = 16
+	clientWriteLimit = 5 * time.Second
+	// Packets arriving this far ahead of a client's schedule are still sent so
+	// clients running at the packet rate are not skipped
+	scheduleJitter = 4 * time.Millisecond
+)
+
+//go:embed static
+var staticFiles embed.FS
+
+type Options struct {
+	Units telemetry.UnitSystem
+	// Channels sent to clients that do not select their own, defaults to all
+	// channels.
+	Channels []string
+	// Rate is the number of updates per second sent to clients that do not
+	// request a rate, defaults to 20.
+	Rate int
+	// MaxRate caps the update rate a client can request, defaults to 60.
+	MaxRate int
+	// AllowedOrigins are the browser origins allowed to connect besides the
+	// origin the server is reached on, such as "http://pitwall.local".
+	AllowedOrigins []string
+	// AllowAnyOrigin accepts connections from pages on any origin, which
+	// lets any web page the user visits read the telemetry.
+	AllowAnyOrigin bool
+}
+
+// Server broadcasts telemetry frames as JSON messages to WebSocket clients.
+// Each client can select its own channels and update rate, either with the
+// channels and rate query parameters when connecting or by sending a message
+// such as {"channels":["ground_speed","engine_rpm"],"rate":10}.
+type Server struct {
+	opts     Options
+	channels []telemetry.Channel
+	mu       sync.Mutex
+	clients  map[*client]struct{}
+	closed   bool
+}
+
+type client struct {
+	conn     *wsConn
+	send     chan []byte
+	encoder  *exporter.JSONLEncoder
+	buffer   bytes.Buffer
+	interval time.Duration
+	next     time.Time
+}
+
+type clientConfig struct {
+	Channels []string `json:"channels"`
+	Rate     int      `json:"rate"`
+}
+
+// New creates a broadcast server. When a client is given the server
+// subscribes to it and broadcasts every decoded frame, otherwise frames are
+// sent with Broadcast.
+func New(gt *telemetry.GTClient, opts Options) (*Server, error) {
+	if opts.Rate < 1 {
+		opts.Rate = defaultRate
+	}
+	if opts.MaxRate < 1 {
+		opts.MaxRate = defaultMaxRate
+	}
+	if opts.Rate > opts.MaxRate {
+		opts.Rate = opts.MaxRate
+	}
+
+	channels, err := telemetry.LookupChannels(opts.Units, opts.Channels)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		opts:     opts,
+		channels: channels,
+		clients:  map[*client]struct{}{},
+	}
+
+	if gt != nil {
+		frames := gt.Subscribe(defaultBuffer)
+		go func() {
+			for frame := range frames {
+				s.Broadcast(frame)
+			}
+			s.Close()
+		}()
+	}
+
+	return s, nil
+}
+
+// Handler returns an HTTP handler serving the dashboard at / and the
+// WebSocket endpoint at /ws.
+func (s *Server) Handler() http.Handler {
+	static, _ := fs.Sub(staticFiles, "static")
+
+	mux := http.NewServeMux()
+	mux.Handle("/", http.FileServer(http.FS(static)))
+	mux.Handle("/ws", s)
+	mux.HandleFunc("/channels", s.serveChannels)
+
+	return mux
+}
+
+// ServeHTTP upgrades the request to a WebSocket connection and streams
+// frames to it until the client disconnects.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !s.originAllowed(r.Header.Get("Origin"), r.Host) {
+		http.Error(w, "origin not allowed", http.StatusForbidden)
+		return
+	}
+
+	config := clientConfig{}
+	if names := r.URL.Query().Get("channels"); names != "" {
+		config.Channels = strings.Split(names, ",")
+	}
+	if rate := r.URL.Query().Get("rate"); rate != "" {
+		value, err := strconv.Atoi(rate)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid rate %q", rate), http.StatusBadRequest)
+			return
+		}
+		config.Rate = value
+	}
+
+	c := &client{
+		send: make(chan []byte, clientQueueSize),
+	}
+	if err := s.configure(c, config); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	conn, err := upgrade(w, r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	c.conn = conn
+
+	if !s.add(c) {
+		conn.Close()
+		return
+	}
+
+	go s.write(c)
+	s.read(c)
+}
+
+// Broadcast sends a frame to every client that is due an update.
+func (s *Server) Broadcast(frame telemetry.Frame) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for c := range s.clients {
+		if frame.Received.Before(c.next.Add(-min(scheduleJitter, c.interval/4))) {
+			continue
+		}
+		c.next = c.next.Add(c.interval)
+		if c.next.Before(frame.Received) {
+			c.next = frame.Received.Add(c.interval)
+		}
+
+		c.buffer.Reset()
+		if err := c.encoder.Encode(frame); err != nil {
+			continue
+		}
+		message := bytes.TrimSuffix(c.buffer.Bytes(), []byte("\n"))
+
+		select {
+		case c.send <- bytes.Clone(message):
+		default:
+			// slow clients miss updates rather than holding up the others
+		}
+	}
+}
+
+// Clients returns the number of connected clients.
+func (s *Server) Clients() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.clients)
+}
+
+// Close disconnects all clients and rejects new connections.
+func (s *Server) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	for c := range s.clients {
+		s.remove(c)
+	}
+}
+
+func (s *Server) add(c *client) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return false
+	}
+	s.clients[c] = struct{}{}
+
+	return true
+}
+
+// remove must be called with the lock held.
+func (s *Server) remove(c *client) {
+	if _, ok := s.clients[c]; !ok {
+		return
+	}
+	delete(s.clients, c)
+	close(c.send)
+}
+
+func (s *Server) disconnect(c *client) {
+	s.mu.Lock()
+	s.remove(c)
+	s.mu.Unlock()
+}
+
+func (s *Server) write(c *client) {
+	defer c.conn.Close()
+
+	for message := range c.send {
+		if err := c.conn.WriteText(message, clientWriteLimit); err != nil {
+			s.disconnect(c)
+			break
+		}
+	}
+}
+
+func (s *Server) read(c *client) {
+	defer s.disconnect(c)
+
+	for {
+		message, err := c.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		config := clientConfig{}
+		if err := json.Unmarshal(message, &config); err != nil {
+			s.sendError(c, fmt.Errorf("invalid client message: %w", err))
+			continue
+		}
+
+		s.mu.Lock()
+		err = s.configure(c, config)
+		s.mu.Unlock()
+		if err != nil {
+			s.sendError(c, err)
+		}
+	}
+}
+
+func (s *Server) sendError(c *client, err error) {
+	message, _ := json.Marshal(map[string]string{"error": err.Error()})
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.clients[c]; !ok {
+		return
+	}
+	select {
+	case c.send <- message:
+	default:
+	}
+}
+
+// configure applies a channel and rate selection to a client, settings that
+// are not given keep their current value.
+func (s *Server) configure(c *client, config clientConfig) error {
+	if config.Rate < 0 {
+		return fmt.Errorf("invalid rate %d", config.Rate)
+	}
+
+	if len(config.Channels) > 0 || c.encoder == nil {
+		channels := s.channels
+		if len(config.Channels) > 0 {
+			var err error
+			channels, err = telemetry.LookupChannels(s.opts.Units, config.Channels)
+			if err != nil {
+				return err
+			}
+		}
+		c.encoder = exporter.NewJSONLEncoder(&c.buffer, channels)
+	}
+
+	if config.Rate > 0 || c.interval == 0 {
+		rate := config.Rate
+		if rate == 0 {
+			rate = s.opts.Rate
+		}
+		if rate > s.opts.MaxRate {
+			rate = s.opts.MaxRate
+		}
+		c.interval = time.Second / time.Duration(rate)
+		c.next = time.Time{}
+	}
+
+	return nil
+}
+
+// originAllowed accepts clients that are not browsers, which send no origin,
+// and pages served from the same host as the server.
+func (s *Server) originAllowed(origin string, host string) bool {
+	if s.opts.AllowAnyOrigin || origin == "" {
+		return true
+	}
+
+	if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, host) {
+		return true
+	}
+
+	for _, allowed := range s.opts.AllowedOrigins {
+		if strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Server) serveChannels(w http.ResponseWriter, _ *http.Request) {
+	type channel struct {
+		Name string `json:"name"`
+		Unit string `json:"unit"`
+	}
+
+	channels := []channel{}
+	for _, c := range telemetry.Channels(s.opts.Units) {
+		channels = append(channels, channel{Name: c.Name, Unit: c.Unit})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(channels)
+}
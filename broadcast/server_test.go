@@ -0,0 +1,297 @@
+package broadcast
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	telemetry "github.com/vwhitteron/gt-telemetry"
+	"github.com/vwhitteron/gt-telemetry/vehicles"
+)
+
+type ServerTestSuite struct {
+	suite.Suite
+	server *Server
+	http   *httptest.Server
+}
+
+func TestServerTestSuite(t *testing.T) {
+	suite.Run(t, new(ServerTestSuite))
+}
+
+func (suite *ServerTestSuite) SetupTest() {
+	server, err := New(nil, Options{
+		Channels:       []string{"sequence_id", "engine_rpm"},
+		AllowedOrigins: []string{"http://pitwall.local"},
+	})
+	suite.Require().NoError(err)
+
+	suite.server = server
+	suite.http = httptest.NewServer(server.Handler())
+}
+
+func (suite *ServerTestSuite) TearDownTest() {
+	suite.server.Close()
+	suite.http.Close()
+}
+
+func newTestFrame(sequenceID uint32, received time.Time) telemetry.Frame {
+	frame := telemetry.NewFrame(telemetry.NewTransformer(&vehicles.Inventory{}), received)
+	frame.RawTelemetry.SequenceId = sequenceID
+	frame.RawTelemetry.EngineRpm = 4500
+
+	return frame
+}
+
+// testClient is a bare bones websocket client for exercising the server.
+type testClient struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+func (suite *ServerTestSuite) connect(query string) *testClient {
+	conn, err := net.Dial("tcp", strings.TrimPrefix(suite.http.URL, "http://"))
+	suite.Require().NoError(err)
+
+	request := "GET /ws" + query + " HTTP/1.1\r\n" +
+		"Host: localhost\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n" +
+		"Sec-WebSocket-Version: 13\r\n\r\n"
+	_, err = conn.Write([]byte(request))
+	suite.Require().NoError(err)
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, nil)
+	suite.Require().NoError(err)
+	suite.Require().Equal(http.StatusSwitchingProtocols, resp.StatusCode)
+	suite.Require().Equal("s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", resp.Header.Get("Sec-WebSocket-Accept"))
+
+	suite.Eventually(func() bool { return suite.server.Clients() > 0 }, time.Second, time.Millisecond)
+
+	return &testClient{conn: conn, reader: reader}
+}
+
+func (c *testClient) read() (map[string]any, error) {
+	_ = c.conn.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(c.reader, header); err != nil {
+		return nil, err
+	}
+	payload := make([]byte, header[1]&0x7f)
+	if header[1]&0x7f == 126 {
+		extended := make([]byte, 2)
+		if _, err := io.ReadFull(c.reader, extended); err != nil {
+			return nil, err
+		}
+		payload = make([]byte, binary.BigEndian.Uint16(extended))
+	}
+	if _, err := io.ReadFull(c.reader, payload); err != nil {
+		return nil, err
+	}
+
+	message := map[string]any{}
+	err := json.Unmarshal(payload, &message)
+
+	return message, err
+}
+
+func (c *testClient) write(message string) error {
+	mask := []byte{1, 2, 3, 4}
+	frame := []byte{0x80 | opText, 0x80 | byte(len(message))}
+	frame = append(frame, mask...)
+	for i := range len(message) {
+		frame = append(frame, message[i]^mask[i%4])
+	}
+	_, err := c.conn.Write(frame)
+
+	return err
+}
+
+func (suite *ServerTestSuite) TestClientsReceiveTheDefaultChannels() {
+	// Arrange
+	client := suite.connect("")
+	defer client.conn.Close()
+
+	// Act
+	suite.server.Broadcast(newTestFrame(7, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)))
+	message, err := client.read()
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(map[string]any{
+		"timestamp":   "2024-01-02T03:04:05Z",
+		"sequence_id": float64(7),
+		"engine_rpm":  float64(4500),
+	}, message)
+}
+
+func (suite *ServerTestSuite) TestClientsSelectChannelsWhenConnecting() {
+	// Arrange
+	client := suite.connect("?channels=engine_rpm")
+	defer client.conn.Close()
+
+	// Act
+	suite.server.Broadcast(newTestFrame(7, time.Now()))
+	message, err := client.read()
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Contains(message, "engine_rpm")
+	suite.NotContains(message, "sequence_id")
+}
+
+func (suite *ServerTestSuite) TestUpdatesAreLimitedToTheClientRate() {
+	// Arrange
+	client := suite.connect("?rate=10")
+	defer client.conn.Close()
+	start := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	// Act
+	for i := range 60 {
+		suite.server.Broadcast(newTestFrame(uint32(i), start.Add(time.Duration(i)*time.Second/60)))
+		time.Sleep(time.Millisecond)
+	}
+
+	received := []float64{}
+	for {
+		message, err := client.read()
+		if err != nil {
+			break
+		}
+		received = append(received, message["sequence_id"].(float64))
+	}
+
+	// Assert
+	suite.Equal([]float64{0, 6, 12, 18, 24, 30, 36, 42, 48, 54}, received)
+}
+
+func (suite *ServerTestSuite) TestRateIsCappedAtTheMaximum() {
+	// Arrange
+	client := suite.connect("?rate=1000")
+	defer client.conn.Close()
+	start := time.Now()
+
+	// Act
+	for i := range 10 {
+		suite.server.Broadcast(newTestFrame(uint32(i), start.Add(time.Duration(i)*time.Second/120)))
+		time.Sleep(time.Millisecond)
+	}
+
+	count := 0
+	for {
+		if _, err := client.read(); err != nil {
+			break
+		}
+		count++
+	}
+
+	// Assert
+	suite.Equal(5, count)
+}
+
+func (suite *ServerTestSuite) TestClientsChangeChannelsWithAMessage() {
+	// Arrange
+	client := suite.connect("")
+	defer client.conn.Close()
+
+	// Act
+	suite.Require().NoError(client.write(`{"channels":["sequence_id"]}`))
+	suite.Eventually(func() bool {
+		suite.server.Broadcast(newTestFrame(3, time.Now()))
+		message, err := client.read()
+		return err == nil && len(message) == 2
+	}, time.Second, 10*time.Millisecond)
+}
+
+func (suite *ServerTestSuite) TestUnknownChannelsAreReportedToTheClient() {
+	// Arrange
+	client := suite.connect("")
+	defer client.conn.Close()
+
+	// Act
+	suite.Require().NoError(client.write(`{"channels":["warp_factor"]}`))
+	message, err := client.read()
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Contains(message["error"], "warp_factor")
+}
+
+func (suite *ServerTestSuite) TestUnknownChannelsAreRejectedWhenConnecting() {
+	// Act
+	resp, err := http.Get(suite.http.URL + "/ws?channels=warp_factor")
+
+	// Assert
+	suite.Require().NoError(err)
+	defer resp.Body.Close()
+	suite.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (suite *ServerTestSuite) TestOriginsOutsideTheAllowListAreRejected() {
+	// Arrange
+	req, err := http.NewRequest(http.MethodGet, suite.http.URL+"/ws", nil)
+	suite.Require().NoError(err)
+	req.Header.Set("Origin", "http://example.com")
+
+	// Act
+	resp, err := http.DefaultClient.Do(req)
+
+	// Assert
+	suite.Require().NoError(err)
+	defer resp.Body.Close()
+	suite.Equal(http.StatusForbidden, resp.StatusCode)
+}
+
+func (suite *ServerTestSuite) TestOriginsAreAllowed() {
+	testCases := map[string]struct {
+		opts   Options
+		origin string
+		want   bool
+	}{
+		"no origin":                 {origin: "", want: true},
+		"same origin":               {origin: "http://localhost:8080", want: true},
+		"other origin by default":   {origin: "http://example.com", want: false},
+		"listed origin":             {opts: Options{AllowedOrigins: []string{"http://pitwall.local"}}, origin: "http://PITWALL.local", want: true},
+		"other origin when listing": {opts: Options{AllowedOrigins: []string{"http://pitwall.local"}}, origin: "http://example.com", want: false},
+		"any origin":                {opts: Options{AllowAnyOrigin: true}, origin: "http://example.com", want: true},
+	}
+
+	for name, tc := range testCases {
+		suite.Run(name, func() {
+			// Arrange
+			server, err := New(nil, tc.opts)
+			suite.Require().NoError(err)
+			defer server.Close()
+
+			// Act
+			allowed := server.originAllowed(tc.origin, "localhost:8080")
+
+			// Assert
+			suite.Equal(tc.want, allowed)
+		})
+	}
+}
+
+func (suite *ServerTestSuite) TestDashboardIsServed() {
+	// Act
+	resp, err := http.Get(suite.http.URL + "/")
+
+	// Assert
+	suite.Require().NoError(err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	suite.Require().NoError(err)
+	suite.Equal(http.StatusOK, resp.StatusCode)
+	suite.Contains(string(body), "<title>GT Telemetry</title>")
+}
@@ -0,0 +1,233 @@
+package telemetry
+
+import (
+	"fmt"
+	"strings"
+)
+
+type UnitSystem int
+
+const (
+	UnitSystemMetric UnitSystem = iota
+	UnitSystemImperial
+)
+
+func ParseUnitSystem(name string) (UnitSystem, error) {
+	switch strings.ToLower(name) {
+	case "", "metric":
+		return UnitSystemMetric, nil
+	case "imperial":
+		return UnitSystemImperial, nil
+	default:
+		return UnitSystemMetric, fmt.Errorf("unknown unit system %q", name)
+	}
+}
+
+func (u UnitSystem) String() string {
+	switch u {
+	case UnitSystemImperial:
+		return "imperial"
+	default:
+		return "metric"
+	}
+}
+
+// Channel describes a single named value that can be sampled from a frame.
+// Values are one of bool, string, float32 or a sized integer type, or nil
+// when the channel does not apply to the vehicle, such as the turbo boost of
+// an electric vehicle.
+type Channel struct {
+	Name  string
+	Unit  string
+	Value func(f Frame) any
+}
+
+// Channels returns every channel exposed by the transformer, using the
+// imperial alternates where the unit system asks for them.
+func Channels(units UnitSystem) []Channel {
+	speedUnit, lengthUnit, tempUnit, boostUnit := units.Speed(), units.Length(), units.Temperature(), units.Pressure()
+	oilPressureUnit := KiloPascals
+	if units == UnitSystemImperial {
+		oilPressureUnit = PoundsPerSquareInch
+	}
+
+	speed := func(v float32) float32 { return Speed(v).In(speedUnit) }
+	length := func(v float32) float32 { return Length(v).In(lengthUnit) }
+	temp := func(v float32) float32 { return Temperature(v).In(tempUnit) }
+
+	channels := []Channel{
+		{"sequence_id", "", func(f Frame) any { return f.SequenceID() }},
+		{"time_of_day", "ms", func(f Frame) any { return f.TimeOfDay().Milliseconds() }},
+		{"current_lap", "", func(f Frame) any { return f.CurrentLap() }},
+		{"race_laps", "", func(f Frame) any { return f.RaceLaps() }},
+		{"best_laptime", "ms", func(f Frame) any { return f.BestLaptime().Milliseconds() }},
+		{"last_laptime", "ms", func(f Frame) any { return f.LastLaptime().Milliseconds() }},
+		{"starting_position", "", func(f Frame) any { return f.StartingPosition() }},
+		{"race_entrants", "", func(f Frame) any { return f.RaceEntrants() }},
+		{"vehicle_id", "", func(f Frame) any { return f.VehicleID() }},
+		{"vehicle_manufacturer", "", func(f Frame) any { return f.VehicleManufacturer() }},
+		{"vehicle_model", "", func(f Frame) any { return f.VehicleModel() }},
+		{"throttle", "%", func(f Frame) any { return f.ThrottlePercent() }},
+		{"brake", "%", func(f Frame) any { return f.BrakePercent() }},
+		{"clutch_actuation", "%", func(f Frame) any { return f.ClutchActuationPercent() }},
+		{"clutch_engagement", "%", func(f Frame) any { return f.ClutchEngagementPercent() }},
+		{"clutch_output_rpm", "rpm", func(f Frame) any { return f.ClutchOutputRPM() }},
+		{"current_gear", "", func(f Frame) any { return f.CurrentGear() }},
+		{"suggested_gear", "", func(f Frame) any { return f.SuggestedGear() }},
+		{"engine_rpm", "rpm", func(f Frame) any { return f.EngineRPM() }},
+		{"rev_light_rpm_min", "rpm", func(f Frame) any { return f.EngineRPMLight().Min }},
+		{"rev_light_rpm_max", "rpm", func(f Frame) any { return f.EngineRPMLight().Max }},
+		{"ground_speed", speedUnit.String(), func(f Frame) any { return f.GroundSpeed().In(speedUnit) }},
+		{"fuel_level", "%", combustion(func(f Frame) any { return f.FuelLevelPercent() })},
+		{"fuel_capacity", "%", combustion(func(f Frame) any { return f.FuelCapacityPercent() })},
+		{"fuel_volume", units.Volume().String(), optional(func(f Frame) (float32, bool) {
+			fuel, ok := f.FuelLevel()
+			return fuel.In(units.Volume()), ok
+		})},
+		{"turbo_boost", boostUnit.String(), combustion(func(f Frame) any { return f.TurboBoost().In(boostUnit) })},
+		{"oil_pressure", oilPressureUnit.String(), combustion(func(f Frame) any { return f.OilPressure().In(oilPressureUnit) })},
+		{"oil_temperature", tempUnit.String(), combustion(func(f Frame) any { return f.OilTemperature().In(tempUnit) })},
+		{"water_temperature", tempUnit.String(), combustion(func(f Frame) any { return f.WaterTemperature().In(tempUnit) })},
+		{"battery_charge", "%", optional(func(f Frame) (float32, bool) { return f.BatteryChargePercent() })},
+		{"energy_recovery", "", optional(func(f Frame) (float32, bool) { return f.EnergyRecovery() })},
+		{"ride_height", lengthUnit.String(), func(f Frame) any { return f.RideHeight().In(lengthUnit) }},
+		{"heading", "", func(f Frame) any { return f.Heading() }},
+		{"transmission_top_speed_ratio", "", func(f Frame) any { return f.TransmissionTopSpeedRatio() }},
+		{"transmission_gears", "", func(f Frame) any { return f.Transmission().Gears }},
+		{"differential_ratio", "", func(f Frame) any { return f.DifferentialRatio() }},
+		{"vmax_speed", speedUnit.String(), func(f Frame) any { return f.VmaxSpeed().In(speedUnit) }},
+		{"vmax_rpm", "rpm", func(f Frame) any { return f.CalculatedVmax().RPM }},
+		{"front_torque_split", "%", func(f Frame) any { return f.Drivetrain().FrontTorqueSplit * 100 }},
+		{"driven_wheel_slip_ratio", "", func(f Frame) any { return f.DrivenWheelSlipRatio() }},
+	}
+
+	for gear := 1; gear <= 8; gear++ {
+		index := gear - 1
+		channels = append(channels, Channel{
+			Name: fmt.Sprintf("gear_ratio_%d", gear),
+			Value: func(f Frame) any {
+				ratios := f.Transmission().GearRatios
+				if index >= len(ratios) {
+					return float32(0)
+				}
+				return ratios[index]
+			},
+		})
+	}
+
+	channels = append(channels, vectorChannels("position", "m", func(f Frame) Vector { return f.PositionalMapCoordinates() })...)
+	channels = append(channels, vectorChannels("velocity", "m/s", func(f Frame) Vector { return f.VelocityVector() })...)
+	channels = append(channels, vectorChannels("angular_velocity", "rad/s", func(f Frame) Vector { return f.AngularVelocityVector() })...)
+	channels = append(channels,
+		Channel{"rotation_pitch", "", func(f Frame) any { return f.RotationVector().Pitch }},
+		Channel{"rotation_yaw", "", func(f Frame) any { return f.RotationVector().Yaw }},
+		Channel{"rotation_roll", "", func(f Frame) any { return f.RotationVector().Roll }},
+	)
+
+	channels = append(channels, cornerChannels("tyre_temperature", tempUnit.String(), temp, func(f Frame) CornerSet { return f.TyreTemperatureCelsius() })...)
+	channels = append(channels, cornerChannels("tyre_radius", lengthUnit.String(), length, func(f Frame) CornerSet { return f.TyreRadiusMeters() })...)
+	channels = append(channels, cornerChannels("suspension_height", lengthUnit.String(), length, func(f Frame) CornerSet { return f.SuspensionHeightMeters() })...)
+	channels = append(channels, cornerChannels("suspension_velocity", lengthUnit.String()+"/s", length, func(f Frame) CornerSet { return f.SuspensionVelocityMetersPerSecond() })...)
+	channels = append(channels, cornerFlagChannels("suspension_bottoming", func(f Frame) Corners[bool] { return f.SuspensionBottomingOut() })...)
+	channels = append(channels, cornerFlagChannels("wheel_off_ground", func(f Frame) Corners[bool] { return f.WheelsOffGround() })...)
+	channels = append(channels,
+		Channel{"body_roll", "deg", func(f Frame) any { return f.BodyRollDegrees() }},
+		Channel{"body_pitch", "deg", func(f Frame) any { return f.BodyPitchDegrees() }},
+	)
+	channels = append(channels, cornerChannels("wheel_speed", speedUnit.String(), speed, func(f Frame) CornerSet { return f.WheelSpeedMetersPerSecond() })...)
+	channels = append(channels, cornerChannels("wheel_rpm", "rpm", nil, func(f Frame) CornerSet { return f.WheelSpeedRPM() })...)
+	channels = append(channels, cornerChannels("tyre_slip_ratio", "", nil, func(f Frame) CornerSet { return f.TyreSlipRatio() })...)
+
+	channels = append(channels,
+		Channel{"flag_live", "", func(f Frame) any { return f.Flags().Live }},
+		Channel{"flag_game_paused", "", func(f Frame) any { return f.Flags().GamePaused }},
+		Channel{"flag_loading", "", func(f Frame) any { return f.Flags().Loading }},
+		Channel{"flag_in_gear", "", func(f Frame) any { return f.Flags().InGear }},
+		Channel{"flag_has_turbo", "", func(f Frame) any { return f.Flags().HasTurbo }},
+		Channel{"flag_rev_limiter_alert", "", func(f Frame) any { return f.Flags().RevLimiterAlert }},
+		Channel{"flag_handbrake_active", "", func(f Frame) any { return f.Flags().HandbrakeActive }},
+		Channel{"flag_headlights_active", "", func(f Frame) any { return f.Flags().HeadlightsActive }},
+		Channel{"flag_high_beam_active", "", func(f Frame) any { return f.Flags().HighBeamActive }},
+		Channel{"flag_low_beam_active", "", func(f Frame) any { return f.Flags().LowBeamActive }},
+		Channel{"flag_asm_active", "", func(f Frame) any { return f.Flags().ASMActive }},
+		Channel{"flag_tcs_active", "", func(f Frame) any { return f.Flags().TCSActive }},
+	)
+
+	return channels
+}
+
+// LookupChannels returns the named channels in the order requested. All
+// channels are returned when no names are given.
+func LookupChannels(units UnitSystem, names []string) ([]Channel, error) {
+	all := Channels(units)
+	if len(names) == 0 {
+		return all, nil
+	}
+
+	index := make(map[string]Channel, len(all))
+	for _, channel := range all {
+		index[channel.Name] = channel
+	}
+
+	selected := make([]Channel, 0, len(names))
+	for _, name := range names {
+		channel, ok := index[strings.TrimSpace(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown channel %q", name)
+		}
+		selected = append(selected, channel)
+	}
+
+	return selected, nil
+}
+
+// combustion reports a channel as not applicable to electric vehicles.
+func combustion(value func(f Frame) any) func(f Frame) any {
+	return func(f Frame) any {
+		if !f.HasCombustionEngine() {
+			return nil
+		}
+		return value(f)
+	}
+}
+
+// optional reports a channel as not applicable when its value is not ok.
+func optional(value func(f Frame) (float32, bool)) func(f Frame) any {
+	return func(f Frame) any {
+		v, ok := value(f)
+		if !ok {
+			return nil
+		}
+		return v
+	}
+}
+
+func cornerChannels(name string, unit string, convert func(float32) float32, value func(f Frame) CornerSet) []Channel {
+	if convert == nil {
+		convert = func(v float32) float32 { return v }
+	}
+
+	return []Channel{
+		{name + "_fl", unit, func(f Frame) any { return convert(value(f).FrontLeft) }},
+		{name + "_fr", unit, func(f Frame) any { return convert(value(f).FrontRight) }},
+		{name + "_rl", unit, func(f Frame) any { return convert(value(f).RearLeft) }},
+		{name + "_rr", unit, func(f Frame) any { return convert(value(f).RearRight) }},
+	}
+}
+
+func cornerFlagChannels(name string, value func(f Frame) Corners[bool]) []Channel {
+	return []Channel{
+		{name + "_fl", "", func(f Frame) any { return value(f).FrontLeft }},
+		{name + "_fr", "", func(f Frame) any { return value(f).FrontRight }},
+		{name + "_rl", "", func(f Frame) any { return value(f).RearLeft }},
+		{name + "_rr", "", func(f Frame) any { return value(f).RearRight }},
+	}
+}
+
+func vectorChannels(name string, unit string, value func(f Frame) Vector) []Channel {
+	return []Channel{
+		{name + "_x", unit, func(f Frame) any { return value(f).X }},
+		{name + "_y", unit, func(f Frame) any { return value(f).Y }},
+		{name + "_z", unit, func(f Frame) any { return value(f).Z }},
+	}
+}
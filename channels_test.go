@@ -0,0 +1,188 @@
+package telemetry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/vwhitteron/gt-telemetry/internal/gttelemetry"
+	"github.com/vwhitteron/gt-telemetry/vehicles"
+)
+
+type ChannelsTestSuite struct {
+	suite.Suite
+	frame Frame
+}
+
+func TestChannelsTestSuite(t *testing.T) {
+	suite.Run(t, new(ChannelsTestSuite))
+}
+
+func (suite *ChannelsTestSuite) SetupTest() {
+	transformer := NewTransformer(&vehicles.Inventory{})
+	transformer.RawTelemetry = gttelemetry.GranTurismoTelemetry{}
+
+	suite.frame = NewFrame(transformer, time.Unix(0, 0))
+}
+
+func (suite *ChannelsTestSuite) TestParseUnitSystemAcceptsKnownNames() {
+	testCases := map[string]UnitSystem{
+		"":         UnitSystemMetric,
+		"metric":   UnitSystemMetric,
+		"Imperial": UnitSystemImperial,
+	}
+
+	for name, wantValue := range testCases {
+		suite.Run(name, func() {
+			// Act
+			gotValue, err := ParseUnitSystem(name)
+
+			// Assert
+			suite.NoError(err)
+			suite.Equal(wantValue, gotValue)
+		})
+	}
+}
+
+func (suite *ChannelsTestSuite) TestParseUnitSystemRejectsUnknownName() {
+	// Act
+	_, err := ParseUnitSystem("furlongs")
+
+	// Assert
+	suite.ErrorContains(err, `unknown unit system "furlongs"`)
+}
+
+func (suite *ChannelsTestSuite) TestChannelNamesAreUnique() {
+	// Arrange
+	seen := map[string]bool{}
+
+	// Act
+	channels := Channels(UnitSystemMetric)
+
+	// Assert
+	for _, channel := range channels {
+		suite.False(seen[channel.Name], "duplicate channel %q", channel.Name)
+		seen[channel.Name] = true
+	}
+}
+
+func (suite *ChannelsTestSuite) TestAllChannelsReturnAValueFromAnEmptyFrame() {
+	// channels that only apply to electric and hybrid vehicles, or need the
+	// size of the fuel tank from the inventory
+	notApplicable := map[string]bool{"battery_charge": true, "energy_recovery": true, "fuel_volume": true}
+
+	for _, units := range []UnitSystem{UnitSystemMetric, UnitSystemImperial} {
+		for _, channel := range Channels(units) {
+			suite.Run(units.String()+"/"+channel.Name, func() {
+				// Act
+				gotValue := channel.Value(suite.frame)
+
+				// Assert
+				if notApplicable[channel.Name] {
+					suite.Nil(gotValue)
+					return
+				}
+				suite.NotNil(gotValue)
+			})
+		}
+	}
+}
+
+func (suite *ChannelsTestSuite) TestPowertrainChannelsFollowTheVehicle() {
+	testCases := map[string]struct {
+		vehicleID uint32
+		want      []any
+	}{
+		"combustion": {vehicleID: 82, want: []any{float32(40), float32(0.5), nil, nil}},
+		"electric":   {vehicleID: 3390, want: []any{nil, nil, float32(40), float32(12.5)}},
+		"hybrid":     {vehicleID: 3459, want: []any{float32(40), float32(0.5), nil, float32(12.5)}},
+	}
+	names := []string{"fuel_level", "turbo_boost", "battery_charge", "energy_recovery"}
+
+	for name, tc := range testCases {
+		suite.Run(name, func() {
+			// Arrange
+			inventory, err := vehicles.NewInventory()
+			suite.Require().NoError(err)
+			transformer := NewTransformer(inventory)
+			transformer.RawTelemetry = newRawTelemetry()
+			transformer.RawTelemetry.VehicleId = tc.vehicleID
+			transformer.RawTelemetry.FuelLevel = 40
+			transformer.RawTelemetry.FuelCapacity = 100
+			transformer.RawTelemetry.ManifoldPressure = 1.5
+			transformer.RawTelemetry.Motion = &gttelemetry.GranTurismoTelemetry_Motion{}
+			transformer.RawTelemetry.Energy = &gttelemetry.GranTurismoTelemetry_Energy{EnergyRecovery: 12.5}
+			channels, err := LookupChannels(UnitSystemMetric, names)
+			suite.Require().NoError(err)
+
+			// Act
+			values := NewFrame(transformer, time.Unix(0, 0)).Values(channels)
+
+			// Assert
+			suite.Equal(tc.want, values)
+		})
+	}
+}
+
+func (suite *ChannelsTestSuite) TestImperialChannelsAreConverted() {
+	// Arrange
+	suite.frame.RawTelemetry.GroundSpeed = 10
+	suite.frame.RawTelemetry.WaterTemperature = 100
+	metric, _ := LookupChannels(UnitSystemMetric, []string{"ground_speed", "water_temperature"})
+	imperial, _ := LookupChannels(UnitSystemImperial, []string{"ground_speed", "water_temperature"})
+
+	// Act
+	metricValues := suite.frame.Values(metric)
+	imperialValues := suite.frame.Values(imperial)
+
+	// Assert
+	suite.Equal([]any{float32(36), float32(100)}, metricValues)
+	suite.Equal([]any{float32(22.369363), float32(212)}, imperialValues)
+	suite.Equal("mph", imperial[0].Unit)
+	suite.Equal("F", imperial[1].Unit)
+}
+
+func (suite *ChannelsTestSuite) TestSuspensionVelocityFollowsTheUnitSystem() {
+	// Act
+	metric, _ := LookupChannels(UnitSystemMetric, []string{"suspension_height_fl", "suspension_velocity_fl"})
+	imperial, _ := LookupChannels(UnitSystemImperial, []string{"suspension_height_fl", "suspension_velocity_fl"})
+
+	// Assert
+	suite.Equal("mm", metric[0].Unit)
+	suite.Equal("mm/s", metric[1].Unit)
+	suite.Equal("in", imperial[0].Unit)
+	suite.Equal("in/s", imperial[1].Unit)
+}
+
+func (suite *ChannelsTestSuite) TestLookupChannelsReturnsChannelsInRequestedOrder() {
+	// Act
+	channels, err := LookupChannels(UnitSystemMetric, []string{"engine_rpm", "sequence_id"})
+
+	// Assert
+	suite.NoError(err)
+	suite.Len(channels, 2)
+	suite.Equal("engine_rpm", channels[0].Name)
+	suite.Equal("sequence_id", channels[1].Name)
+}
+
+func (suite *ChannelsTestSuite) TestLookupChannelsWithUnknownNameReturnsError() {
+	// Act
+	channels, err := LookupChannels(UnitSystemMetric, []string{"engine_rpm", "warp_factor"})
+
+	// Assert
+	suite.Nil(channels)
+	suite.ErrorContains(err, `unknown channel "warp_factor"`)
+}
+
+func (suite *ChannelsTestSuite) TestFrameIsIndependentOfLaterTelemetry() {
+	// Arrange
+	transformer := NewTransformer(&vehicles.Inventory{})
+	transformer.RawTelemetry.SequenceId = 1
+	frame := NewFrame(transformer, time.Now())
+
+	// Act
+	transformer.RawTelemetry.SequenceId = 2
+
+	// Assert
+	suite.Equal(uint32(1), frame.SequenceID())
+}
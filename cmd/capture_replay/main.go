@@ -46,7 +46,11 @@ func main() {
 		os.Exit(1)
 	}
 
-	go gt.Run()
+	go func() {
+		if err := gt.Run(); err != nil {
+			log.Fatal(err)
+		}
+	}()
 
 	fmt.Println("Waiting for replay to start")
 
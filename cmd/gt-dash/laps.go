@@ -0,0 +1,133 @@
+package main
+
+import (
+	"math"
+	"time"
+
+	telemetry_client "github.com/vwhitteron/gt-telemetry"
+)
+
+const (
+	// packets are sent by the console at a fixed rate so lap times are
+	// measured in packets, which also works for replays read at any speed
+	packetRate    = 60
+	traceInterval = 100 * time.Millisecond
+	traceSearch   = 50
+	gravity       = 9.80665
+)
+
+type tracePoint struct {
+	x       float32
+	z       float32
+	elapsed time.Duration
+}
+
+// lapTimer follows the current lap to estimate the lap time, the fuel used
+// per lap and the delta to the best lap driven while the dashboard is open.
+type lapTimer struct {
+	lap      int16
+	startSeq uint32
+	// fuel is tracked as a percentage of the tank as the size of the tank
+	// is not known for every vehicle
+	startFuel  float32
+	fuelPerLap float32
+	trace      []tracePoint
+	reference  []tracePoint
+	refIndex   int
+}
+
+func (l *lapTimer) update(f telemetry_client.Frame) {
+	lap := f.CurrentLap()
+	if lap != l.lap {
+		if lap == l.lap+1 && l.lap > 0 {
+			l.completeLap(f)
+		}
+		if lap < l.lap {
+			l.reference = nil
+			l.fuelPerLap = 0
+		}
+
+		l.lap = lap
+		l.startSeq = f.SequenceID()
+		l.startFuel = f.FuelLevelPercent()
+		l.trace = nil
+		l.refIndex = 0
+	}
+
+	if lap <= 0 {
+		return
+	}
+
+	elapsed := l.elapsed(f)
+	if len(l.trace) > 0 && elapsed-l.trace[len(l.trace)-1].elapsed < traceInterval {
+		return
+	}
+
+	position := f.PositionalMapCoordinates()
+	l.trace = append(l.trace, tracePoint{x: position.X, z: position.Z, elapsed: elapsed})
+}
+
+func (l *lapTimer) completeLap(f telemetry_client.Frame) {
+	if used := l.startFuel - f.FuelLevelPercent(); used > 0 {
+		l.fuelPerLap = used
+	}
+
+	if f.LastLaptime() > 0 && f.LastLaptime() == f.BestLaptime() {
+		l.reference = l.trace
+	}
+}
+
+// elapsed returns the time since the current lap started.
+func (l *lapTimer) elapsed(f telemetry_client.Frame) time.Duration {
+	if l.lap <= 0 {
+		return 0
+	}
+
+	return time.Duration(f.SequenceID()-l.startSeq) * time.Second / packetRate
+}
+
+// delta compares the current lap with the best lap at the nearest point on
+// track, searching forward from the last match so that the start and finish
+// of the lap are not confused.
+func (l *lapTimer) delta(f telemetry_client.Frame) (time.Duration, bool) {
+	if len(l.reference) == 0 || len(l.trace) == 0 {
+		return 0, false
+	}
+
+	position := f.PositionalMapCoordinates()
+	nearest := math.MaxFloat64
+	end := min(l.refIndex+traceSearch, len(l.reference))
+	for i := l.refIndex; i < end; i++ {
+		dx := float64(l.reference[i].x - position.X)
+		dz := float64(l.reference[i].z - position.Z)
+		if distance := dx*dx + dz*dz; distance < nearest {
+			nearest = distance
+			l.refIndex = i
+		}
+	}
+
+	return l.elapsed(f) - l.reference[l.refIndex].elapsed, true
+}
+
+// gMeter estimates the lateral acceleration from the speed and yaw rate, and
+// the longitudinal acceleration from the change in speed between frames.
+type gMeter struct {
+	seq          uint32
+	speed        float32
+	lateral      float32
+	longitudinal float32
+}
+
+func (g *gMeter) update(f telemetry_client.Frame) {
+	speed := f.GroundSpeedMetersPerSecond()
+	seq := f.SequenceID()
+
+	g.lateral = speed * f.AngularVelocityVector().Y / gravity
+	if seq > g.seq && g.seq != 0 {
+		seconds := float32(seq-g.seq) / packetRate
+		g.longitudinal = (speed - g.speed) / seconds / gravity
+	}
+
+	g.seq = seq
+	g.speed = speed
+}
@@ -0,0 +1,293 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	telemetry_client "github.com/vwhitteron/gt-telemetry"
+)
+
+const defaultSource = "udp://255.255.255.255:33739"
+
+type sourceList []string
+
+func (s *sourceList) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *sourceList) Set(value string) error {
+	*s = append(*s, value)
+
+	return nil
+}
+
+// dashboard holds the client for the selected source and the state kept
+// between frames.
+type dashboard struct {
+	sources []string
+	current int
+	units   telemetry_client.UnitSystem
+	panels  []panel
+	client  *telemetry_client.GTClient
+	events  <-chan telemetry_client.StreamEvent
+	failed  <-chan error
+	stopped bool
+	stalled bool
+	status  string
+	laps    lapTimer
+	gforce  gMeter
+	lastSeq uint32
+}
+
+func main() {
+	var sources sourceList
+	var panelList, units string
+	var refresh int
+
+	flag.Var(&sources, "source", "Telemetry source URL, repeat to switch between several sources. Default: "+defaultSource)
+	flag.StringVar(&panelList, "panels", strings.Join(panelNames(), ","), "Comma separated list of panels to show in order, from "+strings.Join(panelNames(), ", "))
+	flag.StringVar(&units, "units", "metric", "Unit system to show values in, either metric or imperial")
+	flag.IntVar(&refresh, "refresh", 20, "Number of screen updates per second")
+	flag.Parse()
+
+	if len(sources) == 0 {
+		sources = sourceList{defaultSource}
+	}
+
+	unitSystem, err := telemetry_client.ParseUnitSystem(units)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	selected, err := lookupPanels(strings.Split(panelList, ","))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if refresh < 1 {
+		log.Fatal("refresh rate must be at least one update per second")
+	}
+
+	term, err := openTerminal()
+	if err != nil {
+		log.Fatalf("Error opening terminal: %s", err)
+	}
+	defer term.Restore()
+
+	fmt.Print(enterScreen)
+	defer fmt.Print(leaveScreen)
+
+	d := &dashboard{
+		sources: sources,
+		units:   unitSystem,
+		panels:  selected,
+	}
+	d.connect(0)
+	defer d.disconnect()
+
+	keys := make(chan byte)
+	go readKeys(keys)
+
+	width, height := term.Size()
+	ticker := time.NewTicker(time.Second / time.Duration(refresh))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-term.Resized():
+			width, height = term.Size()
+		case key, ok := <-keys:
+			if !ok {
+				keys = nil
+				continue
+			}
+			if !d.handleKey(key) {
+				return
+			}
+		}
+
+		draw(os.Stdout, d.render(width), width, height)
+	}
+}
+
+func readKeys(keys chan<- byte) {
+	buffer := make([]byte, 1)
+	for {
+		if _, err := os.Stdin.Read(buffer); err != nil {
+			close(keys)
+			return
+		}
+		keys <- buffer[0]
+	}
+}
+
+// handleKey acts on a key press and returns false when the dashboard should
+// exit.
+func (d *dashboard) handleKey(key byte) bool {
+	switch key {
+	case 'q', 'Q', 3, 4:
+		return false
+	case 'n', '\t':
+		d.connect((d.current + 1) % len(d.sources))
+	case 'p':
+		d.connect((d.current + len(d.sources) - 1) % len(d.sources))
+	case 'r':
+		d.connect(d.current)
+	case 'u':
+		if d.units == telemetry_client.UnitSystemMetric {
+			d.units = telemetry_client.UnitSystemImperial
+		} else {
+			d.units = telemetry_client.UnitSystemMetric
+		}
+	default:
+		if key >= '1' && key <= '9' && int(key-'1') < len(d.sources) {
+			d.connect(int(key - '1'))
+		}
+	}
+
+	return true
+}
+
+// connect stops the client reading the current source and starts reading
+// the source at the given index.
+func (d *dashboard) connect(index int) {
+	d.status = "waiting for telemetry"
+	d.disconnect()
+
+	d.current = index
+	d.laps = lapTimer{}
+	d.gforce = gMeter{}
+	d.lastSeq = 0
+	d.stopped = false
+	d.stalled = false
+
+	client, err := telemetry_client.NewGTClient(telemetry_client.GTClientOpts{
+		Source:   d.sources[index],
+		LogLevel: "off",
+		Units:    d.units,
+	})
+	if err != nil {
+		d.status = err.Error()
+		return
+	}
+
+	d.client = client
+	d.events = client.Events(8)
+
+	failed := make(chan error, 1)
+	d.failed = failed
+	go func() {
+		failed <- client.Run()
+	}()
+}
+
+func (d *dashboard) disconnect() {
+	if d.client == nil {
+		return
+	}
+
+	if err := d.client.Close(); err != nil {
+		d.status = fmt.Sprintf("failed to close %s: %s", d.sources[d.current], err)
+	}
+	d.client = nil
+	d.events = nil
+	d.failed = nil
+}
+
+func (d *dashboard) render(width int) []string {
+	lines := []string{d.header(width), ""}
+
+	frame, ok := d.update()
+	if !ok {
+		lines = append(lines, "  "+d.status)
+	} else {
+		for _, p := range d.panels {
+			lines = append(lines, p.render(d, frame, width)...)
+			lines = append(lines, "")
+		}
+	}
+
+	return append(lines, colour("grey", "q quit  n/p next/previous source  1-9 select source  r reconnect  u toggle units"))
+}
+
+// update reads the latest frame and stream events, feeding new frames to the
+// lap timer and g-meter.
+func (d *dashboard) update() (telemetry_client.Frame, bool) {
+	if d.client == nil {
+		return telemetry_client.Frame{}, false
+	}
+
+	for pending := true; pending; {
+		select {
+		case event, ok := <-d.events:
+			if !ok {
+				d.events = nil
+				pending = false
+				break
+			}
+			d.stalled = event.Type == telemetry_client.StreamStalled
+		case err := <-d.failed:
+			d.failed = nil
+			d.stopped = true
+			if err != nil {
+				d.status = err.Error()
+			}
+		default:
+			pending = false
+		}
+	}
+
+	frame, ok := d.client.LastFrame()
+	if !ok {
+		return frame, false
+	}
+
+	if seq := frame.SequenceID(); seq != d.lastSeq {
+		d.laps.update(frame)
+		d.gforce.update(frame)
+		d.lastSeq = seq
+	}
+
+	return frame, true
+}
+
+func (d *dashboard) header(width int) string {
+	state := colour("green", "live")
+	switch {
+	case d.client == nil || d.stopped:
+		state = colour("grey", "stopped")
+	case d.stalled:
+		state = colour("red", "stalled")
+	}
+
+	vehicle := ""
+	if frame, ok := d.clientFrame(); ok {
+		vehicle = strings.TrimSpace(frame.VehicleManufacturer() + " " + frame.VehicleModel())
+		if _, known := frame.CurrentVehicle(); !known && frame.VehicleID() != 0 {
+			vehicle = fmt.Sprintf("unknown vehicle %d %s", frame.VehicleID(), frame.VehicleDrivetrain())
+		}
+	}
+
+	return fmt.Sprintf("%s  [%d/%d] %s  %s  %s  %s",
+		colour("bold", "GT Telemetry"),
+		d.current+1,
+		len(d.sources),
+		d.sources[d.current],
+		state,
+		d.units,
+		vehicle,
+	)
+}
+
+func (d *dashboard) clientFrame() (telemetry_client.Frame, bool) {
+	if d.client == nil {
+		return telemetry_client.Frame{}, false
+	}
+
+	return d.client.LastFrame()
+}
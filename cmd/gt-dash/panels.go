@@ -0,0 +1,249 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	telemetry_client "github.com/vwhitteron/gt-telemetry"
+)
+
+// panel renders a section of the dashboard from the latest frame, filling at
+// most the given width.
+type panel struct {
+	name   string
+	render func(d *dashboard, f telemetry_client.Frame, width int) []string
+}
+
+var panels = []panel{
+	{"shift", renderShiftLights},
+	{"inputs", renderInputs},
+	{"tyres", renderTyres},
+	{"laps", renderLaps},
+	{"fuel", renderFuel},
+	{"gforce", renderGForce},
+}
+
+func lookupPanels(names []string) ([]panel, error) {
+	selected := make([]panel, 0, len(names))
+
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		found := false
+		for _, p := range panels {
+			if p.name == name {
+				selected = append(selected, p)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown panel %q", name)
+		}
+	}
+
+	return selected, nil
+}
+
+func panelNames() []string {
+	names := make([]string, len(panels))
+	for i, p := range panels {
+		names[i] = p.name
+	}
+
+	return names
+}
+
+func renderShiftLights(d *dashboard, f telemetry_client.Frame, width int) []string {
+	light := f.EngineRPMLight()
+	rpm := f.EngineRPM()
+
+	fraction := float32(0)
+	if light.Max > light.Min {
+		fraction = (rpm - float32(light.Min)) / float32(light.Max-light.Min)
+	}
+
+	segments := max(width-2, 1)
+	lit := int(math.Round(float64(segments) * math.Max(0, math.Min(1, float64(fraction)))))
+
+	var lights strings.Builder
+	for i := 0; i < segments; i++ {
+		switch {
+		case i >= lit:
+			lights.WriteString(colour("grey", "·"))
+		case f.Flags().RevLimiterAlert:
+			lights.WriteString(colour("blue", "●"))
+		case i < segments/2:
+			lights.WriteString(colour("green", "●"))
+		case i < segments*5/6:
+			lights.WriteString(colour("yellow", "●"))
+		default:
+			lights.WriteString(colour("red", "●"))
+		}
+	}
+
+	return []string{"[" + lights.String() + "]"}
+}
+
+func renderInputs(d *dashboard, f telemetry_client.Frame, width int) []string {
+	barWidth := max(width-18, 1)
+
+	suggested := ""
+	if gear := f.SuggestedGear(); gear != 15 {
+		suggested = fmt.Sprintf(" [%d]", gear)
+	}
+
+	return []string{
+		fmt.Sprintf("Throttle  %s %4.0f%%", bar(barWidth, f.ThrottlePercent()/100, "green"), f.ThrottlePercent()),
+		fmt.Sprintf("Brake     %s %4.0f%%", bar(barWidth, f.BrakePercent()/100, "red"), f.BrakePercent()),
+		fmt.Sprintf("Clutch    %s %4.0f%%", bar(barWidth, f.ClutchActuationPercent()/100, "blue"), f.ClutchActuationPercent()),
+		fmt.Sprintf("Gear      %s%-5s %s  %5.0f rpm",
+			colour("bold", f.CurrentGearString()),
+			suggested,
+			f.GroundSpeed().Format(d.units.Speed(), 0),
+			f.EngineRPM(),
+		),
+	}
+}
+
+func renderTyres(d *dashboard, f telemetry_client.Frame, width int) []string {
+	unit := d.units.Temperature()
+	temperature := f.TyreTemperature()
+
+	tyre := func(label string, t telemetry_client.Temperature) string {
+		text := pad(t.Format(unit, 1), 9)
+
+		switch celsius := t.In(telemetry_client.Celsius); {
+		case celsius < 60:
+			text = colour("blue", text)
+		case celsius > 100:
+			text = colour("red", text)
+		default:
+			text = colour("green", text)
+		}
+
+		return label + " " + text
+	}
+
+	return []string{
+		"Tyres     " + tyre("FL", temperature.FrontLeft) + "   " + tyre("FR", temperature.FrontRight),
+		"          " + tyre("RL", temperature.RearLeft) + "   " + tyre("RR", temperature.RearRight),
+	}
+}
+
+func renderLaps(d *dashboard, f telemetry_client.Frame, width int) []string {
+	lap := fmt.Sprintf("%d", f.CurrentLap())
+	if laps := f.RaceLaps(); laps > 0 {
+		lap += fmt.Sprintf("/%d", laps)
+	}
+
+	delta, ok := d.laps.delta(f)
+
+	return []string{
+		fmt.Sprintf("Lap %-6s Current %s   Delta %s",
+			lap,
+			formatLaptime(d.laps.elapsed(f)),
+			formatDelta(delta, ok),
+		),
+		fmt.Sprintf("           Last    %s   Best  %s",
+			formatLaptime(f.LastLaptime()),
+			formatLaptime(f.BestLaptime()),
+		),
+	}
+}
+
+func renderFuel(d *dashboard, f telemetry_client.Frame, width int) []string {
+	unit := d.units.Volume()
+	barWidth := max(width-18, 1)
+
+	if charge, ok := f.BatteryChargePercent(); ok {
+		return renderBattery(f, charge, barWidth)
+	}
+
+	fill := "green"
+	if f.FuelLevelPercent() < 15 {
+		fill = "red"
+	}
+
+	remaining := "-"
+	tank := f.VehicleFuelTank()
+	if fuel, ok := f.FuelLevel(); ok {
+		remaining = fuel.Format(unit, 1)
+	}
+
+	usage := "Used/lap  -"
+	if d.laps.fuelPerLap > 0 {
+		used := fmt.Sprintf("%.1f%%", d.laps.fuelPerLap)
+		if tank > 0 {
+			used = (tank * telemetry_client.Volume(d.laps.fuelPerLap/100)).Format(unit, 2)
+		}
+		usage = fmt.Sprintf("Used/lap %s   Laps left %4.1f", used, f.FuelLevelPercent()/d.laps.fuelPerLap)
+	}
+
+	return []string{
+		fmt.Sprintf("Fuel      %s %4.0f%%", bar(barWidth, f.FuelLevelPercent()/100, fill), f.FuelLevelPercent()),
+		fmt.Sprintf("          %s   %s", remaining, usage),
+	}
+}
+
+// renderBattery replaces the fuel gauge for electric vehicles.
+func renderBattery(f telemetry_client.Frame, charge float32, barWidth int) []string {
+	fill := "green"
+	if charge < 15 {
+		fill = "red"
+	}
+
+	recovery := "Recovery  -"
+	if value, ok := f.EnergyRecovery(); ok {
+		recovery = fmt.Sprintf("Recovery  %.1f", value)
+	}
+
+	return []string{
+		fmt.Sprintf("Battery   %s %4.0f%%", bar(barWidth, charge/100, fill), charge),
+		recovery,
+	}
+}
+
+// renderGForce plots the acceleration on a grid spanning two g in each
+// direction with the current value marked.
+func renderGForce(d *dashboard, f telemetry_client.Frame, width int) []string {
+	const halfWidth, halfHeight, scale = 10, 2, 2.0
+
+	column := halfWidth + int(math.Round(float64(d.gforce.lateral/scale*halfWidth)))
+	row := halfHeight - int(math.Round(float64(d.gforce.longitudinal/scale*halfHeight)))
+	column = max(0, min(2*halfWidth, column))
+	row = max(0, min(2*halfHeight, row))
+
+	lines := make([]string, 0, 2*halfHeight+1)
+	for y := 0; y <= 2*halfHeight; y++ {
+		var line strings.Builder
+		for x := 0; x <= 2*halfWidth; x++ {
+			switch {
+			case x == column && y == row:
+				line.WriteString(colour("yellow", "●"))
+			case x == halfWidth && y == halfHeight:
+				line.WriteString("┼")
+			case x == halfWidth:
+				line.WriteString(colour("grey", "│"))
+			case y == halfHeight:
+				line.WriteString(colour("grey", "─"))
+			default:
+				line.WriteString(" ")
+			}
+		}
+
+		label := "          "
+		switch y {
+		case 0:
+			label = "G-force   "
+		case halfHeight - 1:
+			line.WriteString(fmt.Sprintf("   Lateral      %+5.2f g", d.gforce.lateral))
+		case halfHeight + 1:
+			line.WriteString(fmt.Sprintf("   Longitudinal %+5.2f g", d.gforce.longitudinal))
+		}
+
+		lines = append(lines, label+line.String())
+	}
+
+	return lines
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	defaultWidth  = 80
+	defaultHeight = 24
+
+	enterScreen = "\033[?1049h\033[?25l"
+	leaveScreen = "\033[?25h\033[?1049l"
+)
+
+var colours = map[string]string{
+	"red":    "\033[31m",
+	"green":  "\033[32m",
+	"yellow": "\033[33m",
+	"blue":   "\033[34m",
+	"cyan":   "\033[36m",
+	"grey":   "\033[90m",
+	"bold":   "\033[1m",
+}
+
+func colour(name string, text string) string {
+	code, ok := colours[name]
+	if !ok {
+		return text
+	}
+
+	return code + text + "\033[0m"
+}
+
+// draw writes the lines to the top of the screen, clipping them to the
+// terminal size. Lines end with a carriage return as the terminal is in raw
+// mode.
+func draw(out io.Writer, lines []string, width int, height int) {
+	var screen strings.Builder
+	screen.WriteString("\033[H")
+
+	for i, line := range lines {
+		if i == height {
+			break
+		}
+		if i > 0 {
+			screen.WriteString("\r\n")
+		}
+		screen.WriteString(clip(line, width))
+		screen.WriteString("\033[K")
+	}
+	screen.WriteString("\033[J")
+
+	io.WriteString(out, screen.String())
+}
+
+// clip shortens a line to the given number of visible characters, escape
+// sequences are kept so that colours are still reset.
+func clip(line string, width int) string {
+	var out strings.Builder
+	visible := 0
+	escape := false
+
+	for _, r := range line {
+		switch {
+		case escape:
+			escape = r != 'm'
+		case r == '\033':
+			escape = true
+		case visible < width:
+			visible++
+		default:
+			continue
+		}
+
+		out.WriteRune(r)
+	}
+
+	return out.String()
+}
+
+// bar renders a horizontal bar filled to the given fraction.
+func bar(width int, fraction float32, fill string) string {
+	if width < 1 {
+		return ""
+	}
+
+	fraction = float32(math.Max(0, math.Min(1, float64(fraction))))
+	filled := int(math.Round(float64(fraction) * float64(width)))
+
+	return colour(fill, strings.Repeat("█", filled)) + colour("grey", strings.Repeat("░", width-filled))
+}
+
+// pad right aligns text to the given number of visible characters.
+func pad(text string, width int) string {
+	if n := utf8.RuneCountInString(text); n < width {
+		return strings.Repeat(" ", width-n) + text
+	}
+
+	return text
+}
+
+func formatLaptime(d time.Duration) string {
+	if d <= 0 {
+		return "--:--.---"
+	}
+
+	return fmt.Sprintf("%d:%02d.%03d", int(d.Minutes()), int(d.Seconds())%60, d.Milliseconds()%1000)
+}
+
+func formatDelta(d time.Duration, ok bool) string {
+	if !ok {
+		return "  -.---"
+	}
+
+	text := fmt.Sprintf("%+7.3f", d.Seconds())
+	if d > 0 {
+		return colour("red", text)
+	}
+
+	return colour("green", text)
+}
@@ -0,0 +1,81 @@
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// terminal puts the controlling terminal into raw mode with stty so that key
+// presses are read without waiting for a new line.
+type terminal struct {
+	state   string
+	resized chan os.Signal
+}
+
+func openTerminal() (*terminal, error) {
+	state, err := stty("-g")
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := stty("raw", "-echo"); err != nil {
+		return nil, err
+	}
+
+	t := &terminal{
+		state:   strings.TrimSpace(state),
+		resized: make(chan os.Signal, 1),
+	}
+	signal.Notify(t.resized, syscall.SIGWINCH)
+
+	return t, nil
+}
+
+// Resized receives a value whenever the terminal window changes size.
+func (t *terminal) Resized() <-chan os.Signal {
+	return t.resized
+}
+
+// Size returns the width and height of the terminal in characters.
+func (t *terminal) Size() (int, int) {
+	out, err := stty("size")
+	if err != nil {
+		return defaultWidth, defaultHeight
+	}
+
+	fields := strings.Fields(out)
+	if len(fields) != 2 {
+		return defaultWidth, defaultHeight
+	}
+
+	height, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return defaultWidth, defaultHeight
+	}
+	width, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return defaultWidth, defaultHeight
+	}
+
+	return width, height
+}
+
+func (t *terminal) Restore() {
+	signal.Stop(t.resized)
+	stty(t.state)
+}
+
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+
+	out, err := cmd.Output()
+
+	return string(out), err
+}
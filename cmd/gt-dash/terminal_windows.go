@@ -0,0 +1,41 @@
+//go:build windows
+
+package main
+
+import (
+	"os"
+	"strconv"
+)
+
+// terminal falls back to line buffered input on Windows, key presses are
+// read once enter is pressed and the size is taken from the environment.
+type terminal struct {
+	resized chan os.Signal
+}
+
+func openTerminal() (*terminal, error) {
+	return &terminal{
+		resized: make(chan os.Signal),
+	}, nil
+}
+
+// Resized never receives as resizing is not reported on Windows.
+func (t *terminal) Resized() <-chan os.Signal {
+	return t.resized
+}
+
+// Size returns the width and height of the terminal in characters.
+func (t *terminal) Size() (int, int) {
+	width, err := strconv.Atoi(os.Getenv("COLUMNS"))
+	if err != nil {
+		width = defaultWidth
+	}
+	height, err := strconv.Atoi(os.Getenv("LINES"))
+	if err != nil {
+		height = defaultHeight
+	}
+
+	return width, height
+}
+
+func (t *terminal) Restore() {}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+
+	telemetry_client "github.com/vwhitteron/gt-telemetry"
+	"github.com/vwhitteron/gt-telemetry/exporter"
+)
+
+func main() {
+	var source, outFile, format, channelList, units string
+	var driver, venue, comment string
+	var influxURL, influxToken, session string
+	var interval int
+	var splitLaps, listChannels bool
+
+	flag.StringVar(&source, "source", "udp://255.255.255.255:33739", "Telemetry source URL, either a PlayStation address, a file:// replay or a pcap:// capture")
+	flag.StringVar(&outFile, "o", "", "Output file name, the format is taken from the extension unless -format is set. Default: stdout")
+	flag.StringVar(&format, "format", "", "Output format, one of csv, jsonl, ld (MoTeC i2) or lp (InfluxDB line protocol)")
+	flag.StringVar(&channelList, "channels", "", "Comma separated list of channels to export. Default: all channels")
+	flag.StringVar(&units, "units", "metric", "Unit system for converted channels, either metric or imperial")
+	flag.IntVar(&interval, "every", 1, "Downsample the output by only writing every Nth packet")
+	flag.BoolVar(&splitLaps, "split-laps", false, "Write each lap to a separate file")
+	flag.BoolVar(&listChannels, "list", false, "List the available channels and exit")
+	flag.StringVar(&driver, "driver", "", "Driver name recorded in MoTeC log headers")
+	flag.StringVar(&venue, "venue", "", "Venue name recorded in MoTeC log headers")
+	flag.StringVar(&comment, "comment", "", "Comment recorded in MoTeC log headers")
+	flag.StringVar(&influxURL, "influx-url", "", "InfluxDB write URL to send points to instead of writing a file")
+	flag.StringVar(&influxToken, "influx-token", "", "InfluxDB API token")
+	flag.StringVar(&session, "session", "", "Session ID tag added to InfluxDB points")
+	flag.Parse()
+
+	unitSystem, err := telemetry_client.ParseUnitSystem(units)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if listChannels {
+		for _, channel := range telemetry_client.Channels(unitSystem) {
+			fmt.Printf("%-32s %s\n", channel.Name, channel.Unit)
+		}
+		return
+	}
+
+	var names []string
+	if channelList != "" {
+		names = strings.Split(channelList, ",")
+	}
+	channels, err := telemetry_client.LookupChannels(unitSystem, names)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	opts := exporter.Options{
+		Channels:  channels,
+		Path:      outFile,
+		Interval:  interval,
+		SplitLaps: splitLaps,
+		Motec: exporter.MotecOptions{
+			Driver:  driver,
+			Venue:   venue,
+			Comment: comment,
+		},
+		Influx: exporter.InfluxOptions{
+			SessionID: session,
+		},
+	}
+	if influxURL != "" {
+		opts.Encoder, err = exporter.NewInfluxHTTPEncoder(channels, opts.Influx, exporter.InfluxHTTPOptions{
+			URL:     influxURL,
+			Token:   influxToken,
+			OnError: func(err error) { log.Printf("%s, retrying with the next batch", err) },
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+	} else if outFile == "" {
+		opts.Output = os.Stdout
+		if format == "" {
+			format = "csv"
+		}
+	}
+	if format != "" {
+		opts.Format, err = exporter.ParseFormat(format)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// Replay files and captures are converted as fast as they can be read
+	sourceURL, err := url.Parse(source)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if (sourceURL.Scheme == "file" || sourceURL.Scheme == "pcap") && !sourceURL.Query().Has("realtime") {
+		query := sourceURL.Query()
+		query.Set("realtime", "false")
+		sourceURL.RawQuery = query.Encode()
+	}
+
+	gt, err := telemetry_client.NewGTClient(telemetry_client.GTClientOpts{
+		Source: sourceURL.String(),
+		Units:  unitSystem,
+	})
+	if err != nil {
+		log.Fatalf("Error creating GT client: %s", err)
+	}
+
+	export, err := exporter.New(opts)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	frames := gt.Subscribe(120)
+	go func() {
+		if err := gt.Run(); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	exported := 0
+	for running := true; running; {
+		select {
+		case <-ctx.Done():
+			running = false
+		case frame, ok := <-frames:
+			if !ok {
+				running = false
+				break
+			}
+
+			if err := export.Write(frame); err != nil {
+				log.Fatal(err)
+			}
+			exported++
+		}
+	}
+
+	if err := export.Close(); err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Fprintf(os.Stderr, "Export complete, total frames: %d\n", exported)
+}
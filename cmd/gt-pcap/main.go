@@ -0,0 +1,100 @@
+package main
+
+import (
+	"compress/gzip"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	telemetry_client "github.com/vwhitteron/gt-telemetry"
+)
+
+func main() {
+	var outFile, portList string
+
+	flag.StringVar(&outFile, "o", "", "Output replay file name, either .gtz or .gtr. Default: the capture name with a .gtz extension")
+	flag.StringVar(&portList, "ports", "33739,33740", "Comma separated list of UDP ports carrying telemetry")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] capture.pcap\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	capture := flag.Arg(0)
+
+	if outFile == "" {
+		outFile = strings.TrimSuffix(capture, filepath.Ext(capture)) + ".gtz"
+	}
+
+	var ports []uint16
+	for _, value := range strings.Split(portList, ",") {
+		port, err := strconv.ParseUint(strings.TrimSpace(value), 10, 16)
+		if err != nil {
+			log.Fatalf("Invalid port %q: %s", value, err)
+		}
+		ports = append(ports, uint16(port))
+	}
+
+	in, err := os.Open(capture)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer in.Close()
+
+	source, err := telemetry_client.NewPcapSource(in, false, ports...)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fh, err := os.Create(outFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer fh.Close()
+
+	var buffer io.Writer
+	fileExt := filepath.Ext(outFile)
+	switch fileExt {
+	case ".gtz":
+		gz, err := gzip.NewWriterLevel(fh, gzip.BestCompression)
+		if err != nil {
+			log.Fatal(err)
+		}
+		gz.Comment = "Gran Turismo 7 Telemetry Replay"
+		defer gz.Close()
+		buffer = gz
+	case ".gtr":
+		buffer = fh
+	default:
+		os.Remove(outFile)
+		log.Fatalf("Unsupported file extension %q, use either .gtr or .gtz", fileExt)
+	}
+
+	converted := 0
+	for {
+		bufLen, packet, err := source.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if _, err := buffer.Write(packet[:bufLen]); err != nil {
+			log.Fatal(err)
+		}
+		converted++
+	}
+
+	fmt.Printf("Converted %d packets to %s\n", converted, outFile)
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/url"
+	"strconv"
+	"time"
+
+	telemetry_client "github.com/vwhitteron/gt-telemetry"
+)
+
+func main() {
+	var console, listen, logLevel string
+	var port int
+	var timeout time.Duration
+	var decrypted bool
+
+	flag.StringVar(&console, "console", "255.255.255.255", "Address of the PlayStation to receive telemetry from")
+	flag.IntVar(&port, "port", 33739, "Port the PlayStation receives heartbeats on")
+	flag.StringVar(&listen, "listen", ":33739", "Address to receive heartbeats from subscribers on")
+	flag.DurationVar(&timeout, "timeout", 30*time.Second, "Time after the last heartbeat that a subscriber is removed")
+	flag.BoolVar(&decrypted, "decrypted", false, "Forward deciphered packets instead of the packets sent by the PlayStation, subscribers then need decrypted=true in their udp:// source")
+	flag.StringVar(&logLevel, "log-level", "info", "Log level, one of trace, debug, info, warn or error")
+	flag.Parse()
+
+	query := url.Values{}
+	query.Set("listen", listen)
+	query.Set("timeout", timeout.String())
+	if decrypted {
+		query.Set("decrypted", "true")
+	}
+	source := url.URL{
+		Scheme:   "relay",
+		Host:     net.JoinHostPort(console, strconv.Itoa(port)),
+		RawQuery: query.Encode(),
+	}
+
+	gt, err := telemetry_client.NewGTClient(telemetry_client.GTClientOpts{
+		Source:   source.String(),
+		LogLevel: logLevel,
+	})
+	if err != nil {
+		log.Fatalf("Error creating GT client: %s", err)
+	}
+
+	fmt.Printf("Relaying telemetry from %s to subscribers on %s\n", console, listen)
+
+	if err := gt.Run(); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"strings"
+
+	telemetry_client "github.com/vwhitteron/gt-telemetry"
+	"github.com/vwhitteron/gt-telemetry/broadcast"
+)
+
+func main() {
+	var source, addr, channelList, units, origins string
+	var rate, maxRate int
+	var anyOrigin bool
+
+	flag.StringVar(&source, "source", "udp://255.255.255.255:33739", "Telemetry source URL, either a PlayStation address or a file:// replay")
+	flag.StringVar(&addr, "addr", ":8080", "Address to serve the dashboard and WebSocket endpoint on")
+	flag.StringVar(&channelList, "channels", "", "Comma separated list of channels sent to clients that do not select their own. Default: all channels")
+	flag.StringVar(&units, "units", "metric", "Unit system for converted channels, either metric or imperial")
+	flag.IntVar(&rate, "rate", 20, "Default number of updates per second sent to each client")
+	flag.IntVar(&maxRate, "max-rate", 60, "Maximum number of updates per second a client can request")
+	flag.StringVar(&origins, "origins", "", "Comma separated list of browser origins allowed to connect besides the dashboard's own")
+	flag.BoolVar(&anyOrigin, "any-origin", false, "Allow pages on any origin to connect, which lets any web page read the telemetry")
+	flag.Parse()
+
+	unitSystem, err := telemetry_client.ParseUnitSystem(units)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	opts := broadcast.Options{
+		Units:          unitSystem,
+		Rate:           rate,
+		MaxRate:        maxRate,
+		AllowAnyOrigin: anyOrigin,
+	}
+	if channelList != "" {
+		opts.Channels = strings.Split(channelList, ",")
+	}
+	if origins != "" {
+		opts.AllowedOrigins = strings.Split(origins, ",")
+	}
+
+	gt, err := telemetry_client.NewGTClient(telemetry_client.GTClientOpts{
+		Source: source,
+		Units:  unitSystem,
+	})
+	if err != nil {
+		log.Fatalf("Error creating GT client: %s", err)
+	}
+
+	server, err := broadcast.New(gt, opts)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	go func() {
+		if err := gt.Run(); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	log.Printf("Serving dashboard on %s", addr)
+	log.Fatal(http.ListenAndServe(addr, server.Handler()))
+}
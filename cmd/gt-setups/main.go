@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/vwhitteron/gt-telemetry/setups"
+)
+
+const usage = `Usage: %s <command> [flags]
+
+Commands:
+  list    list the setups of a vehicle
+  add     record a tuning sheet for a vehicle
+  show    print a setup as JSON
+  laps    list the laps driven with a setup
+
+Run "%s <command> -h" for the flags of a command.
+`
+
+func main() {
+	log.SetFlags(0)
+
+	if len(os.Args) < 2 {
+		fmt.Fprintf(os.Stderr, usage, os.Args[0], os.Args[0])
+		os.Exit(2)
+	}
+
+	command, args := os.Args[1], os.Args[2:]
+	switch command {
+	case "list":
+		list(args)
+	case "add":
+		add(args)
+	case "show":
+		show(args)
+	case "laps":
+		laps(args)
+	default:
+		fmt.Fprintf(os.Stderr, usage, os.Args[0], os.Args[0])
+		os.Exit(2)
+	}
+}
+
+func newFlags(name string, arguments string) (*flag.FlagSet, *string) {
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	storeFile := flags.String("store", "setups.json", "Setup store file")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s %s [flags] %s\n", os.Args[0], name, arguments)
+		flags.PrintDefaults()
+	}
+
+	return flags, storeFile
+}
+
+func openStore(file string) *setups.Store {
+	store, err := setups.Open(file)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return store
+}
+
+func list(args []string) {
+	flags, storeFile := newFlags("list", "")
+	vehicleID := flags.Int("vehicle", 0, "Vehicle ID to list the setups of")
+	flags.Parse(args)
+
+	store := openStore(*storeFile)
+
+	out := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(out, "ID\tNAME\tCREATED\tLAPS\tBEST")
+	for _, setup := range store.Setups(*vehicleID) {
+		laps := store.Laps(setup.ID)
+		best := time.Duration(0)
+		for _, lap := range laps {
+			if best == 0 || lap.Time < best {
+				best = lap.Time
+			}
+		}
+
+		fmt.Fprintf(out, "%s\t%s\t%s\t%d\t%s\n",
+			setup.ID,
+			setup.Name,
+			setup.Created.Format("2006-01-02 15:04"),
+			len(laps),
+			formatLaptime(best),
+		)
+	}
+	out.Flush()
+}
+
+func add(args []string) {
+	flags, storeFile := newFlags("add", "[sheet.json]")
+	vehicleID := flags.Int("vehicle", 0, "Vehicle ID the setup is for")
+	name := flags.String("name", "", "Name of the setup")
+	notes := flags.String("notes", "", "Notes about the setup")
+	flags.Parse(args)
+
+	if *vehicleID == 0 || *name == "" || flags.NArg() > 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	setup := setups.Setup{}
+	if flags.NArg() == 1 {
+		data, err := os.ReadFile(flags.Arg(0))
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&setup); err != nil {
+			log.Fatalf("invalid tuning sheet %s: %s", flags.Arg(0), err)
+		}
+	}
+	setup.VehicleID = *vehicleID
+	setup.Name = *name
+	if *notes != "" {
+		setup.Notes = *notes
+	}
+
+	setup, err := openStore(*storeFile).Add(setup)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(setup.ID)
+}
+
+func show(args []string) {
+	flags, storeFile := newFlags("show", "setup-id")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	setup, err := openStore(*storeFile).Get(flags.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	data, err := json.MarshalIndent(setup, "", "  ")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(string(data))
+}
+
+func laps(args []string) {
+	flags, storeFile := newFlags("laps", "setup-id")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	store := openStore(*storeFile)
+	if _, err := store.Get(flags.Arg(0)); err != nil {
+		log.Fatal(err)
+	}
+
+	out := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(out, "RECORDED\tLAP\tTIME")
+	for _, lap := range store.Laps(flags.Arg(0)) {
+		fmt.Fprintf(out, "%s\t%d\t%s\n", lap.Recorded.Format("2006-01-02 15:04:05"), lap.Lap, formatLaptime(lap.Time))
+	}
+	out.Flush()
+}
+
+func formatLaptime(laptime time.Duration) string {
+	if laptime <= 0 {
+		return "-"
+	}
+
+	return fmt.Sprintf("%d:%06.3f", int(laptime.Minutes()), (laptime % time.Minute).Seconds())
+}
@@ -0,0 +1,287 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	telemetry_client "github.com/vwhitteron/gt-telemetry"
+	"github.com/vwhitteron/gt-telemetry/vehicles"
+)
+
+const usage = `Usage: %s <command> [flags]
+
+Commands:
+  check    check that inventory files load and every key matches its CarID
+  export   write the inventory as CSV or JSON
+  import   merge a CSV of vehicles into an inventory
+  unknown  list vehicle IDs in replay files that are not in the inventory
+
+Run "%s <command> -h" for the flags of a command.
+`
+
+func main() {
+	log.SetFlags(0)
+
+	if len(os.Args) < 2 {
+		fmt.Fprintf(os.Stderr, usage, os.Args[0], os.Args[0])
+		os.Exit(2)
+	}
+
+	command, args := os.Args[1], os.Args[2:]
+	switch command {
+	case "check":
+		check(args)
+	case "export":
+		export(args)
+	case "import":
+		importCSV(args)
+	case "unknown":
+		unknown(args)
+	default:
+		fmt.Fprintf(os.Stderr, usage, os.Args[0], os.Args[0])
+		os.Exit(2)
+	}
+}
+
+type fileList []string
+
+func (f *fileList) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *fileList) Set(value string) error {
+	*f = append(*f, value)
+
+	return nil
+}
+
+func check(args []string) {
+	flags := flag.NewFlagSet("check", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s check inventory.json...\n", os.Args[0])
+	}
+	flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	failed := false
+	for _, file := range flags.Args() {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if err := vehicles.Check(file, data); err != nil {
+			fmt.Println(err)
+			failed = true
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func export(args []string) {
+	var overrides fileList
+	var outFile, format string
+
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	flags.Var(&overrides, "inventory", "Inventory file merged over the embedded inventory, can be repeated")
+	flags.StringVar(&outFile, "o", "", "Output file name, the format is taken from the extension unless -format is set. Default: stdout")
+	flags.StringVar(&format, "format", "", "Output format, either csv or json")
+	flags.Parse(args)
+
+	inventory, err := vehicles.NewInventory(overrides...)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	write(outFile, format, inventory.All())
+}
+
+func importCSV(args []string) {
+	var base, outFile, format string
+	var overwrite bool
+
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	flags.StringVar(&base, "base", "", "Inventory file to merge into. Default: the embedded inventory")
+	flags.StringVar(&outFile, "o", "", "Output file name, the format is taken from the extension unless -format is set. Default: stdout")
+	flags.StringVar(&format, "format", "", "Output format, either csv or json")
+	flags.BoolVar(&overwrite, "overwrite", false, "Replace existing values that conflict with the CSV")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s import [flags] vehicles.csv\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	var current []vehicles.Vehicle
+	if base == "" {
+		inventory, err := vehicles.NewInventory()
+		if err != nil {
+			log.Fatal(err)
+		}
+		current = inventory.All()
+	} else {
+		fh, err := os.Open(base)
+		if err != nil {
+			log.Fatal(err)
+		}
+		current, err = vehicles.ReadJSON(fh)
+		fh.Close()
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	fh, err := os.Open(flags.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	updates, err := vehicles.ReadCSV(fh)
+	fh.Close()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	merged, conflicts, err := vehicles.Merge(current, updates, overwrite)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, conflict := range conflicts {
+		action := "kept"
+		if overwrite {
+			action = "replaced"
+		}
+		fmt.Fprintf(os.Stderr, "conflict: %s, %s\n", conflict, action)
+	}
+
+	write(outFile, format, merged)
+
+	fmt.Fprintf(os.Stderr, "Imported %d rows, %d vehicles added, %d conflicts\n",
+		len(updates),
+		len(merged)-len(current),
+		len(conflicts),
+	)
+}
+
+func unknown(args []string) {
+	var overrides fileList
+
+	flags := flag.NewFlagSet("unknown", flag.ExitOnError)
+	flags.Var(&overrides, "inventory", "Inventory file merged over the embedded inventory, can be repeated")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s unknown [flags] replay.gtz...\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	// errors exit with 2 as 1 reports that unknown vehicles were found
+	fail := func(format string, args ...any) {
+		fmt.Fprintf(os.Stderr, format+"\n", args...)
+		os.Exit(2)
+	}
+
+	for _, replay := range flags.Args() {
+		fh, err := os.Open(replay)
+		if err != nil {
+			fail("Error opening replay: %s", err)
+		}
+		fh.Close()
+	}
+
+	seen := map[uint32]int{}
+	for _, replay := range flags.Args() {
+		gt, err := telemetry_client.NewGTClient(telemetry_client.GTClientOpts{
+			Source:           "file://" + replay + "?realtime=false",
+			LogLevel:         "off",
+			VehicleOverrides: overrides,
+		})
+		if err != nil {
+			fail("Error creating GT client: %s", err)
+		}
+
+		frames := gt.Subscribe(1024)
+		failed := make(chan error, 1)
+		go func() {
+			failed <- gt.Run()
+		}()
+
+		for frame := range frames {
+			id := frame.VehicleID()
+			if _, ok := frame.CurrentVehicle(); !ok && id != 0 {
+				seen[id]++
+			}
+		}
+		if err := <-failed; err != nil {
+			fail("Error reading %s: %s", replay, err)
+		}
+	}
+
+	ids := make([]uint32, 0, len(seen))
+	for id := range seen {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(a, b int) bool {
+		return ids[a] < ids[b]
+	})
+
+	for _, id := range ids {
+		fmt.Printf("%d\t%d frames\n", id, seen[id])
+	}
+	if len(ids) > 0 {
+		os.Exit(1)
+	}
+}
+
+func write(outFile string, format string, list []vehicles.Vehicle) {
+	if format == "" {
+		format = strings.TrimPrefix(filepath.Ext(outFile), ".")
+	}
+	if format == "" {
+		format = "json"
+	}
+
+	var out io.Writer = os.Stdout
+	if outFile != "" {
+		fh, err := os.Create(outFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer fh.Close()
+		out = fh
+	}
+
+	var err error
+	switch format {
+	case "csv":
+		err = vehicles.WriteCSV(out, list)
+	case "json":
+		err = vehicles.WriteJSON(out, list)
+	default:
+		log.Fatalf("unknown output format %q", format)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,112 @@
+package telemetry
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+
+	"github.com/vwhitteron/gt-telemetry/internal/telemetrysrc"
+)
+
+type ConsoleGroupOpts struct {
+	// Consoles are the addresses of the PlayStations to read from.
+	Consoles []string
+	// Port the consoles send telemetry from, 33739 by default.
+	Port int
+	// Client configures the client of every console. The Source and
+	// SourceReader fields are ignored, and the console connection options
+	// configure the socket shared by the consoles.
+	Client GTClientOpts
+}
+
+// ConsoleGroup reads telemetry from several consoles through a single
+// socket so that any number of consoles can be followed from one host. Each
+// console has its own client with independent telemetry and statistics.
+type ConsoleGroup struct {
+	mux      *telemetrysrc.UDPMultiplexer
+	consoles []string
+	clients  map[string]*GTClient
+}
+
+func NewConsoleGroup(opts ConsoleGroupOpts) (*ConsoleGroup, error) {
+	if len(opts.Consoles) == 0 {
+		return nil, fmt.Errorf("no consoles configured")
+	}
+	if opts.Port == 0 {
+		opts.Port = defaultConsolePort
+	}
+
+	log := newLogger(opts.Client)
+
+	mux, err := telemetrysrc.NewUDPMultiplexer(opts.Consoles, opts.Port, telemetrysrc.UDPOptions{
+		BindAddress:       opts.Client.BindAddress,
+		Interface:         opts.Client.Interface,
+		ReceivePort:       opts.Client.ReceivePort,
+		HeartbeatInterval: opts.Client.HeartbeatInterval,
+		StallTimeout:      opts.Client.StallTimeout,
+		MaxBackoff:        opts.Client.MaxBackoff,
+		PacketFormat:      opts.Client.PacketFormat,
+	}, log)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &ConsoleGroup{
+		mux:      mux,
+		consoles: append([]string{}, opts.Consoles...),
+		clients:  map[string]*GTClient{},
+	}
+
+	for _, console := range opts.Consoles {
+		clientOpts := opts.Client
+		clientOpts.Source = "udp://" + net.JoinHostPort(console, strconv.Itoa(opts.Port))
+		clientOpts.SourceReader = mux.Stream(console)
+
+		client, err := NewGTClient(clientOpts)
+		if err != nil {
+			mux.Close()
+			return nil, fmt.Errorf("failed to create client for console %q: %w", console, err)
+		}
+		g.clients[console] = client
+	}
+
+	return g, nil
+}
+
+// Consoles returns the console addresses in the order they were configured.
+func (g *ConsoleGroup) Consoles() []string {
+	return append([]string{}, g.consoles...)
+}
+
+// Client returns the client for a console address, or nil if the console is
+// not part of the group.
+func (g *ConsoleGroup) Client(console string) *GTClient {
+	return g.clients[console]
+}
+
+// Run reads from every console until the group is closed, and returns the
+// errors of the consoles whose client failed to run.
+func (g *ConsoleGroup) Run() error {
+	var wg sync.WaitGroup
+	errs := make([]error, len(g.consoles))
+	for i, console := range g.consoles {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := g.clients[console].Run(); err != nil {
+				errs[i] = fmt.Errorf("console %s: %w", console, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// Close stops reading from the consoles, which ends the subscriptions of
+// every client.
+func (g *ConsoleGroup) Close() error {
+	return g.mux.Close()
+}
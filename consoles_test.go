@@ -0,0 +1,128 @@
+package telemetry
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ConsoleGroupTestSuite struct {
+	suite.Suite
+}
+
+func TestConsoleGroupTestSuite(t *testing.T) {
+	suite.Run(t, new(ConsoleGroupTestSuite))
+}
+
+// freePort returns a console port whose receive port is available.
+func (suite *ConsoleGroupTestSuite) freePort() int {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{})
+	suite.Require().NoError(err)
+	defer conn.Close()
+
+	return conn.LocalAddr().(*net.UDPAddr).Port - 1
+}
+
+func (suite *ConsoleGroupTestSuite) TestEveryConsoleHasItsOwnClient() {
+	// Arrange
+	group, err := NewConsoleGroup(ConsoleGroupOpts{
+		Consoles: []string{"127.0.0.1", "127.0.0.2"},
+		Port:     suite.freePort(),
+		Client:   GTClientOpts{LogLevel: "off", StatsEnabled: true},
+	})
+	suite.Require().NoError(err)
+	defer group.Close()
+
+	// Act
+	first := group.Client("127.0.0.1")
+	second := group.Client("127.0.0.2")
+
+	// Assert
+	suite.Equal([]string{"127.0.0.1", "127.0.0.2"}, group.Consoles())
+	suite.Require().NotNil(first)
+	suite.Require().NotNil(second)
+	suite.NotSame(first.stats, second.stats)
+	suite.Nil(group.Client("127.0.0.3"))
+}
+
+func (suite *ConsoleGroupTestSuite) TestClosingTheGroupFinishesEveryClient() {
+	// Arrange
+	group, err := NewConsoleGroup(ConsoleGroupOpts{
+		Consoles: []string{"127.0.0.1", "127.0.0.2"},
+		Port:     suite.freePort(),
+		Client:   GTClientOpts{LogLevel: "off"},
+	})
+	suite.Require().NoError(err)
+	frames := group.Client("127.0.0.2").Subscribe(1)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- group.Run()
+	}()
+
+	// Act
+	suite.Require().NoError(group.Close())
+
+	// Assert
+	select {
+	case err := <-done:
+		suite.NoError(err)
+	case <-time.After(time.Second):
+		suite.Fail("group did not stop")
+	}
+	_, open := <-frames
+	suite.False(open)
+	suite.True(group.Client("127.0.0.1").Stopped())
+}
+
+func (suite *ConsoleGroupTestSuite) TestConsoleOptionsConfigureTheSharedSocket() {
+	// Arrange
+	console, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	suite.Require().NoError(err)
+	defer console.Close()
+	receivePort := suite.freePort() + 1
+
+	// Act
+	group, err := NewConsoleGroup(ConsoleGroupOpts{
+		Consoles: []string{"127.0.0.1"},
+		Port:     console.LocalAddr().(*net.UDPAddr).Port,
+		Client: GTClientOpts{
+			LogLevel:     "off",
+			BindAddress:  "127.0.0.1",
+			ReceivePort:  receivePort,
+			PacketFormat: "~",
+		},
+	})
+	suite.Require().NoError(err)
+	defer group.Close()
+
+	// Assert
+	buffer := make([]byte, 16)
+	suite.Require().NoError(console.SetReadDeadline(time.Now().Add(time.Second)))
+	n, addr, err := console.ReadFromUDP(buffer)
+	suite.Require().NoError(err)
+	suite.Equal("~", string(buffer[:n]))
+	suite.Equal(receivePort, addr.Port)
+}
+
+func (suite *ConsoleGroupTestSuite) TestUnknownPacketFormatsAreRejected() {
+	// Act
+	_, err := NewConsoleGroup(ConsoleGroupOpts{
+		Consoles: []string{"127.0.0.1"},
+		Port:     suite.freePort(),
+		Client:   GTClientOpts{LogLevel: "off", PacketFormat: "C"},
+	})
+
+	// Assert
+	suite.ErrorContains(err, `unknown packet format "C"`)
+}
+
+func (suite *ConsoleGroupTestSuite) TestConsolesAreRequired() {
+	// Act
+	_, err := NewConsoleGroup(ConsoleGroupOpts{})
+
+	// Assert
+	suite.EqualError(err, "no consoles configured")
+}
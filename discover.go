@@ -0,0 +1,203 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"time"
+
+	"github.com/vwhitteron/gt-telemetry/internal/utils"
+)
+
+const (
+	defaultConsolePort      = 33739
+	defaultDiscoveryTimeout = 5 * time.Second
+	discoveryProbeInterval  = time.Second
+	// Subnets larger than a /22 are only probed around the local address
+	minProbePrefix = 22
+)
+
+var ErrConsoleNotFound = errors.New("no PlayStation found on the local network")
+
+type discoverer struct {
+	conn    *net.UDPConn
+	port    int
+	targets []net.IP
+	// first stops discovery at the first console found
+	first bool
+}
+
+// Discover probes the subnets of every local IPv4 interface with heartbeats
+// and returns the addresses of the consoles that reply with telemetry. It
+// runs until the context is done, so a deadline should be set.
+func Discover(ctx context.Context) ([]string, error) {
+	return discover(ctx, defaultConsolePort, false)
+}
+
+func discover(ctx context.Context, port int, first bool) ([]string, error) {
+	targets, err := localProbeTargets()
+	if err != nil {
+		return nil, err
+	}
+	if len(targets) == 0 {
+		return nil, errors.New("no IPv4 network interfaces available for discovery")
+	}
+
+	// an ephemeral port does not clash with a client already receiving
+	// telemetry, the console replies to the port the heartbeat came from
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup discovery listener: %w", err)
+	}
+	defer conn.Close()
+
+	d := &discoverer{
+		conn:    conn,
+		port:    port,
+		targets: targets,
+		first:   first,
+	}
+
+	return d.run(ctx)
+}
+
+func (d *discoverer) run(ctx context.Context) ([]string, error) {
+	found := map[string]bool{}
+	consoles := []string{}
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			// unblock the read loop
+			_ = d.conn.SetReadDeadline(time.Now())
+		case <-done:
+		}
+	}()
+
+	buffer := make([]byte, 4096)
+	nextProbe := time.Time{}
+	for ctx.Err() == nil {
+		if time.Now().After(nextProbe) {
+			d.probe()
+			nextProbe = time.Now().Add(discoveryProbeInterval)
+		}
+
+		_ = d.conn.SetReadDeadline(nextProbe)
+		if ctx.Err() != nil {
+			break
+		}
+
+		n, addr, err := d.conn.ReadFromUDP(buffer)
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				continue
+			}
+			return consoles, fmt.Errorf("failed to receive discovery replies: %w", err)
+		}
+
+		if _, err := utils.Salsa20Decode(buffer[:n]); err != nil {
+			continue
+		}
+
+		address := addr.IP.String()
+		if found[address] {
+			continue
+		}
+		found[address] = true
+		consoles = append(consoles, address)
+
+		if d.first {
+			break
+		}
+	}
+
+	sort.Strings(consoles)
+
+	return consoles, nil
+}
+
+func (d *discoverer) probe() {
+	for _, target := range d.targets {
+		// failures are expected for unreachable hosts and are ignored
+		_, _ = d.conn.WriteToUDP([]byte("A"), &net.UDPAddr{IP: target, Port: d.port})
+	}
+}
+
+func localProbeTargets() ([]net.IP, error) {
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list network interfaces: %w", err)
+	}
+
+	seen := map[string]bool{}
+	targets := []net.IP{}
+	for _, iface := range interfaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if !ok || ipNet.IP.To4() == nil {
+				continue
+			}
+
+			for _, target := range probeAddresses(ipNet.IP, ipNet.Mask) {
+				if !seen[target.String()] {
+					seen[target.String()] = true
+					targets = append(targets, target)
+				}
+			}
+		}
+	}
+
+	return targets, nil
+}
+
+// probeAddresses returns every host address in the subnet of a local address
+// along with the subnet broadcast address, excluding the local address.
+func probeAddresses(local net.IP, mask net.IPMask) []net.IP {
+	ip := local.To4()
+	if len(mask) == net.IPv6len {
+		mask = mask[12:]
+	}
+	if ip == nil || len(mask) != net.IPv4len {
+		return nil
+	}
+
+	ones, _ := mask.Size()
+	if ones >= 31 {
+		return nil
+	}
+
+	if ones < minProbePrefix {
+		ones = minProbePrefix
+		mask = net.CIDRMask(ones, 32)
+	}
+	hosts := uint32(1) << (32 - ones)
+
+	network := binary.BigEndian.Uint32(ip) & binary.BigEndian.Uint32(mask)
+	self := binary.BigEndian.Uint32(ip)
+
+	targets := make([]net.IP, 0, hosts-1)
+	for host := uint32(1); host < hosts; host++ {
+		addr := network + host
+		if addr == self {
+			continue
+		}
+		targets = append(targets, binary.BigEndian.AppendUint32(nil, addr))
+	}
+
+	return targets
+}
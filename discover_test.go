@@ -0,0 +1,140 @@
+package telemetry
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/vwhitteron/gt-telemetry/internal/utils"
+)
+
+type DiscoverTestSuite struct {
+	suite.Suite
+}
+
+func TestDiscoverTestSuite(t *testing.T) {
+	suite.Run(t, new(DiscoverTestSuite))
+}
+
+// fakeConsole replies to heartbeats with a telemetry packet.
+func (suite *DiscoverTestSuite) fakeConsole(reply []byte) *net.UDPConn {
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	suite.Require().NoError(err)
+
+	go func() {
+		buffer := make([]byte, 16)
+		for {
+			_, addr, err := conn.ReadFromUDP(buffer)
+			if err != nil {
+				return
+			}
+			_, _ = conn.WriteToUDP(reply, addr)
+		}
+	}()
+
+	return conn
+}
+
+func (suite *DiscoverTestSuite) discoverer(console *net.UDPConn, first bool) *discoverer {
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	suite.Require().NoError(err)
+
+	return &discoverer{
+		conn:    conn,
+		port:    console.LocalAddr().(*net.UDPAddr).Port,
+		targets: []net.IP{net.IPv4(127, 0, 0, 1)},
+		first:   first,
+	}
+}
+
+func (suite *DiscoverTestSuite) TestConsolesReplyingWithTelemetryAreFound() {
+	// Arrange
+	raw := newRawTelemetry()
+	packet, err := utils.Salsa20Encode(encodePacket(&raw), 99)
+	suite.Require().NoError(err)
+	console := suite.fakeConsole(packet)
+	defer console.Close()
+	d := suite.discoverer(console, true)
+	defer d.conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	// Act
+	start := time.Now()
+	consoles, err := d.run(ctx)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal([]string{"127.0.0.1"}, consoles)
+	suite.Less(time.Since(start), time.Second)
+}
+
+func (suite *DiscoverTestSuite) TestHostsReplyingWithOtherTrafficAreIgnored() {
+	// Arrange
+	console := suite.fakeConsole([]byte("not telemetry at all, just some other service"))
+	defer console.Close()
+	d := suite.discoverer(console, false)
+	defer d.conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	// Act
+	consoles, err := d.run(ctx)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Empty(consoles)
+}
+
+func (suite *DiscoverTestSuite) TestProbeAddressesCoverTheSubnet() {
+	// Act
+	targets := probeAddresses(net.IPv4(192, 168, 1, 20), net.CIDRMask(24, 32))
+
+	// Assert
+	suite.Len(targets, 254)
+	suite.Equal("192.168.1.1", targets[0].String())
+	suite.Equal("192.168.1.255", targets[len(targets)-1].String())
+	suite.NotContains(targets, net.IP{192, 168, 1, 20})
+}
+
+func (suite *DiscoverTestSuite) TestLargeSubnetsAreProbedAroundTheLocalAddress() {
+	// Act
+	targets := probeAddresses(net.IPv4(10, 20, 30, 40), net.CIDRMask(8, 32))
+
+	// Assert
+	suite.Len(targets, 1022)
+	suite.Equal("10.20.28.1", targets[0].String())
+	suite.Equal("10.20.31.255", targets[len(targets)-1].String())
+}
+
+func (suite *DiscoverTestSuite) TestPointToPointLinksAreNotProbed() {
+	// Act
+	targets := probeAddresses(net.IPv4(10, 0, 0, 1), net.CIDRMask(32, 32))
+
+	// Assert
+	suite.Empty(targets)
+}
+
+func (suite *DiscoverTestSuite) TestDiscoveryRunsAlongsideARunningClient() {
+	// Arrange
+	client, err := net.ListenUDP("udp4", &net.UDPAddr{})
+	suite.Require().NoError(err)
+	defer client.Close()
+	port := client.LocalAddr().(*net.UDPAddr).Port - 1
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	// Act
+	_, err = discover(ctx, port, false)
+
+	// Assert
+	if err != nil {
+		suite.NotContains(err.Error(), "failed to setup discovery listener")
+	}
+}
@@ -0,0 +1,218 @@
+package telemetry
+
+// DrivetrainSource is where the drivetrain of the vehicle was resolved from.
+type DrivetrainSource string
+
+const (
+	DrivetrainFromInventory DrivetrainSource = "inventory"
+	DrivetrainFromProfile   DrivetrainSource = "profile"
+	DrivetrainFromTelemetry DrivetrainSource = "telemetry"
+	// DrivetrainAssumed is rear wheel drive, used until the driven wheels
+	// are known.
+	DrivetrainAssumed DrivetrainSource = "assumed"
+)
+
+// Drivetrain describes the driven wheels of the vehicle.
+type Drivetrain struct {
+	// Layout is the inventory drivetrain code such as FF, MR or 4WD, empty
+	// when it could not be told from the telemetry.
+	Layout    string
+	FrontAxle bool
+	RearAxle  bool
+	// FrontTorqueSplit is the share of the drive going to the front axle
+	// from 0 to 1. For all wheel drive vehicles it is estimated from the
+	// wheel slip under power and is 0.5 until enough has been seen.
+	FrontTorqueSplit float32
+	Source           DrivetrainSource
+}
+
+const (
+	// average wheel slip that tells a driven axle from a free rolling one
+	driveSlipThreshold = 0.02
+	driveMinThrottle   = 50
+	driveMinSpeed      = 5
+	// packets under power needed before the wheel slip estimate is used
+	driveMinSamples = 60
+	// weight of each packet in the wheel slip averages
+	driveSmoothing = 0.02
+)
+
+// driveEstimate tracks the wheel slip of each axle under power. It only holds
+// values so that frames keep the estimate at the time they were taken.
+type driveEstimate struct {
+	vehicleID uint32
+	frontSlip float32
+	rearSlip  float32
+	samples   int
+}
+
+// observeDrive updates the wheel slip estimate from the current packet.
+func (t *transformer) observeDrive() {
+	id := t.RawTelemetry.VehicleId
+	if id != t.drive.vehicleID {
+		t.drive = driveEstimate{vehicleID: id}
+	}
+
+	front, rear, ok := powerSlip(t)
+	if !ok {
+		return
+	}
+
+	if t.drive.samples == 0 {
+		t.drive.frontSlip, t.drive.rearSlip = front, rear
+	} else {
+		t.drive.frontSlip += (front - t.drive.frontSlip) * driveSmoothing
+		t.drive.rearSlip += (rear - t.drive.rearSlip) * driveSmoothing
+	}
+	t.drive.samples++
+}
+
+// Drivetrain resolves the driven wheels from the inventory, then the learned
+// profile of an unknown vehicle, then the wheel slip seen so far, and
+// otherwise assumes rear wheel drive.
+func (t *transformer) Drivetrain() Drivetrain {
+	t.updateVehicle()
+
+	drivetrain := Drivetrain{}
+	switch {
+	case t.vehicleKnown && layoutAxles(t.vehicle.Drivetrain, &drivetrain):
+		drivetrain.Layout = t.vehicle.Drivetrain
+		drivetrain.Source = DrivetrainFromInventory
+	case t.profileKnown && layoutAxles(t.profile.Drivetrain(), &drivetrain):
+		drivetrain.Layout = t.profile.Drivetrain()
+		drivetrain.Source = DrivetrainFromProfile
+	case t.drive.samples >= driveMinSamples && slipAxles(float64(t.drive.frontSlip), float64(t.drive.rearSlip), &drivetrain):
+		drivetrain.Layout = layoutOf(drivetrain)
+		drivetrain.Source = DrivetrainFromTelemetry
+	default:
+		drivetrain.Layout = ""
+		drivetrain.RearAxle = true
+		drivetrain.Source = DrivetrainAssumed
+	}
+
+	switch {
+	case drivetrain.FrontAxle && drivetrain.RearAxle:
+		drivetrain.FrontTorqueSplit = t.drive.frontSplit()
+	case drivetrain.FrontAxle:
+		drivetrain.FrontTorqueSplit = 1
+	}
+
+	return drivetrain
+}
+
+// frontSplit estimates the share of the drive on the front axle from how
+// much each axle slips, a driven tyre slips in proportion to the force it
+// puts down.
+func (d driveEstimate) frontSplit() float32 {
+	if d.samples < driveMinSamples || d.frontSlip <= 0 || d.rearSlip <= 0 {
+		return 0.5
+	}
+
+	return d.frontSlip / (d.frontSlip + d.rearSlip)
+}
+
+// DrivenTyreDiameterMeters is the rolling diameter of the driven wheels,
+// weighted by the torque split for all wheel drive vehicles.
+func (t *transformer) DrivenTyreDiameterMeters() float32 {
+	return t.drivenAverage(t.TyreDiameterMeters())
+}
+
+// DrivenWheelSpeedMetersPerSecond is the speed of the driven wheels, weighted
+// by the torque split for all wheel drive vehicles.
+func (t *transformer) DrivenWheelSpeedMetersPerSecond() float32 {
+	return t.drivenAverage(t.WheelSpeedMetersPerSecond())
+}
+
+// DrivenWheelSlipRatio is the speed of the driven wheels relative to the
+// ground speed, above 1 when they spin and below 1 when they lock.
+func (t *transformer) DrivenWheelSlipRatio() float32 {
+	groundSpeed := t.GroundSpeedMetersPerSecond()
+	if groundSpeed == 0 {
+		return 1
+	}
+
+	return t.DrivenWheelSpeedMetersPerSecond() / groundSpeed
+}
+
+func (t *transformer) drivenAverage(set CornerSet) float32 {
+	split := t.Drivetrain().FrontTorqueSplit
+	front := (set.FrontLeft + set.FrontRight) / 2
+	rear := (set.RearLeft + set.RearRight) / 2
+
+	return front*split + rear*(1-split)
+}
+
+// layoutAxles sets the driven axles of an inventory drivetrain code and
+// reports false for codes that do not say which wheels are driven.
+func layoutAxles(layout string, drivetrain *Drivetrain) bool {
+	switch layout {
+	case "FF":
+		drivetrain.FrontAxle = true
+	case "FR", "MR", "RR":
+		drivetrain.RearAxle = true
+	// LP is the laser propulsion of the Chaparral 2X VGT, which drives no
+	// wheels and is treated as rear wheel drive like an assumed drivetrain
+	case "LP":
+		drivetrain.RearAxle = true
+	case "4WD":
+		drivetrain.FrontAxle = true
+		drivetrain.RearAxle = true
+	default:
+		return false
+	}
+
+	return true
+}
+
+// layoutOf names the driven axles with an inventory drivetrain code, rear
+// wheel drive is reported as FR since the engine position is not known.
+func layoutOf(drivetrain Drivetrain) string {
+	switch {
+	case drivetrain.FrontAxle && drivetrain.RearAxle:
+		return "4WD"
+	case drivetrain.FrontAxle:
+		return "FF"
+	default:
+		return "FR"
+	}
+}
+
+// slipAxles sets the driven axles from the average wheel slip of each axle
+// under power and reports false when the wheels barely slipped.
+func slipAxles(front float64, rear float64, drivetrain *Drivetrain) bool {
+	switch {
+	case rear-front > driveSlipThreshold:
+		drivetrain.RearAxle = true
+	case front-rear > driveSlipThreshold:
+		drivetrain.FrontAxle = true
+	case front > driveSlipThreshold && rear > driveSlipThreshold:
+		drivetrain.FrontAxle = true
+		drivetrain.RearAxle = true
+	default:
+		return false
+	}
+
+	return true
+}
+
+// powerSlip returns how much faster than the ground the wheels of each axle
+// turn while the vehicle accelerates, ok is false when it is not under power.
+func powerSlip(t *transformer) (front float32, rear float32, ok bool) {
+	groundSpeed := t.GroundSpeedMetersPerSecond()
+	gear := t.CurrentGear()
+	flags := t.Flags()
+	underPower := t.ThrottlePercent() >= driveMinThrottle &&
+		t.BrakePercent() == 0 &&
+		flags.InGear && !flags.GamePaused &&
+		gear >= 1 && gear <= 8 &&
+		groundSpeed >= driveMinSpeed
+	if !underPower {
+		return 0, 0, false
+	}
+
+	wheelSpeed := t.WheelSpeedMetersPerSecond()
+	front = (wheelSpeed.FrontLeft+wheelSpeed.FrontRight)/2/groundSpeed - 1
+	rear = (wheelSpeed.RearLeft+wheelSpeed.RearRight)/2/groundSpeed - 1
+
+	return front, rear, true
+}
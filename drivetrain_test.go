@@ -0,0 +1,224 @@
+package telemetry
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/vwhitteron/gt-telemetry/internal/gttelemetry"
+	"github.com/vwhitteron/gt-telemetry/vehicles"
+)
+
+type DrivetrainTestSuite struct {
+	suite.Suite
+	transformer *transformer
+}
+
+func TestDrivetrainTestSuite(t *testing.T) {
+	suite.Run(t, new(DrivetrainTestSuite))
+}
+
+func (suite *DrivetrainTestSuite) SetupTest() {
+	inventory, err := vehicles.NewInventory()
+	suite.Require().NoError(err)
+	profiles, err := vehicles.OpenProfileStore("")
+	suite.Require().NoError(err)
+	suite.Require().NoError(profiles.Put(vehicles.Profile{ID: 99001, DrivenAxle: vehicles.FrontAxle}))
+
+	suite.transformer = NewTransformer(inventory)
+	suite.transformer.profiles = profiles
+	suite.transformer.RawTelemetry = newRawTelemetry()
+}
+
+// drive feeds packets of the vehicle accelerating with the given wheel slip
+// on each axle.
+func (suite *DrivetrainTestSuite) drive(id uint32, frontSlip float32, rearSlip float32, packets int) {
+	const groundSpeed, radius = 20, 0.3
+
+	raw := &suite.transformer.RawTelemetry
+	raw.VehicleId = id
+	raw.GroundSpeed = groundSpeed
+	raw.Throttle = 255
+	raw.Flags.InGear = true
+	raw.TransmissionGear.Current = 2
+	*raw.TyreRadius = gttelemetry.GranTurismoTelemetry_CornerSet{FrontLeft: radius, FrontRight: radius, RearLeft: radius, RearRight: radius}
+
+	front := groundSpeed * (1 + frontSlip) / radius
+	rear := groundSpeed * (1 + rearSlip) / radius
+	*raw.WheelRadiansPerSecond = gttelemetry.GranTurismoTelemetry_CornerSet{FrontLeft: front, FrontRight: front, RearLeft: rear, RearRight: rear}
+
+	for i := 0; i < packets; i++ {
+		suite.transformer.observeDrive()
+	}
+}
+
+func (suite *DrivetrainTestSuite) TestDrivetrainsAreResolved() {
+	testCases := map[string]struct {
+		vehicleID uint32
+		frontSlip float32
+		rearSlip  float32
+		packets   int
+		want      Drivetrain
+	}{
+		"front wheel drive in the inventory": {
+			vehicleID: 37,
+			want:      Drivetrain{Layout: "FF", FrontAxle: true, FrontTorqueSplit: 1, Source: DrivetrainFromInventory},
+		},
+		"mid engine in the inventory": {
+			vehicleID: 116,
+			want:      Drivetrain{Layout: "MR", RearAxle: true, Source: DrivetrainFromInventory},
+		},
+		"all wheel drive in the inventory": {
+			vehicleID: 105,
+			want:      Drivetrain{Layout: "4WD", FrontAxle: true, RearAxle: true, FrontTorqueSplit: 0.5, Source: DrivetrainFromInventory},
+		},
+		"learned profile": {
+			vehicleID: 99001,
+			want:      Drivetrain{Layout: "FF", FrontAxle: true, FrontTorqueSplit: 1, Source: DrivetrainFromProfile},
+		},
+		"unknown vehicle with wheel slip": {
+			vehicleID: 99002,
+			rearSlip:  0.08,
+			packets:   driveMinSamples,
+			want:      Drivetrain{Layout: "FR", RearAxle: true, Source: DrivetrainFromTelemetry},
+		},
+		"unknown vehicle without enough telemetry": {
+			vehicleID: 99002,
+			frontSlip: 0.08,
+			packets:   driveMinSamples - 1,
+			want:      Drivetrain{RearAxle: true, Source: DrivetrainAssumed},
+		},
+	}
+
+	for name, tc := range testCases {
+		suite.Run(name, func() {
+			// Arrange
+			suite.SetupTest()
+			suite.transformer.RawTelemetry.VehicleId = tc.vehicleID
+			suite.drive(tc.vehicleID, tc.frontSlip, tc.rearSlip, tc.packets)
+
+			// Act
+			drivetrain := suite.transformer.Drivetrain()
+
+			// Assert
+			suite.Equal(tc.want, drivetrain)
+		})
+	}
+}
+
+func (suite *DrivetrainTestSuite) TestUnrecognisedInventoryCodesFallBackToTheTelemetry() {
+	// Arrange
+	file := filepath.Join(suite.T().TempDir(), "override.json")
+	suite.Require().NoError(os.WriteFile(file, []byte(`{"3519": {"Drivetrain": "2WD"}}`), 0o644))
+	inventory, err := vehicles.NewInventory(file)
+	suite.Require().NoError(err)
+	suite.transformer.inventory = inventory
+	suite.drive(3519, 0.06, 0.06, driveMinSamples)
+
+	// Act
+	drivetrain := suite.transformer.Drivetrain()
+
+	// Assert
+	suite.Equal(Drivetrain{Layout: "4WD", FrontAxle: true, RearAxle: true, FrontTorqueSplit: 0.5, Source: DrivetrainFromTelemetry}, drivetrain)
+}
+
+func (suite *DrivetrainTestSuite) TestEveryInventoryDrivetrainIsResolved() {
+	inventory, err := vehicles.NewInventory()
+	suite.Require().NoError(err)
+
+	for _, vehicle := range inventory.All() {
+		// Act
+		drivetrain := Drivetrain{}
+		ok := layoutAxles(vehicle.Drivetrain, &drivetrain)
+
+		// Assert
+		suite.True(ok, "vehicle %d %s has drivetrain %q", vehicle.ID, vehicle.Model, vehicle.Drivetrain)
+	}
+}
+
+func (suite *DrivetrainTestSuite) TestAllWheelDriveTorqueSplitIsEstimatedFromWheelSlip() {
+	// Arrange
+	suite.drive(105, 0.03, 0.09, driveMinSamples)
+
+	// Act
+	drivetrain := suite.transformer.Drivetrain()
+
+	// Assert
+	suite.InDelta(0.25, drivetrain.FrontTorqueSplit, 0.001)
+}
+
+func (suite *DrivetrainTestSuite) TestTheEstimateIsResetForANewVehicle() {
+	// Arrange
+	suite.drive(99002, 0.08, 0, driveMinSamples)
+
+	// Act
+	suite.drive(99003, 0, 0, 1)
+
+	// Assert
+	suite.Equal(DrivetrainAssumed, suite.transformer.Drivetrain().Source)
+}
+
+func (suite *DrivetrainTestSuite) TestCalculationsUseTheDrivenWheels() {
+	testCases := map[string]struct {
+		vehicleID    uint32
+		wantDiameter float32
+		wantRPM      uint16
+	}{
+		"front wheel drive": {vehicleID: 37, wantDiameter: 0.6, wantRPM: 6631},
+		"rear wheel drive":  {vehicleID: 116, wantDiameter: 0.7, wantRPM: 5684},
+		"all wheel drive":   {vehicleID: 105, wantDiameter: 0.65, wantRPM: 6121},
+	}
+
+	for name, tc := range testCases {
+		suite.Run(name, func() {
+			// Arrange
+			suite.SetupTest()
+			raw := &suite.transformer.RawTelemetry
+			raw.VehicleId = tc.vehicleID
+			raw.CalculatedMaxSpeed = 300
+			raw.TransmissionTopSpeedRatio = 2.5
+			copy(raw.TransmissionGearRatio.Gear, []float32{3.2, 2.1, 1.5, 1.2, 1.0, 0.8})
+			*raw.TyreRadius = gttelemetry.GranTurismoTelemetry_CornerSet{FrontLeft: 0.3, FrontRight: 0.3, RearLeft: 0.35, RearRight: 0.35}
+
+			// Act
+			diameter := suite.transformer.DrivenTyreDiameterMeters()
+			vmax := suite.transformer.CalculatedVmax()
+			differential := suite.transformer.DifferentialRatio()
+
+			// Assert
+			suite.InDelta(tc.wantDiameter, diameter, 0.0001)
+			suite.Equal(tc.wantRPM, vmax.RPM)
+			suite.InDelta(2.5/0.8, differential, 0.01)
+		})
+	}
+}
+
+func (suite *DrivetrainTestSuite) TestDrivenWheelSlipRatioFollowsTheDrivenAxle() {
+	// Arrange
+	suite.drive(37, 0.1, 0, 1)
+
+	// Act
+	slip := suite.transformer.DrivenWheelSlipRatio()
+	speed := suite.transformer.DrivenWheelSpeedMetersPerSecond()
+
+	// Assert
+	suite.InDelta(1.1, slip, 0.0001)
+	suite.InDelta(22, speed, 0.001)
+}
+
+func (suite *DrivetrainTestSuite) TestDifferentialRatioIsUnknownWithoutTyres() {
+	// Arrange
+	raw := &suite.transformer.RawTelemetry
+	raw.CalculatedMaxSpeed = 300
+	copy(raw.TransmissionGearRatio.Gear, []float32{3.2, 2.1})
+
+	// Act
+	differential := suite.transformer.DifferentialRatio()
+
+	// Assert
+	suite.Equal(float32(-1), differential)
+	suite.False(math.IsNaN(float64(differential)))
+}
@@ -19,13 +19,17 @@ func main() {
 		log.Fatalf("Failed to create GT client: %s", err.Error())
 	}
 
-	go client.Run()
+	go func() {
+		if err := client.Run(); err != nil {
+			log.Fatal(err)
+		}
+	}()
 
 	fmt.Println("Waiting for data...    Press Ctrl+C to exit")
 
 	sequenceID := uint32(0)
 	for {
-		if client.Finished {
+		if client.Stopped() {
 			break
 		}
 
@@ -207,21 +211,31 @@ func main() {
 			renderFlag(client.Telemetry.Flags().Flag15, "15", "red", "grey"),
 			renderFlag(client.Telemetry.Flags().Flag16, "16", "red", "grey"),
 		)
-		if clientConfig.StatsEnabled {
+		if stats := client.Stats(); stats.Enabled {
 			fmt.Println()
 			fmt.Printf("Packets       Total: %9d    Dropped: %9d     Invalid: %9d\n",
-				client.Statistics.PacketsTotal,
-				client.Statistics.PacketsDropped,
-				client.Statistics.PacketsInvalid,
+				stats.PacketsReceived,
+				stats.PacketsDropped,
+				stats.PacketsInvalid,
+			)
+			fmt.Printf("              Duplicate: %5d    Late: %12d     Resets: %10d\n",
+				stats.PacketsDuplicate,
+				stats.PacketsOutOfOrder,
+				stats.SequenceResets,
+			)
+			fmt.Printf("Packet rate   Current: %5.1f/s  Lifetime: %6.1f/s    Maximum: %7.1f/s\n",
+				stats.PacketRate,
+				stats.PacketRateLifetime,
+				stats.PacketRateMax,
 			)
-			fmt.Printf("Packet rate   Current: %7d/s  Average: %9d/s   Maximum: %9d/s\n",
-				client.Statistics.PacketRateCurrent,
-				client.Statistics.PacketRateAvg,
-				client.Statistics.PacketRateMax,
+			fmt.Printf("Jitter        p50: %9dus    p99: %11dus     Maximum: %7dus\n",
+				stats.Jitter.P50.Microseconds(),
+				stats.Jitter.P99.Microseconds(),
+				stats.Jitter.Max.Microseconds(),
 			)
 			fmt.Printf("Decode time                       Average: %9dus   Maximum: %9dus\n",
-				client.Statistics.DecodeTimeAvg.Microseconds(),
-				client.Statistics.DecodeTimeMax.Microseconds(),
+				stats.DecodeTime.Mean.Microseconds(),
+				stats.DecodeTime.Max.Microseconds(),
 			)
 		}
 
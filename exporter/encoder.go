@@ -0,0 +1,186 @@
+package exporter
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	telemetry "github.com/vwhitteron/gt-telemetry"
+)
+
+type Format string
+
+const (
+	FormatCSV    Format = "csv"
+	FormatJSONL  Format = "jsonl"
+	FormatMotec  Format = "ld"
+	FormatInflux Format = "lp"
+)
+
+func ParseFormat(name string) (Format, error) {
+	switch name {
+	case "csv":
+		return FormatCSV, nil
+	case "jsonl", "ndjson":
+		return FormatJSONL, nil
+	case "ld", "motec":
+		return FormatMotec, nil
+	case "lp", "influx":
+		return FormatInflux, nil
+	default:
+		return "", fmt.Errorf("unsupported export format %q", name)
+	}
+}
+
+// Encoder writes frames to an output stream in a specific format.
+type Encoder interface {
+	Encode(frame telemetry.Frame) error
+	Flush() error
+}
+
+func NewEncoder(format Format, w io.Writer, channels []telemetry.Channel) (Encoder, error) {
+	switch format {
+	case FormatCSV:
+		return NewCSVEncoder(w, channels), nil
+	case FormatJSONL:
+		return NewJSONLEncoder(w, channels), nil
+	case FormatMotec:
+		return NewMotecEncoder(w, nil, channels, MotecOptions{}), nil
+	case FormatInflux:
+		return NewInfluxEncoder(w, channels, InfluxOptions{}), nil
+	default:
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+type CSVEncoder struct {
+	writer        *csv.Writer
+	channels      []telemetry.Channel
+	headerWritten bool
+}
+
+func NewCSVEncoder(w io.Writer, channels []telemetry.Channel) *CSVEncoder {
+	return &CSVEncoder{
+		writer:   csv.NewWriter(w),
+		channels: channels,
+	}
+}
+
+func (e *CSVEncoder) Encode(frame telemetry.Frame) error {
+	if !e.headerWritten {
+		header := make([]string, 0, len(e.channels)+1)
+		header = append(header, "timestamp")
+		for _, channel := range e.channels {
+			header = append(header, channel.Name)
+		}
+
+		if err := e.writer.Write(header); err != nil {
+			return fmt.Errorf("failed to write csv header: %w", err)
+		}
+		e.headerWritten = true
+	}
+
+	record := make([]string, 0, len(e.channels)+1)
+	record = append(record, frame.Received.Format(time.RFC3339Nano))
+	for _, value := range frame.Values(e.channels) {
+		record = append(record, FormatValue(value))
+	}
+
+	if err := e.writer.Write(record); err != nil {
+		return fmt.Errorf("failed to write csv record: %w", err)
+	}
+
+	return nil
+}
+
+func (e *CSVEncoder) Flush() error {
+	e.writer.Flush()
+
+	return e.writer.Error()
+}
+
+type JSONLEncoder struct {
+	writer   io.Writer
+	channels []telemetry.Channel
+	buffer   bytes.Buffer
+}
+
+func NewJSONLEncoder(w io.Writer, channels []telemetry.Channel) *JSONLEncoder {
+	return &JSONLEncoder{
+		writer:   w,
+		channels: channels,
+	}
+}
+
+func (e *JSONLEncoder) Encode(frame telemetry.Frame) error {
+	// The object is assembled by hand to keep the keys in channel order
+	e.buffer.Reset()
+	e.buffer.WriteString(`{"timestamp":"`)
+	e.buffer.WriteString(frame.Received.Format(time.RFC3339Nano))
+	e.buffer.WriteByte('"')
+
+	for i, value := range frame.Values(e.channels) {
+		if !isFinite(value) {
+			value = nil
+		}
+
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("failed to encode channel %q: %w", e.channels[i].Name, err)
+		}
+
+		e.buffer.WriteString(`,"`)
+		e.buffer.WriteString(e.channels[i].Name)
+		e.buffer.WriteString(`":`)
+		e.buffer.Write(encoded)
+	}
+	e.buffer.WriteString("}\n")
+
+	if _, err := e.writer.Write(e.buffer.Bytes()); err != nil {
+		return fmt.Errorf("failed to write json line: %w", err)
+	}
+
+	return nil
+}
+
+func (e *JSONLEncoder) Flush() error {
+	return nil
+}
+
+func isFinite(value any) bool {
+	switch v := value.(type) {
+	case float32:
+		return !math.IsNaN(float64(v)) && !math.IsInf(float64(v), 0)
+	case float64:
+		return !math.IsNaN(v) && !math.IsInf(v, 0)
+	default:
+		return true
+	}
+}
+
+// FormatValue renders a channel value as text without any loss of precision.
+// Channels that do not apply to the vehicle are empty.
+func FormatValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		if v {
+			return "1"
+		}
+		return "0"
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,123 @@
+package exporter
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	telemetry "github.com/vwhitteron/gt-telemetry"
+	"github.com/vwhitteron/gt-telemetry/vehicles"
+)
+
+type EncoderTestSuite struct {
+	suite.Suite
+	channels []telemetry.Channel
+	frame    telemetry.Frame
+}
+
+func TestEncoderTestSuite(t *testing.T) {
+	suite.Run(t, new(EncoderTestSuite))
+}
+
+func (suite *EncoderTestSuite) SetupTest() {
+	channels, err := telemetry.LookupChannels(telemetry.UnitSystemMetric, []string{"sequence_id", "engine_rpm", "flag_live"})
+	suite.Require().NoError(err)
+	suite.channels = channels
+
+	suite.frame = newTestFrame(42, 0)
+	suite.frame.RawTelemetry.EngineRpm = 6543.5
+}
+
+func newTestFrame(sequenceID uint32, lap uint16) telemetry.Frame {
+	frame := telemetry.NewFrame(telemetry.NewTransformer(&vehicles.Inventory{}), time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	frame.RawTelemetry.SequenceId = sequenceID
+	frame.RawTelemetry.CurrentLap = lap
+
+	return frame
+}
+
+func (suite *EncoderTestSuite) TestCSVEncoderWritesHeaderAndRecords() {
+	// Arrange
+	buffer := bytes.Buffer{}
+	encoder := NewCSVEncoder(&buffer, suite.channels)
+
+	// Act
+	suite.NoError(encoder.Encode(suite.frame))
+	suite.NoError(encoder.Encode(suite.frame))
+	suite.NoError(encoder.Flush())
+
+	// Assert
+	suite.Equal(
+		"timestamp,sequence_id,engine_rpm,flag_live\n"+
+			"2024-01-02T03:04:05Z,42,6543.5,0\n"+
+			"2024-01-02T03:04:05Z,42,6543.5,0\n",
+		buffer.String(),
+	)
+}
+
+func (suite *EncoderTestSuite) TestJSONLEncoderWritesOneObjectPerLineInChannelOrder() {
+	// Arrange
+	buffer := bytes.Buffer{}
+	encoder := NewJSONLEncoder(&buffer, suite.channels)
+
+	// Act
+	suite.NoError(encoder.Encode(suite.frame))
+	suite.NoError(encoder.Flush())
+
+	// Assert
+	suite.Equal(
+		`{"timestamp":"2024-01-02T03:04:05Z","sequence_id":42,"engine_rpm":6543.5,"flag_live":false}`+"\n",
+		buffer.String(),
+	)
+}
+
+func (suite *EncoderTestSuite) TestJSONLEncoderWritesNonFiniteValuesAsNull() {
+	// Arrange
+	buffer := bytes.Buffer{}
+	suite.frame.RawTelemetry.EngineRpm = float32(math.Inf(1))
+	encoder := NewJSONLEncoder(&buffer, suite.channels)
+
+	// Act
+	err := encoder.Encode(suite.frame)
+
+	// Assert
+	suite.NoError(err)
+	suite.Contains(buffer.String(), `"engine_rpm":null`)
+}
+
+func (suite *EncoderTestSuite) TestNewEncoderWithUnknownFormatReturnsError() {
+	// Act
+	encoder, err := NewEncoder(Format("xml"), &bytes.Buffer{}, suite.channels)
+
+	// Assert
+	suite.Nil(encoder)
+	suite.ErrorContains(err, `unsupported export format "xml"`)
+}
+
+func (suite *EncoderTestSuite) TestFormatValueRendersValues() {
+	testCases := []struct {
+		value any
+		want  string
+	}{
+		{float32(0.1), "0.1"},
+		{float64(2.5), "2.5"},
+		{true, "1"},
+		{false, "0"},
+		{"Nissan", "Nissan"},
+		{int16(-1), "-1"},
+		{uint32(7), "7"},
+		{nil, ""},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.want, func() {
+			// Act
+			gotValue := FormatValue(tc.value)
+
+			// Assert
+			suite.Equal(tc.want, gotValue)
+		})
+	}
+}
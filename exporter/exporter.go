@@ -0,0 +1,212 @@
+package exporter
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	telemetry "github.com/vwhitteron/gt-telemetry"
+)
+
+type Options struct {
+	Format   Format
+	Channels []telemetry.Channel
+	// Path of the output file. When empty the frames are written to Output.
+	Path   string
+	Output io.Writer
+	// Interval downsamples the output by writing one of every Interval frames.
+	// MoTeC logs need an interval that divides the 60 Hz packet rate.
+	Interval int
+	// SplitLaps writes each lap to a separate file with the lap number
+	// appended to the file name.
+	SplitLaps bool
+	// Motec holds the session details written to MoTeC i2 log headers.
+	Motec MotecOptions
+	// Influx holds the measurement and tags for line protocol output.
+	Influx InfluxOptions
+	// Encoder replaces the file output with a custom encoder, such as an
+	// InfluxHTTPEncoder.
+	Encoder Encoder
+}
+
+type Exporter struct {
+	opts    Options
+	encoder Encoder
+	buffer  *bufio.Writer
+	file    *os.File
+	ldxFile *os.File
+	lap     int16
+	count   int
+}
+
+func New(opts Options) (*Exporter, error) {
+	if len(opts.Channels) == 0 {
+		return nil, errors.New("no channels selected for export")
+	}
+	if opts.Path == "" && opts.Output == nil && opts.Encoder == nil {
+		return nil, errors.New("no output path or writer configured")
+	}
+	if opts.SplitLaps && opts.Path == "" {
+		return nil, errors.New("splitting laps requires an output path")
+	}
+	if opts.Interval < 1 {
+		opts.Interval = 1
+	}
+	if opts.Format == "" && opts.Encoder == nil {
+		format, err := FormatFromPath(opts.Path)
+		if err != nil {
+			return nil, err
+		}
+		opts.Format = format
+	}
+	if opts.Format == FormatMotec && opts.Motec.SampleRate == 0 && motecFrameRate%opts.Interval != 0 {
+		return nil, fmt.Errorf("MoTeC export interval %d does not divide the %d Hz packet rate", opts.Interval, motecFrameRate)
+	}
+
+	e := &Exporter{
+		opts: opts,
+		lap:  -1,
+	}
+
+	if opts.Encoder != nil {
+		e.encoder = opts.Encoder
+		e.opts.SplitLaps = false
+
+		return e, nil
+	}
+
+	if !opts.SplitLaps {
+		if err := e.open(opts.Path); err != nil {
+			return nil, err
+		}
+	}
+
+	return e, nil
+}
+
+// FormatFromPath infers the export format from the extension of a file name.
+func FormatFromPath(path string) (Format, error) {
+	ext := strings.TrimPrefix(filepath.Ext(path), ".")
+	if ext == "" {
+		return "", fmt.Errorf("unable to determine export format from %q", path)
+	}
+
+	return ParseFormat(ext)
+}
+
+// LapPath returns the file name used for a lap when laps are split.
+func LapPath(path string, lap int16) string {
+	ext := filepath.Ext(path)
+
+	return fmt.Sprintf("%s-lap%03d%s", strings.TrimSuffix(path, ext), lap, ext)
+}
+
+func (e *Exporter) Write(frame telemetry.Frame) error {
+	if e.opts.SplitLaps && frame.CurrentLap() != e.lap {
+		if err := e.close(); err != nil {
+			return err
+		}
+		if err := e.open(LapPath(e.opts.Path, frame.CurrentLap())); err != nil {
+			return err
+		}
+		e.lap = frame.CurrentLap()
+		e.count = 0
+	}
+
+	e.count++
+	if (e.count-1)%e.opts.Interval != 0 {
+		return nil
+	}
+
+	return e.encoder.Encode(frame)
+}
+
+func (e *Exporter) Close() error {
+	return e.close()
+}
+
+func (e *Exporter) open(path string) error {
+	output := e.opts.Output
+	if path != "" {
+		fh, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("failed to create export file: %w", err)
+		}
+		e.file = fh
+		output = fh
+	}
+
+	e.buffer = bufio.NewWriter(output)
+
+	if e.opts.Format == FormatMotec {
+		motecOpts := e.opts.Motec
+		if motecOpts.SampleRate == 0 {
+			motecOpts.SampleRate = motecFrameRate / e.opts.Interval
+		}
+
+		var ldx io.Writer
+		if path != "" {
+			fh, err := os.Create(strings.TrimSuffix(path, filepath.Ext(path)) + ".ldx")
+			if err != nil {
+				return fmt.Errorf("failed to create lap marker file: %w", err)
+			}
+			e.ldxFile = fh
+			ldx = fh
+		}
+
+		e.encoder = NewMotecEncoder(e.buffer, ldx, e.opts.Channels, motecOpts)
+
+		return nil
+	}
+
+	if e.opts.Format == FormatInflux {
+		e.encoder = NewInfluxEncoder(e.buffer, e.opts.Channels, e.opts.Influx)
+
+		return nil
+	}
+
+	encoder, err := NewEncoder(e.opts.Format, e.buffer, e.opts.Channels)
+	if err != nil {
+		return err
+	}
+	e.encoder = encoder
+
+	return nil
+}
+
+func (e *Exporter) close() error {
+	if e.encoder == nil {
+		return nil
+	}
+
+	var err error
+	if closer, ok := e.encoder.(io.Closer); ok {
+		err = closer.Close()
+	} else {
+		err = e.encoder.Flush()
+	}
+	if err == nil && e.buffer != nil {
+		err = e.buffer.Flush()
+	}
+	for _, fh := range []*os.File{e.file, e.ldxFile} {
+		if fh == nil {
+			continue
+		}
+		if closeErr := fh.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	e.file = nil
+	e.ldxFile = nil
+	e.encoder = nil
+
+	if err != nil {
+		return fmt.Errorf("failed to finish export: %w", err)
+	}
+
+	return nil
+}
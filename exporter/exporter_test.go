@@ -0,0 +1,117 @@
+package exporter
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	telemetry "github.com/vwhitteron/gt-telemetry"
+)
+
+type ExporterTestSuite struct {
+	suite.Suite
+	channels []telemetry.Channel
+}
+
+func TestExporterTestSuite(t *testing.T) {
+	suite.Run(t, new(ExporterTestSuite))
+}
+
+func (suite *ExporterTestSuite) SetupTest() {
+	channels, err := telemetry.LookupChannels(telemetry.UnitSystemMetric, []string{"sequence_id", "current_lap"})
+	suite.Require().NoError(err)
+	suite.channels = channels
+}
+
+func (suite *ExporterTestSuite) TestExporterDownsamplesFrames() {
+	// Arrange
+	buffer := bytes.Buffer{}
+	export, err := New(Options{
+		Format:   FormatCSV,
+		Channels: suite.channels,
+		Output:   &buffer,
+		Interval: 3,
+	})
+	suite.Require().NoError(err)
+
+	// Act
+	for id := uint32(1); id <= 7; id++ {
+		suite.NoError(export.Write(newTestFrame(id, 1)))
+	}
+	suite.NoError(export.Close())
+
+	// Assert
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	suite.Len(lines, 4)
+	suite.True(strings.HasSuffix(lines[1], ",1,1"))
+	suite.True(strings.HasSuffix(lines[2], ",4,1"))
+	suite.True(strings.HasSuffix(lines[3], ",7,1"))
+}
+
+func (suite *ExporterTestSuite) TestExporterSplitsLapsIntoSeparateFiles() {
+	// Arrange
+	path := filepath.Join(suite.T().TempDir(), "session.jsonl")
+	export, err := New(Options{
+		Channels:  suite.channels,
+		Path:      path,
+		SplitLaps: true,
+	})
+	suite.Require().NoError(err)
+
+	// Act
+	suite.NoError(export.Write(newTestFrame(1, 1)))
+	suite.NoError(export.Write(newTestFrame(2, 1)))
+	suite.NoError(export.Write(newTestFrame(3, 2)))
+	suite.NoError(export.Close())
+
+	// Assert
+	lap1, err := os.ReadFile(LapPath(path, 1))
+	suite.NoError(err)
+	suite.Equal(2, strings.Count(string(lap1), "\n"))
+
+	lap2, err := os.ReadFile(LapPath(path, 2))
+	suite.NoError(err)
+	suite.Equal(1, strings.Count(string(lap2), "\n"))
+	suite.Contains(string(lap2), `"sequence_id":3`)
+}
+
+func (suite *ExporterTestSuite) TestNewExporterValidatesOptions() {
+	testCases := map[string]struct {
+		opts Options
+		want string
+	}{
+		"NoChannels": {Options{Path: "out.csv"}, "no channels selected for export"},
+		"NoOutput":   {Options{Channels: suite.channels}, "no output path or writer configured"},
+		"SplitLapsWithoutPath": {
+			Options{Channels: suite.channels, Output: &bytes.Buffer{}, Format: FormatCSV, SplitLaps: true},
+			"splitting laps requires an output path",
+		},
+		"UnknownExtension": {Options{Channels: suite.channels, Path: "out.xls"}, `unsupported export format "xls"`},
+		"MotecUnevenInterval": {
+			Options{Channels: suite.channels, Output: &bytes.Buffer{}, Format: FormatMotec, Interval: 7},
+			"MoTeC export interval 7 does not divide the 60 Hz packet rate",
+		},
+	}
+
+	for name, tc := range testCases {
+		suite.Run(name, func() {
+			// Act
+			export, err := New(tc.opts)
+
+			// Assert
+			suite.Nil(export)
+			suite.ErrorContains(err, tc.want)
+		})
+	}
+}
+
+func (suite *ExporterTestSuite) TestLapPathInsertsLapNumberBeforeExtension() {
+	// Act
+	gotValue := LapPath("/tmp/session.csv", 7)
+
+	// Assert
+	suite.Equal("/tmp/session-lap007.csv", gotValue)
+}
@@ -0,0 +1,315 @@
+package exporter
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	telemetry "github.com/vwhitteron/gt-telemetry"
+)
+
+const (
+	defaultInfluxMeasurement   = "gt_telemetry"
+	defaultInfluxBatchSize     = 500
+	defaultInfluxFlushInterval = time.Second
+	// Number of batches held for retry before points are discarded
+	influxMaxPendingBatches = 10
+)
+
+type InfluxOptions struct {
+	// Measurement name for every point, defaults to gt_telemetry.
+	Measurement string
+	// SessionID is added as a tag to tell sessions apart.
+	SessionID string
+	// Tags are static tags added to every point.
+	Tags map[string]string
+}
+
+// InfluxEncoder writes frames as InfluxDB line protocol with the vehicle,
+// session and lap as tags and the numeric channels as fields.
+type InfluxEncoder struct {
+	writer   io.Writer
+	channels []telemetry.Channel
+	opts     InfluxOptions
+	line     bytes.Buffer
+}
+
+func NewInfluxEncoder(w io.Writer, channels []telemetry.Channel, opts InfluxOptions) *InfluxEncoder {
+	if opts.Measurement == "" {
+		opts.Measurement = defaultInfluxMeasurement
+	}
+
+	return &InfluxEncoder{
+		writer:   w,
+		channels: channels,
+		opts:     opts,
+	}
+}
+
+func (e *InfluxEncoder) Encode(frame telemetry.Frame) error {
+	if !e.encodeLine(frame) {
+		return nil
+	}
+
+	if _, err := e.writer.Write(e.line.Bytes()); err != nil {
+		return fmt.Errorf("failed to write line protocol: %w", err)
+	}
+
+	return nil
+}
+
+func (e *InfluxEncoder) Flush() error {
+	return nil
+}
+
+// encodeLine renders a frame into the line buffer, frames without any numeric
+// fields are skipped and reported as false.
+func (e *InfluxEncoder) encodeLine(frame telemetry.Frame) bool {
+	tags := map[string]string{
+		"vehicle_id":   strconv.FormatUint(uint64(frame.VehicleID()), 10),
+		"manufacturer": frame.VehicleManufacturer(),
+		"model":        frame.VehicleModel(),
+		"session_id":   e.opts.SessionID,
+		"lap":          strconv.Itoa(int(frame.CurrentLap())),
+	}
+	for key, value := range e.opts.Tags {
+		tags[key] = value
+	}
+
+	keys := make([]string, 0, len(tags))
+	for key, value := range tags {
+		// empty tag values are rejected by InfluxDB
+		if value != "" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	e.line.Reset()
+	e.line.WriteString(influxEscape(e.opts.Measurement, ", "))
+	for _, key := range keys {
+		e.line.WriteByte(',')
+		e.line.WriteString(influxEscape(key, ",= "))
+		e.line.WriteByte('=')
+		e.line.WriteString(influxEscape(tags[key], ",= "))
+	}
+
+	fields := 0
+	for i, value := range frame.Values(e.channels) {
+		formatted, ok := influxFieldValue(value)
+		if !ok {
+			continue
+		}
+
+		if fields == 0 {
+			e.line.WriteByte(' ')
+		} else {
+			e.line.WriteByte(',')
+		}
+		e.line.WriteString(influxEscape(e.channels[i].Name, ",= "))
+		e.line.WriteByte('=')
+		e.line.WriteString(formatted)
+		fields++
+	}
+
+	if fields == 0 {
+		return false
+	}
+
+	e.line.WriteByte(' ')
+	e.line.WriteString(strconv.FormatInt(frame.Received.UnixNano(), 10))
+	e.line.WriteByte('\n')
+
+	return true
+}
+
+// influxFieldValue formats a numeric channel value as a field, strings,
+// values that are not finite and channels that do not apply to the vehicle
+// are not written.
+func influxFieldValue(value any) (string, bool) {
+	switch v := value.(type) {
+	case float32:
+		if math.IsNaN(float64(v)) || math.IsInf(float64(v), 0) {
+			return "", false
+		}
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), true
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return "", false
+		}
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(v), true
+	case int:
+		return strconv.Itoa(v) + "i", true
+	case int16:
+		return strconv.Itoa(int(v)) + "i", true
+	case int64:
+		return strconv.FormatInt(v, 10) + "i", true
+	case uint16:
+		return strconv.FormatUint(uint64(v), 10) + "i", true
+	case uint32:
+		return strconv.FormatUint(uint64(v), 10) + "i", true
+	case uint64:
+		return strconv.FormatUint(v, 10) + "i", true
+	default:
+		return "", false
+	}
+}
+
+func influxEscape(s string, chars string) string {
+	if !strings.ContainsAny(s, chars+`\`) {
+		return s
+	}
+
+	sb := strings.Builder{}
+	for _, r := range s {
+		if r == '\\' || strings.ContainsRune(chars, r) {
+			sb.WriteByte('\\')
+		}
+		sb.WriteRune(r)
+	}
+
+	return sb.String()
+}
+
+type InfluxHTTPOptions struct {
+	// URL of the write endpoint including the database or bucket parameters,
+	// e.g. http://localhost:8086/api/v2/write?org=team&bucket=gt7
+	URL string
+	// Token is sent as the authorization token when set.
+	Token string
+	// BatchSize is the maximum number of points sent in a single request.
+	BatchSize int
+	// FlushInterval is the longest time points are held before being sent.
+	FlushInterval time.Duration
+	Client        *http.Client
+	// OnError is called with the writes that fail while the points are kept
+	// to be sent again with the next batch.
+	OnError func(err error)
+}
+
+// InfluxHTTPEncoder batches line protocol points and sends them to an
+// InfluxDB compatible write endpoint.
+type InfluxHTTPEncoder struct {
+	encoder   *InfluxEncoder
+	opts      InfluxHTTPOptions
+	batch     bytes.Buffer
+	points    int
+	lastFlush time.Time
+	lastErr   error
+}
+
+func NewInfluxHTTPEncoder(channels []telemetry.Channel, opts InfluxOptions, httpOpts InfluxHTTPOptions) (*InfluxHTTPEncoder, error) {
+	if httpOpts.URL == "" {
+		return nil, errors.New("no influxdb write url configured")
+	}
+	if httpOpts.BatchSize < 1 {
+		httpOpts.BatchSize = defaultInfluxBatchSize
+	}
+	if httpOpts.FlushInterval <= 0 {
+		httpOpts.FlushInterval = defaultInfluxFlushInterval
+	}
+	if httpOpts.Client == nil {
+		httpOpts.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	e := &InfluxHTTPEncoder{
+		opts:      httpOpts,
+		lastFlush: time.Now(),
+	}
+	e.encoder = NewInfluxEncoder(&e.batch, channels, opts)
+
+	return e, nil
+}
+
+func (e *InfluxHTTPEncoder) Encode(frame telemetry.Frame) error {
+	before := e.batch.Len()
+	if err := e.encoder.Encode(frame); err != nil {
+		return err
+	}
+	if e.batch.Len() > before {
+		e.points++
+	}
+
+	if e.points >= e.opts.BatchSize || time.Since(e.lastFlush) >= e.opts.FlushInterval {
+		return e.Flush()
+	}
+
+	return nil
+}
+
+// Flush sends any buffered points to the write endpoint. When the write
+// fails the points are kept to be sent again with the next batch, and an
+// error is only returned once too many points are pending and they are
+// dropped.
+func (e *InfluxHTTPEncoder) Flush() error {
+	e.lastFlush = time.Now()
+	if e.points == 0 {
+		return nil
+	}
+
+	req, err := http.NewRequest(http.MethodPost, e.opts.URL, bytes.NewReader(e.batch.Bytes()))
+	if err != nil {
+		return fmt.Errorf("failed to create influxdb request: %w", err)
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if e.opts.Token != "" {
+		req.Header.Set("Authorization", "Token "+e.opts.Token)
+	}
+
+	err = e.send(req)
+	e.lastErr = err
+	if err != nil && e.points < influxMaxPendingBatches*e.opts.BatchSize {
+		if e.opts.OnError != nil {
+			e.opts.OnError(err)
+		}
+
+		return nil
+	}
+
+	dropped := e.points
+	e.batch.Reset()
+	e.points = 0
+
+	if err != nil {
+		return fmt.Errorf("dropped %d influxdb points: %w", dropped, err)
+	}
+
+	return nil
+}
+
+// Close sends the buffered points and returns an error when any could not be
+// sent.
+func (e *InfluxHTTPEncoder) Close() error {
+	if err := e.Flush(); err != nil {
+		return err
+	}
+	if e.points > 0 {
+		return fmt.Errorf("failed to send %d influxdb points: %w", e.points, e.lastErr)
+	}
+
+	return nil
+}
+
+func (e *InfluxHTTPEncoder) send(req *http.Request) error {
+	resp, err := e.opts.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send points to influxdb: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("influxdb write failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return nil
+}
@@ -0,0 +1,220 @@
+package exporter
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	telemetry "github.com/vwhitteron/gt-telemetry"
+)
+
+type InfluxTestSuite struct {
+	suite.Suite
+	channels []telemetry.Channel
+}
+
+func TestInfluxTestSuite(t *testing.T) {
+	suite.Run(t, new(InfluxTestSuite))
+}
+
+func (suite *InfluxTestSuite) SetupTest() {
+	channels, err := telemetry.LookupChannels(telemetry.UnitSystemMetric, []string{
+		"engine_rpm", "current_gear", "vehicle_model", "flag_live",
+	})
+	suite.Require().NoError(err)
+	suite.channels = channels
+}
+
+type fakeInflux struct {
+	mu       sync.Mutex
+	requests []string
+	tokens   []string
+	status   int
+}
+
+func (f *fakeInflux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	body, _ := io.ReadAll(r.Body)
+	f.requests = append(f.requests, string(body))
+	f.tokens = append(f.tokens, r.Header.Get("Authorization"))
+
+	if f.status != 0 {
+		w.WriteHeader(f.status)
+		_, _ = w.Write([]byte("database not found"))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (suite *InfluxTestSuite) TestInfluxEncoderWritesLineProtocol() {
+	// Arrange
+	buffer := bytes.Buffer{}
+	encoder := NewInfluxEncoder(&buffer, suite.channels, InfluxOptions{SessionID: "race 1"})
+	frame := newTestFrame(10, 2)
+	frame.RawTelemetry.EngineRpm = 5000.25
+	frame.RawTelemetry.VehicleId = 123
+
+	// Act
+	err := encoder.Encode(frame)
+
+	// Assert
+	suite.NoError(err)
+	suite.Equal(
+		`gt_telemetry,lap=2,session_id=race\ 1,vehicle_id=123 engine_rpm=5000.25,current_gear=15i,flag_live=false 1704164645000000000`+"\n",
+		buffer.String(),
+	)
+}
+
+func (suite *InfluxTestSuite) TestInfluxEncoderSkipsNonFiniteFields() {
+	// Arrange
+	buffer := bytes.Buffer{}
+	channels, _ := telemetry.LookupChannels(telemetry.UnitSystemMetric, []string{"engine_rpm", "current_gear"})
+	encoder := NewInfluxEncoder(&buffer, channels, InfluxOptions{Measurement: "car"})
+	frame := newTestFrame(1, 0)
+	frame.RawTelemetry.EngineRpm = float32(math.NaN())
+
+	// Act
+	err := encoder.Encode(frame)
+
+	// Assert
+	suite.NoError(err)
+	suite.Equal("car,lap=0,vehicle_id=0 current_gear=15i 1704164645000000000\n", buffer.String())
+}
+
+func (suite *InfluxTestSuite) TestInfluxEscapeEscapesSpecialCharacters() {
+	testCases := map[string]string{
+		"plain":      "plain",
+		"a b":        `a\ b`,
+		"a,b=c":      `a\,b\=c`,
+		`back\slash`: `back\\slash`,
+	}
+
+	for value, want := range testCases {
+		suite.Run(value, func() {
+			// Act
+			gotValue := influxEscape(value, ",= ")
+
+			// Assert
+			suite.Equal(want, gotValue)
+		})
+	}
+}
+
+func (suite *InfluxTestSuite) TestInfluxHTTPEncoderSendsBatches() {
+	// Arrange
+	fake := &fakeInflux{}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	encoder, err := NewInfluxHTTPEncoder(suite.channels, InfluxOptions{}, InfluxHTTPOptions{
+		URL:       server.URL + "/api/v2/write?bucket=gt7",
+		Token:     "secret",
+		BatchSize: 2,
+	})
+	suite.Require().NoError(err)
+
+	// Act
+	for id := uint32(1); id <= 5; id++ {
+		suite.NoError(encoder.Encode(newTestFrame(id, 1)))
+	}
+	suite.NoError(encoder.Flush())
+
+	// Assert
+	suite.Len(fake.requests, 3)
+	suite.Equal(2, strings.Count(fake.requests[0], "\n"))
+	suite.Equal(2, strings.Count(fake.requests[1], "\n"))
+	suite.Equal(1, strings.Count(fake.requests[2], "\n"))
+	suite.Equal("Token secret", fake.tokens[0])
+}
+
+func (suite *InfluxTestSuite) TestInfluxHTTPEncoderKeepsPointsWhenWriteFails() {
+	// Arrange
+	fake := &fakeInflux{status: http.StatusNotFound}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	failures := []error{}
+	encoder, err := NewInfluxHTTPEncoder(suite.channels, InfluxOptions{}, InfluxHTTPOptions{
+		URL:       server.URL,
+		BatchSize: 1,
+		OnError:   func(err error) { failures = append(failures, err) },
+	})
+	suite.Require().NoError(err)
+
+	// Act
+	err = encoder.Encode(newTestFrame(1, 1))
+	fake.status = 0
+	retryErr := encoder.Encode(newTestFrame(2, 1))
+
+	// Assert
+	suite.NoError(err)
+	suite.Require().Len(failures, 1)
+	suite.ErrorContains(failures[0], "influxdb write failed with status 404: database not found")
+	suite.NoError(retryErr)
+	suite.Len(fake.requests, 2)
+	suite.Equal(2, strings.Count(fake.requests[1], "\n"))
+}
+
+func (suite *InfluxTestSuite) TestInfluxHTTPEncoderReportsPointsThatAreNotSent() {
+	testCases := map[string]struct {
+		act  func(encoder *InfluxHTTPEncoder) error
+		want string
+	}{
+		"dropped": {
+			act: func(encoder *InfluxHTTPEncoder) error {
+				var err error
+				for i := 1; i <= influxMaxPendingBatches && err == nil; i++ {
+					err = encoder.Encode(newTestFrame(uint32(i), 1))
+				}
+				return err
+			},
+			want: "dropped 10 influxdb points",
+		},
+		"pending on close": {
+			act: func(encoder *InfluxHTTPEncoder) error {
+				if err := encoder.Encode(newTestFrame(1, 1)); err != nil {
+					return err
+				}
+				return encoder.Close()
+			},
+			want: "failed to send 1 influxdb points",
+		},
+	}
+
+	for name, tc := range testCases {
+		suite.Run(name, func() {
+			// Arrange
+			server := httptest.NewServer(&fakeInflux{status: http.StatusNotFound})
+			defer server.Close()
+			encoder, err := NewInfluxHTTPEncoder(suite.channels, InfluxOptions{}, InfluxHTTPOptions{
+				URL:       server.URL,
+				BatchSize: 1,
+			})
+			suite.Require().NoError(err)
+
+			// Act
+			err = tc.act(encoder)
+
+			// Assert
+			suite.ErrorContains(err, tc.want)
+			suite.ErrorContains(err, "status 404")
+		})
+	}
+}
+
+func (suite *InfluxTestSuite) TestNewInfluxHTTPEncoderRequiresURL() {
+	// Act
+	encoder, err := NewInfluxHTTPEncoder(suite.channels, InfluxOptions{}, InfluxHTTPOptions{})
+
+	// Assert
+	suite.Nil(encoder)
+	suite.ErrorContains(err, "no influxdb write url configured")
+}
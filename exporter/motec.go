@@ -0,0 +1,426 @@
+package exporter
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	telemetry "github.com/vwhitteron/gt-telemetry"
+)
+
+// Layout of the MoTeC i2 log file blocks, all blocks are little endian and
+// written back to back in the order listed here.
+const (
+	motecHeaderSize  = 1762
+	motecEventSize   = 1154
+	motecVenueSize   = 1100
+	motecVehicleSize = 260
+	motecChannelSize = 124
+)
+
+const (
+	motecFrameRate  = 60
+	motecSlowRate   = 10
+	motecDeviceType = "ADL"
+)
+
+type MotecOptions struct {
+	Driver  string
+	Venue   string
+	Event   string
+	Session string
+	Comment string
+	// SampleRate is the rate in Hz that frames are passed to the encoder.
+	SampleRate int
+}
+
+type motecChannel struct {
+	channel   telemetry.Channel
+	name      string
+	shortName string
+	unit      string
+	rate      int
+	samples   []float32
+}
+
+type motecLap struct {
+	number int16
+	start  time.Duration
+}
+
+type motecLapTime struct {
+	number  int16
+	laptime time.Duration
+}
+
+// MotecEncoder buffers frames in memory and writes a MoTeC i2 log (.ld) and
+// optional lap marker (.ldx) file when flushed.
+type MotecEncoder struct {
+	writer      io.Writer
+	ldxWriter   io.Writer
+	opts        MotecOptions
+	channels    []telemetry.Channel
+	selected    []*motecChannel
+	started     time.Time
+	vehicle     string
+	vehicleType string
+	frames      int
+	lap         int16
+	laps        []motecLap
+	lapTimes    []motecLapTime
+}
+
+func NewMotecEncoder(w io.Writer, ldx io.Writer, channels []telemetry.Channel, opts MotecOptions) *MotecEncoder {
+	if opts.SampleRate < 1 {
+		opts.SampleRate = motecFrameRate
+	}
+
+	return &MotecEncoder{
+		writer:    w,
+		ldxWriter: ldx,
+		opts:      opts,
+		channels:  channels,
+	}
+}
+
+func (e *MotecEncoder) Encode(frame telemetry.Frame) error {
+	if e.selected == nil {
+		e.start(frame)
+	}
+
+	lap := frame.CurrentLap()
+	if lap != e.lap {
+		if lap > 0 {
+			e.laps = append(e.laps, motecLap{
+				number: lap,
+				start:  e.elapsed(),
+			})
+		}
+		if lastLaptime := frame.LastLaptime(); e.lap > 0 && lastLaptime > 0 {
+			e.lapTimes = append(e.lapTimes, motecLapTime{
+				number:  e.lap,
+				laptime: lastLaptime,
+			})
+		}
+		e.lap = lap
+	}
+
+	for _, channel := range e.selected {
+		if e.frames%(e.opts.SampleRate/channel.rate) != 0 {
+			continue
+		}
+
+		value, _ := motecValue(channel.channel.Value(frame))
+		channel.samples = append(channel.samples, value)
+	}
+	e.frames++
+
+	return nil
+}
+
+func (e *MotecEncoder) Flush() error {
+	if e.selected == nil {
+		return nil
+	}
+
+	if _, err := e.writer.Write(e.encodeLog()); err != nil {
+		return fmt.Errorf("failed to write motec log: %w", err)
+	}
+
+	if e.ldxWriter != nil {
+		if _, err := io.WriteString(e.ldxWriter, e.encodeLapMarkers()); err != nil {
+			return fmt.Errorf("failed to write motec lap markers: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// start selects the numeric channels and captures the session details from
+// the first frame.
+func (e *MotecEncoder) start(frame telemetry.Frame) {
+	e.started = frame.Received
+	e.vehicle = strings.TrimSpace(frame.VehicleManufacturer() + " " + frame.VehicleModel())
+	e.vehicleType = frame.VehicleCategory()
+	e.lap = frame.CurrentLap()
+
+	e.selected = []*motecChannel{}
+	for _, channel := range e.channels {
+		if _, ok := motecValue(channel.Value(frame)); !ok {
+			continue
+		}
+
+		name, shortName := motecChannelName(channel.Name)
+		rate := e.opts.SampleRate
+		if motecSlowChannel(channel.Name) && rate > motecSlowRate && rate%motecSlowRate == 0 {
+			rate = motecSlowRate
+		}
+
+		e.selected = append(e.selected, &motecChannel{
+			channel:   channel,
+			name:      name,
+			shortName: shortName,
+			unit:      channel.Unit,
+			rate:      rate,
+		})
+	}
+}
+
+func (e *MotecEncoder) elapsed() time.Duration {
+	return time.Duration(e.frames) * time.Second / time.Duration(e.opts.SampleRate)
+}
+
+func (e *MotecEncoder) encodeLog() []byte {
+	eventPtr := motecHeaderSize
+	venuePtr := eventPtr + motecEventSize
+	vehiclePtr := venuePtr + motecVenueSize
+	metaPtr := vehiclePtr + motecVehicleSize
+	dataPtr := metaPtr + motecChannelSize*len(e.selected)
+	if len(e.selected) == 0 {
+		metaPtr = 0
+	}
+
+	buf := &motecBuffer{}
+
+	// File header
+	buf.u32(0x40)
+	buf.pad(4)
+	buf.u32(uint32(metaPtr))
+	buf.u32(uint32(dataPtr))
+	buf.pad(20)
+	buf.u32(uint32(eventPtr))
+	buf.pad(24)
+	buf.u16(1)
+	buf.u16(0x4240)
+	buf.u16(0xf)
+	buf.u32(0x1f44)
+	buf.str(motecDeviceType, 8)
+	buf.u16(420)
+	buf.u16(0xadb0)
+	buf.u32(uint32(len(e.selected)))
+	buf.pad(4)
+	buf.str(e.started.Format("02/01/2006"), 16)
+	buf.pad(16)
+	buf.str(e.started.Format("15:04:05"), 16)
+	buf.pad(16)
+	buf.str(e.opts.Driver, 64)
+	buf.str(e.vehicle, 64)
+	buf.pad(64)
+	buf.str(e.opts.Venue, 64)
+	buf.pad(64)
+	buf.pad(1024)
+	buf.u32(0xc81a4)
+	buf.pad(66)
+	buf.str(e.opts.Comment, 64)
+	buf.pad(126)
+
+	// Event
+	buf.str(e.opts.Event, 64)
+	buf.str(e.opts.Session, 64)
+	buf.str(e.opts.Comment, 1024)
+	buf.u16(uint16(venuePtr))
+
+	// Venue
+	buf.str(e.opts.Venue, 64)
+	buf.pad(1034)
+	buf.u16(uint16(vehiclePtr))
+
+	// Vehicle
+	buf.str(e.vehicle, 64)
+	buf.pad(128)
+	buf.u32(0)
+	buf.str(e.vehicleType, 32)
+	buf.str("", 32)
+
+	// Channel metadata as a doubly linked list followed by the sample data
+	offset := dataPtr
+	for i, channel := range e.selected {
+		prev, next := 0, 0
+		if i > 0 {
+			prev = metaPtr + (i-1)*motecChannelSize
+		}
+		if i < len(e.selected)-1 {
+			next = metaPtr + (i+1)*motecChannelSize
+		}
+
+		buf.u32(uint32(prev))
+		buf.u32(uint32(next))
+		buf.u32(uint32(offset))
+		buf.u32(uint32(len(channel.samples)))
+		buf.u16(uint16(0x2ee1 + i))
+		buf.u16(0x07) // floating point samples
+		buf.u16(4)    // 4 byte samples
+		buf.u16(uint16(channel.rate))
+		buf.u16(0) // shift
+		buf.u16(1) // multiplier
+		buf.u16(1) // scale
+		buf.u16(0) // decimal places
+		buf.str(channel.name, 32)
+		buf.str(channel.shortName, 8)
+		buf.str(channel.unit, 12)
+		buf.pad(40)
+
+		offset += 4 * len(channel.samples)
+	}
+
+	for _, channel := range e.selected {
+		for _, sample := range channel.samples {
+			buf.u32(math.Float32bits(sample))
+		}
+	}
+
+	return buf.Bytes()
+}
+
+func (e *MotecEncoder) encodeLapMarkers() string {
+	sb := strings.Builder{}
+	sb.WriteString("<?xml version=\"1.0\"?>\n")
+	sb.WriteString("<LDXFile Locale=\"English_United States.1252\" DefaultLocale=\"C\" Version=\"1.6\">\n")
+	sb.WriteString(" <Layers>\n  <Layer>\n   <MarkerBlock>\n")
+	sb.WriteString("    <MarkerGroup Name=\"Beacons\" Index=\"3\">\n")
+	for _, lap := range e.laps {
+		fmt.Fprintf(&sb, "     <Marker Version=\"100\" ClassName=\"BCN\" Name=\"Lap %d\" Flags=\"77\" Time=\"%d.000000\"/>\n",
+			lap.number,
+			lap.start.Microseconds(),
+		)
+	}
+	sb.WriteString("    </MarkerGroup>\n")
+	sb.WriteString("   </MarkerBlock>\n  </Layer>\n  <RangeBlock/>\n </Layers>\n")
+	sb.WriteString(" <Details>\n")
+	fmt.Fprintf(&sb, "  <String Id=\"Total Laps\" Value=\"%d\"/>\n", len(e.laps))
+
+	fastest := motecLapTime{}
+	for _, lap := range e.lapTimes {
+		if fastest.number == 0 || lap.laptime < fastest.laptime {
+			fastest = lap
+		}
+	}
+	if fastest.number > 0 {
+		fmt.Fprintf(&sb, "  <String Id=\"Fastest Time\" Value=\"%d:%06.3f\"/>\n",
+			int(fastest.laptime.Minutes()),
+			math.Mod(fastest.laptime.Seconds(), 60),
+		)
+		fmt.Fprintf(&sb, "  <String Id=\"Fastest Lap\" Value=\"%d\"/>\n", fastest.number)
+	}
+	sb.WriteString(" </Details>\n")
+	sb.WriteString("</LDXFile>\n")
+
+	return sb.String()
+}
+
+// motecValue converts a channel value to a float sample. String values and
+// channels that do not apply to the vehicle can not be logged and are
+// reported as not ok.
+func motecValue(value any) (float32, bool) {
+	switch v := value.(type) {
+	case float32:
+		return v, true
+	case float64:
+		return float32(v), true
+	case bool:
+		if v {
+			return 1, true
+		}
+		return 0, true
+	case int:
+		return float32(v), true
+	case int16:
+		return float32(v), true
+	case int64:
+		return float32(v), true
+	case uint16:
+		return float32(v), true
+	case uint32:
+		return float32(v), true
+	case uint64:
+		return float32(v), true
+	default:
+		return 0, false
+	}
+}
+
+// motecChannelNames maps channels to the names used by the MoTeC i2 default
+// workbooks so that the stock maths and worksheets pick them up.
+var motecChannelNames = map[string][2]string{
+	"ground_speed":      {"Ground Speed", "Speed"},
+	"engine_rpm":        {"Engine RPM", "RPM"},
+	"throttle":          {"Throttle Pos", "TPS"},
+	"brake":             {"Brake Pos", "Brake"},
+	"current_gear":      {"Gear", "Gear"},
+	"current_lap":       {"Lap Number", "Lap"},
+	"fuel_level":        {"Fuel Level", "Fuel"},
+	"oil_pressure":      {"Oil Pressure", "OilP"},
+	"oil_temperature":   {"Oil Temp", "OilT"},
+	"water_temperature": {"Water Temp", "WatT"},
+	"turbo_boost":       {"Boost Pressure", "Boost"},
+	"position_x":        {"Car Pos X", "PosX"},
+	"position_y":        {"Car Pos Y", "PosY"},
+	"position_z":        {"Car Pos Z", "PosZ"},
+}
+
+var motecAbbreviations = map[string]bool{
+	"fl": true, "fr": true, "rl": true, "rr": true,
+	"id": true, "rpm": true, "asm": true, "tcs": true,
+	"x": true, "y": true, "z": true,
+}
+
+func motecChannelName(name string) (string, string) {
+	if names, ok := motecChannelNames[name]; ok {
+		return names[0], names[1]
+	}
+
+	words := strings.Split(name, "_")
+	short := ""
+	for i, word := range words {
+		if word == "" {
+			continue
+		}
+		if motecAbbreviations[word] {
+			words[i] = strings.ToUpper(word)
+		} else {
+			words[i] = strings.ToUpper(word[:1]) + word[1:]
+		}
+		short += strings.ToUpper(word[:1])
+	}
+
+	return strings.Join(words, " "), short
+}
+
+// motecSlowChannel reports whether a channel changes slowly enough to be
+// logged at a reduced rate.
+func motecSlowChannel(name string) bool {
+	for _, prefix := range []string{"tyre_temperature", "oil_", "water_", "fuel_", "race_", "best_", "last_", "vehicle_", "starting_", "gear_ratio"} {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type motecBuffer struct {
+	bytes.Buffer
+}
+
+func (b *motecBuffer) u16(v uint16) {
+	_ = binary.Write(b, binary.LittleEndian, v)
+}
+
+func (b *motecBuffer) u32(v uint32) {
+	_ = binary.Write(b, binary.LittleEndian, v)
+}
+
+func (b *motecBuffer) pad(n int) {
+	b.Write(make([]byte, n))
+}
+
+// str writes a fixed width, zero padded string truncating it when too long.
+func (b *motecBuffer) str(s string, n int) {
+	field := make([]byte, n)
+	copy(field, s)
+	b.Write(field)
+}
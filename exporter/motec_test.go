@@ -0,0 +1,142 @@
+package exporter
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	telemetry "github.com/vwhitteron/gt-telemetry"
+)
+
+type MotecTestSuite struct {
+	suite.Suite
+	channels []telemetry.Channel
+}
+
+func TestMotecTestSuite(t *testing.T) {
+	suite.Run(t, new(MotecTestSuite))
+}
+
+func (suite *MotecTestSuite) SetupTest() {
+	channels, err := telemetry.LookupChannels(telemetry.UnitSystemMetric, []string{
+		"engine_rpm", "vehicle_model", "water_temperature", "tyre_temperature_fl",
+	})
+	suite.Require().NoError(err)
+	suite.channels = channels
+}
+
+func (suite *MotecTestSuite) encode(frames int, lapAt func(i int) uint16) ([]byte, string) {
+	ld := bytes.Buffer{}
+	ldx := bytes.Buffer{}
+	encoder := NewMotecEncoder(&ld, &ldx, suite.channels, MotecOptions{Driver: "Test Driver", Venue: "Fuji"})
+
+	for i := 0; i < frames; i++ {
+		frame := newTestFrame(uint32(i), lapAt(i))
+		frame.RawTelemetry.EngineRpm = float32(i)
+		frame.RawTelemetry.LastLaptime = int32(90000 + i)
+		suite.Require().NoError(encoder.Encode(frame))
+	}
+	suite.Require().NoError(encoder.Flush())
+
+	return ld.Bytes(), ldx.String()
+}
+
+func (suite *MotecTestSuite) TestMotecEncoderWritesHeaderPointers() {
+	// Act
+	ld, _ := suite.encode(60, func(int) uint16 { return 1 })
+
+	// Assert
+	metaPtr := binary.LittleEndian.Uint32(ld[8:12])
+	dataPtr := binary.LittleEndian.Uint32(ld[12:16])
+	eventPtr := binary.LittleEndian.Uint32(ld[36:40])
+	channelCount := binary.LittleEndian.Uint32(ld[86:90])
+
+	suite.Equal(uint32(motecHeaderSize), eventPtr)
+	suite.Equal(uint32(motecHeaderSize+motecEventSize+motecVenueSize+motecVehicleSize), metaPtr)
+	suite.Equal(uint32(3), channelCount, "string channels are not logged")
+	suite.Equal(metaPtr+3*motecChannelSize, dataPtr)
+	suite.Equal("ADL", string(bytes.TrimRight(ld[74:82], "\x00")))
+	suite.Equal("Test Driver", string(bytes.TrimRight(ld[158:222], "\x00")))
+	suite.Equal("Fuji", string(bytes.TrimRight(ld[350:414], "\x00")))
+}
+
+func (suite *MotecTestSuite) TestMotecEncoderWritesChannelMetadataAndSamples() {
+	// Act
+	ld, _ := suite.encode(60, func(int) uint16 { return 1 })
+
+	// Assert
+	metaPtr := binary.LittleEndian.Uint32(ld[8:12])
+	meta := ld[metaPtr : metaPtr+motecChannelSize]
+
+	next := binary.LittleEndian.Uint32(meta[4:8])
+	dataPtr := binary.LittleEndian.Uint32(meta[8:12])
+	samples := binary.LittleEndian.Uint32(meta[12:16])
+	rate := binary.LittleEndian.Uint16(meta[22:24])
+
+	suite.Equal(metaPtr+motecChannelSize, next)
+	suite.Equal(uint32(60), samples)
+	suite.Equal(uint16(60), rate)
+	suite.Equal("Engine RPM", string(bytes.TrimRight(meta[32:64], "\x00")))
+	suite.Equal("rpm", string(bytes.TrimRight(meta[72:84], "\x00")))
+	suite.Equal(float32(59), math.Float32frombits(binary.LittleEndian.Uint32(ld[dataPtr+59*4:])))
+}
+
+func (suite *MotecTestSuite) TestMotecEncoderLogsSlowChannelsAtReducedRate() {
+	// Act
+	ld, _ := suite.encode(60, func(int) uint16 { return 1 })
+
+	// Assert
+	metaPtr := binary.LittleEndian.Uint32(ld[8:12])
+	meta := ld[metaPtr+motecChannelSize : metaPtr+2*motecChannelSize]
+
+	suite.Equal("Water Temp", string(bytes.TrimRight(meta[32:64], "\x00")))
+	suite.Equal(uint32(10), binary.LittleEndian.Uint32(meta[12:16]))
+	suite.Equal(uint16(10), binary.LittleEndian.Uint16(meta[22:24]))
+}
+
+func (suite *MotecTestSuite) TestMotecEncoderWritesLapMarkers() {
+	// Act
+	_, ldx := suite.encode(180, func(i int) uint16 { return uint16(i/60 + 1) })
+
+	// Assert
+	suite.NotContains(ldx, `Name="Lap 1"`, "the log starts part way through the first lap")
+	suite.Contains(ldx, `Name="Lap 2" Flags="77" Time="1000000.000000"`)
+	suite.Contains(ldx, `Name="Lap 3" Flags="77" Time="2000000.000000"`)
+	suite.Contains(ldx, `<String Id="Total Laps" Value="2"/>`)
+	suite.Contains(ldx, `<String Id="Fastest Time" Value="1:30.060"/>`)
+	suite.Contains(ldx, `<String Id="Fastest Lap" Value="1"/>`)
+}
+
+func (suite *MotecTestSuite) TestMotecChannelNameUsesWorkbookNames() {
+	testCases := map[string][2]string{
+		"ground_speed":        {"Ground Speed", "Speed"},
+		"tyre_temperature_fl": {"Tyre Temperature FL", "TTF"},
+		"time_of_day":         {"Time Of Day", "TOD"},
+	}
+
+	for name, want := range testCases {
+		suite.Run(name, func() {
+			// Act
+			gotName, gotShort := motecChannelName(name)
+
+			// Assert
+			suite.Equal(want[0], gotName)
+			suite.Equal(want[1], gotShort)
+		})
+	}
+}
+
+func (suite *MotecTestSuite) TestMotecEncoderWithoutFramesWritesNothing() {
+	// Arrange
+	ld := bytes.Buffer{}
+	encoder := NewMotecEncoder(&ld, nil, suite.channels, MotecOptions{})
+
+	// Act
+	err := encoder.Flush()
+
+	// Assert
+	suite.NoError(err)
+	suite.Zero(ld.Len())
+}
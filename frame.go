@@ -0,0 +1,45 @@
+package telemetry
+
+import "time"
+
+// Frame is a snapshot of the telemetry from a single decoded packet. All of
+// the transformer methods are available on a frame, it is safe to keep after
+// later packets have been received and to read from several goroutines.
+type Frame struct {
+	*transformer
+	Received time.Time
+}
+
+func NewFrame(t *transformer, received time.Time) Frame {
+	// the vehicle is resolved before the copy so that reading the frame
+	// does not need to update it
+	t.updateVehicle()
+	snapshot := *t
+	snapshot.frozen = true
+
+	return Frame{
+		transformer: &snapshot,
+		Received:    received,
+	}
+}
+
+// copy returns a frame with its own snapshot of the transformer, so that
+// frames handed to different consumers do not share any state.
+func (f Frame) copy() Frame {
+	snapshot := *f.transformer
+
+	return Frame{
+		transformer: &snapshot,
+		Received:    f.Received,
+	}
+}
+
+// Values samples the given channels from the frame.
+func (f Frame) Values(channels []Channel) []any {
+	values := make([]any, len(channels))
+	for i, channel := range channels {
+		values[i] = channel.Value(f)
+	}
+
+	return values
+}
@@ -53,6 +53,8 @@ type GranTurismoTelemetry struct {
 	TransmissionTopSpeedRatio float32
 	TransmissionGearRatio     *GranTurismoTelemetry_GearRatio
 	VehicleId                 uint32
+	Motion                    *GranTurismoTelemetry_Motion
+	Energy                    *GranTurismoTelemetry_Energy
 	_io                       *kaitai.Stream
 	_root                     *GranTurismoTelemetry
 	_parent                   interface{}
@@ -307,6 +309,30 @@ func (this *GranTurismoTelemetry) Read(io *kaitai.Stream, parent interface{}, ro
 		return err
 	}
 	this.VehicleId = uint32(tmp45)
+	tmp46, err := this._io.EOF()
+	if err != nil {
+		return err
+	}
+	if !(tmp46) {
+		tmp47 := NewGranTurismoTelemetry_Motion()
+		err = tmp47.Read(this._io, this, this._root)
+		if err != nil {
+			return err
+		}
+		this.Motion = tmp47
+	}
+	tmp48, err := this._io.EOF()
+	if err != nil {
+		return err
+	}
+	if !(tmp48) {
+		tmp49 := NewGranTurismoTelemetry_Energy()
+		err = tmp49.Read(this._io, this, this._root)
+		if err != nil {
+			return err
+		}
+		this.Energy = tmp49
+	}
 	return err
 }
 
@@ -344,86 +370,86 @@ func (this *GranTurismoTelemetry_Flags) Read(io *kaitai.Stream, parent *GranTuri
 	this._parent = parent
 	this._root = root
 
-	tmp46, err := this._io.ReadBitsIntLe(1)
-	if err != nil {
-		return err
-	}
-	this.Live = tmp46 != 0
-	tmp47, err := this._io.ReadBitsIntLe(1)
-	if err != nil {
-		return err
-	}
-	this.GamePaused = tmp47 != 0
-	tmp48, err := this._io.ReadBitsIntLe(1)
-	if err != nil {
-		return err
-	}
-	this.Loading = tmp48 != 0
-	tmp49, err := this._io.ReadBitsIntLe(1)
-	if err != nil {
-		return err
-	}
-	this.InGear = tmp49 != 0
 	tmp50, err := this._io.ReadBitsIntLe(1)
 	if err != nil {
 		return err
 	}
-	this.HasTurbo = tmp50 != 0
+	this.Live = tmp50 != 0
 	tmp51, err := this._io.ReadBitsIntLe(1)
 	if err != nil {
 		return err
 	}
-	this.RevLimiterAlert = tmp51 != 0
+	this.GamePaused = tmp51 != 0
 	tmp52, err := this._io.ReadBitsIntLe(1)
 	if err != nil {
 		return err
 	}
-	this.HandBrakeActive = tmp52 != 0
+	this.Loading = tmp52 != 0
 	tmp53, err := this._io.ReadBitsIntLe(1)
 	if err != nil {
 		return err
 	}
-	this.HeadlightsActive = tmp53 != 0
+	this.InGear = tmp53 != 0
 	tmp54, err := this._io.ReadBitsIntLe(1)
 	if err != nil {
 		return err
 	}
-	this.HighBeamActive = tmp54 != 0
+	this.HasTurbo = tmp54 != 0
 	tmp55, err := this._io.ReadBitsIntLe(1)
 	if err != nil {
 		return err
 	}
-	this.LowBeamActive = tmp55 != 0
+	this.RevLimiterAlert = tmp55 != 0
 	tmp56, err := this._io.ReadBitsIntLe(1)
 	if err != nil {
 		return err
 	}
-	this.AsmActive = tmp56 != 0
+	this.HandBrakeActive = tmp56 != 0
 	tmp57, err := this._io.ReadBitsIntLe(1)
 	if err != nil {
 		return err
 	}
-	this.TcsActive = tmp57 != 0
+	this.HeadlightsActive = tmp57 != 0
 	tmp58, err := this._io.ReadBitsIntLe(1)
 	if err != nil {
 		return err
 	}
-	this.Flag13 = tmp58 != 0
+	this.HighBeamActive = tmp58 != 0
 	tmp59, err := this._io.ReadBitsIntLe(1)
 	if err != nil {
 		return err
 	}
-	this.Flag14 = tmp59 != 0
+	this.LowBeamActive = tmp59 != 0
 	tmp60, err := this._io.ReadBitsIntLe(1)
 	if err != nil {
 		return err
 	}
-	this.Flag15 = tmp60 != 0
+	this.AsmActive = tmp60 != 0
 	tmp61, err := this._io.ReadBitsIntLe(1)
 	if err != nil {
 		return err
 	}
-	this.Flag16 = tmp61 != 0
+	this.TcsActive = tmp61 != 0
+	tmp62, err := this._io.ReadBitsIntLe(1)
+	if err != nil {
+		return err
+	}
+	this.Flag13 = tmp62 != 0
+	tmp63, err := this._io.ReadBitsIntLe(1)
+	if err != nil {
+		return err
+	}
+	this.Flag14 = tmp63 != 0
+	tmp64, err := this._io.ReadBitsIntLe(1)
+	if err != nil {
+		return err
+	}
+	this.Flag15 = tmp64 != 0
+	tmp65, err := this._io.ReadBitsIntLe(1)
+	if err != nil {
+		return err
+	}
+	this.Flag16 = tmp65 != 0
 	return err
 }
 
@@ -448,21 +474,21 @@ func (this *GranTurismoTelemetry_Vector) Read(io *kaitai.Stream, parent *GranTur
 	this._parent = parent
 	this._root = root
 
-	tmp62, err := this._io.ReadF4le()
+	tmp66, err := this._io.ReadF4le()
 	if err != nil {
 		return err
 	}
-	this.VectorX = float32(tmp62)
-	tmp63, err := this._io.ReadF4le()
+	this.VectorX = float32(tmp66)
+	tmp67, err := this._io.ReadF4le()
 	if err != nil {
 		return err
 	}
-	this.VectorY = float32(tmp63)
-	tmp64, err := this._io.ReadF4le()
+	this.VectorY = float32(tmp67)
+	tmp68, err := this._io.ReadF4le()
 	if err != nil {
 		return err
 	}
-	this.VectorZ = float32(tmp64)
+	this.VectorZ = float32(tmp68)
 	return err
 }
 
@@ -488,26 +514,26 @@ func (this *GranTurismoTelemetry_CornerSet) Read(io *kaitai.Stream, parent *Gran
 	this._parent = parent
 	this._root = root
 
-	tmp65, err := this._io.ReadF4le()
+	tmp69, err := this._io.ReadF4le()
 	if err != nil {
 		return err
 	}
-	this.FrontLeft = float32(tmp65)
-	tmp66, err := this._io.ReadF4le()
+	this.FrontLeft = float32(tmp69)
+	tmp70, err := this._io.ReadF4le()
 	if err != nil {
 		return err
 	}
-	this.FrontRight = float32(tmp66)
-	tmp67, err := this._io.ReadF4le()
+	this.FrontRight = float32(tmp70)
+	tmp71, err := this._io.ReadF4le()
 	if err != nil {
 		return err
 	}
-	this.RearLeft = float32(tmp67)
-	tmp68, err := this._io.ReadF4le()
+	this.RearLeft = float32(tmp71)
+	tmp72, err := this._io.ReadF4le()
 	if err != nil {
 		return err
 	}
-	this.RearRight = float32(tmp68)
+	this.RearRight = float32(tmp72)
 	return err
 }
 
@@ -532,21 +558,21 @@ func (this *GranTurismoTelemetry_Coordinate) Read(io *kaitai.Stream, parent *Gra
 	this._parent = parent
 	this._root = root
 
-	tmp69, err := this._io.ReadF4le()
+	tmp73, err := this._io.ReadF4le()
 	if err != nil {
 		return err
 	}
-	this.CoordinateX = float32(tmp69)
-	tmp70, err := this._io.ReadF4le()
+	this.CoordinateX = float32(tmp73)
+	tmp74, err := this._io.ReadF4le()
 	if err != nil {
 		return err
 	}
-	this.CoordinateY = float32(tmp70)
-	tmp71, err := this._io.ReadF4le()
+	this.CoordinateY = float32(tmp74)
+	tmp75, err := this._io.ReadF4le()
 	if err != nil {
 		return err
 	}
-	this.CoordinateZ = float32(tmp71)
+	this.CoordinateZ = float32(tmp75)
 	return err
 }
 
@@ -570,16 +596,16 @@ func (this *GranTurismoTelemetry_TransmissionGear) Read(io *kaitai.Stream, paren
 	this._parent = parent
 	this._root = root
 
-	tmp72, err := this._io.ReadBitsIntLe(4)
+	tmp76, err := this._io.ReadBitsIntLe(4)
 	if err != nil {
 		return err
 	}
-	this.Current = tmp72
-	tmp73, err := this._io.ReadBitsIntLe(4)
+	this.Current = tmp76
+	tmp77, err := this._io.ReadBitsIntLe(4)
 	if err != nil {
 		return err
 	}
-	this.Suggested = tmp73
+	this.Suggested = tmp77
 	return err
 }
 
@@ -602,12 +628,12 @@ func (this *GranTurismoTelemetry_Header) Read(io *kaitai.Stream, parent *GranTur
 	this._parent = parent
 	this._root = root
 
-	tmp74, err := this._io.ReadBytes(int(4))
+	tmp78, err := this._io.ReadBytes(int(4))
 	if err != nil {
 		return err
 	}
-	tmp74 = tmp74
-	this.Magic = tmp74
+	tmp78 = tmp78
+	this.Magic = tmp78
 	if !(bytes.Equal(this.Magic, []uint8{48, 83, 55, 71})) {
 		return kaitai.NewValidationNotEqualError([]uint8{48, 83, 55, 71}, this.Magic, this._io, "/types/header/seq/0")
 	}
@@ -635,11 +661,11 @@ func (this *GranTurismoTelemetry_GearRatio) Read(io *kaitai.Stream, parent *Gran
 
 	for i := 0; i < int(8); i++ {
 		_ = i
-		tmp75, err := this._io.ReadF4le()
+		tmp79, err := this._io.ReadF4le()
 		if err != nil {
 			return err
 		}
-		this.Gear = append(this.Gear, tmp75)
+		this.Gear = append(this.Gear, tmp79)
 	}
 	return err
 }
@@ -665,20 +691,134 @@ func (this *GranTurismoTelemetry_SymmetryAxes) Read(io *kaitai.Stream, parent *G
 	this._parent = parent
 	this._root = root
 
-	tmp76, err := this._io.ReadF4le()
+	tmp80, err := this._io.ReadF4le()
+	if err != nil {
+		return err
+	}
+	this.Pitch = float32(tmp80)
+	tmp81, err := this._io.ReadF4le()
+	if err != nil {
+		return err
+	}
+	this.Yaw = float32(tmp81)
+	tmp82, err := this._io.ReadF4le()
+	if err != nil {
+		return err
+	}
+	this.Roll = float32(tmp82)
+	return err
+}
+
+/**
+ * Steering and body motion
+ */
+type GranTurismoTelemetry_Motion struct {
+	WheelRotation float32
+	Ignore2       []byte
+	Sway          float32
+	Heave         float32
+	Surge         float32
+	_io           *kaitai.Stream
+	_root         *GranTurismoTelemetry
+	_parent       *GranTurismoTelemetry
+}
+
+func NewGranTurismoTelemetry_Motion() *GranTurismoTelemetry_Motion {
+	return &GranTurismoTelemetry_Motion{}
+}
+
+func (this *GranTurismoTelemetry_Motion) Read(io *kaitai.Stream, parent *GranTurismoTelemetry, root *GranTurismoTelemetry) (err error) {
+	this._io = io
+	this._parent = parent
+	this._root = root
+
+	tmp83, err := this._io.ReadF4le()
 	if err != nil {
 		return err
 	}
-	this.Pitch = float32(tmp76)
-	tmp77, err := this._io.ReadF4le()
+	this.WheelRotation = float32(tmp83)
+	tmp84, err := this._io.ReadBytes(int(4))
+	if err != nil {
+		return err
+	}
+	tmp84 = tmp84
+	this.Ignore2 = tmp84
+	tmp85, err := this._io.ReadF4le()
+	if err != nil {
+		return err
+	}
+	this.Sway = float32(tmp85)
+	tmp86, err := this._io.ReadF4le()
+	if err != nil {
+		return err
+	}
+	this.Heave = float32(tmp86)
+	tmp87, err := this._io.ReadF4le()
+	if err != nil {
+		return err
+	}
+	this.Surge = float32(tmp87)
+	return err
+}
+
+/**
+ * Filtered inputs, torque vectoring and energy recovery
+ */
+type GranTurismoTelemetry_Energy struct {
+	ThrottleFiltered uint8
+	BrakeFiltered    uint8
+	Ignore3          []byte
+	TorqueVector     []float32
+	EnergyRecovery   float32
+	Ignore4          []byte
+	_io              *kaitai.Stream
+	_root            *GranTurismoTelemetry
+	_parent          *GranTurismoTelemetry
+}
+
+func NewGranTurismoTelemetry_Energy() *GranTurismoTelemetry_Energy {
+	return &GranTurismoTelemetry_Energy{}
+}
+
+func (this *GranTurismoTelemetry_Energy) Read(io *kaitai.Stream, parent *GranTurismoTelemetry, root *GranTurismoTelemetry) (err error) {
+	this._io = io
+	this._parent = parent
+	this._root = root
+
+	tmp88, err := this._io.ReadU1()
+	if err != nil {
+		return err
+	}
+	this.ThrottleFiltered = tmp88
+	tmp89, err := this._io.ReadU1()
+	if err != nil {
+		return err
+	}
+	this.BrakeFiltered = tmp89
+	tmp90, err := this._io.ReadBytes(int(2))
+	if err != nil {
+		return err
+	}
+	tmp90 = tmp90
+	this.Ignore3 = tmp90
+	for i := 0; i < int(4); i++ {
+		_ = i
+		tmp91, err := this._io.ReadF4le()
+		if err != nil {
+			return err
+		}
+		this.TorqueVector = append(this.TorqueVector, tmp91)
+	}
+	tmp92, err := this._io.ReadF4le()
 	if err != nil {
 		return err
 	}
-	this.Yaw = float32(tmp77)
-	tmp78, err := this._io.ReadF4le()
+	this.EnergyRecovery = float32(tmp92)
+	tmp93, err := this._io.ReadBytes(int(4))
 	if err != nil {
 		return err
 	}
-	this.Roll = float32(tmp78)
+	tmp93 = tmp93
+	this.Ignore4 = tmp93
 	return err
 }
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"bytes"
 	"compress/gzip"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -14,58 +15,71 @@ import (
 
 var packetHeader = []byte{0x30, 0x53, 0x37, 0x47}
 
+var gzipMagic = []byte{0x1f, 0x8b}
+
 const packetInterval = (1000 / 60) * time.Millisecond
 
 type FileReader struct {
 	fileContent *bufio.Scanner
 	lastRead    time.Time
+	realtime    bool
 	log         zerolog.Logger
 	closer      func() error
 }
 
-func NewFileReader(file string, log zerolog.Logger) *FileReader {
-	_, err := os.Stat(file)
-	if os.IsNotExist(err) {
-		log.Fatal().Str("file", file).Msg("file does not exist")
-	} else if err != nil {
-		log.Fatal().Err(err).Msg("failed to check file")
+func NewFileReader(file string, realtime bool, log zerolog.Logger) (*FileReader, error) {
+	if len(file) < 3 {
+		return nil, fmt.Errorf("filename too short: %q", file)
+	}
+
+	fileExt := file[len(file)-3:]
+	if fileExt != "gtz" && fileExt != "gtr" {
+		return nil, fmt.Errorf("unsupported file extension %q", fileExt)
 	}
 
 	fh, err := os.Open(file)
 	if err != nil {
-		log.Fatal().Err(err).Msg("failed to open file")
+		return nil, fmt.Errorf("failed to open file: %w", err)
 	}
 
-	if len(file) < 3 {
-		log.Fatal().Str("file", file).Msg("filename too short")
+	r, err := NewReplayReader(fh, realtime, log)
+	if err != nil {
+		fh.Close()
+		return nil, err
 	}
+	r.closer = fh.Close
 
-	var reader io.Reader
-	fileExt := file[len(file)-3:]
-	switch fileExt {
-	case "gtz":
-		reader, err = gzip.NewReader(fh)
+	return r, nil
+}
+
+// NewReplayReader reads a plain or gzip compressed replay from any reader, the
+// compression is detected from the content.
+func NewReplayReader(content io.Reader, realtime bool, log zerolog.Logger) (*FileReader, error) {
+	buffered := bufio.NewReader(content)
+
+	var reader io.Reader = buffered
+	magic, _ := buffered.Peek(len(gzipMagic))
+	if bytes.Equal(magic, gzipMagic) {
+		gz, err := gzip.NewReader(buffered)
 		if err != nil {
-			log.Fatal().Err(err).Msg("failed to create gzip reader")
+			return nil, fmt.Errorf("failed to create gzip reader: %w", err)
 		}
-	case "gtr":
-		reader = fh
-	default:
-		log.Fatal().Str("extension", fileExt).Msg("unsupported file extension")
+		reader = gz
 	}
 
 	scanner := bufio.NewScanner(reader)
 
 	splitFunc := func(data []byte, atEOF bool) (advance int, token []byte, err error) {
 		headerLen := len(packetHeader)
-		if bytes.Equal(data[:headerLen], packetHeader) {
+		if len(data) >= headerLen && bytes.Equal(data[:headerLen], packetHeader) {
 			return headerLen, data[:headerLen], nil
 		}
 		if bytes.Contains(data, packetHeader) {
-			packetLen := bytes.Index(data, packetHeader) + 4
+			// the header of the next packet is consumed along with this one
+			packetLen := bytes.Index(data, packetHeader)
 			packet := append(packetHeader, data[:packetLen]...)
 
-			return packetLen, packet, nil
+			return packetLen + headerLen, packet, nil
 		}
 		if atEOF {
 			if len(data) == 0 {
@@ -74,7 +88,7 @@ func NewFileReader(file string, log zerolog.Logger) *FileReader {
 
 			packet := append(packetHeader, data...)
 
-			return len(packet), packet, nil
+			return len(data), packet, nil
 		}
 		return 0, nil, nil
 	}
@@ -84,9 +98,10 @@ func NewFileReader(file string, log zerolog.Logger) *FileReader {
 	return &FileReader{
 		fileContent: scanner,
 		lastRead:    time.Unix(0, 0),
+		realtime:    realtime,
 		log:         log,
-		closer:      fh.Close,
-	}
+		closer:      func() error { return nil },
+	}, nil
 }
 
 func (r *FileReader) Read() (int, []byte, error) {
@@ -97,7 +112,14 @@ func (r *FileReader) Read() (int, []byte, error) {
 
 	ok := r.fileContent.Scan()
 	if !ok {
-		return 0, nil, r.fileContent.Err()
+		// the split function always ends the scan with an error once the
+		// final packet has been read
+		err := r.fileContent.Err()
+		if err == nil || errors.Is(err, bufio.ErrAdvanceTooFar) || err.Error() == "EOF" {
+			return 0, nil, io.EOF
+		}
+
+		return 0, nil, fmt.Errorf("failed to read replay: %w", err)
 	}
 
 	packet := r.fileContent.Bytes()
@@ -105,6 +127,10 @@ func (r *FileReader) Read() (int, []byte, error) {
 		return 0, nil, nil
 	}
 
+	if !r.realtime {
+		return len(packet), packet, nil
+	}
+
 	elapsed := time.Since(r.lastRead)
 	waitTime := packetInterval - elapsed
 
@@ -118,6 +144,11 @@ func (r *FileReader) Read() (int, []byte, error) {
 	return len(packet), packet, nil
 }
 
+// Realtime reports whether packets are paced at the console packet rate.
+func (r *FileReader) Realtime() bool {
+	return r.realtime
+}
+
 func (r *FileReader) Close() error {
-	return nil
+	return r.closer()
 }
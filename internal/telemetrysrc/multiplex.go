@@ -0,0 +1,266 @@
+package telemetrysrc
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/vwhitteron/gt-telemetry/internal/utils"
+)
+
+// Packets queued for each console before further packets are dropped
+const multiplexQueueSize = 120
+
+// UDPMultiplexer shares a single socket between several consoles. It sends
+// heartbeats to every console and routes the packets it receives to a
+// stream for the console they were sent from.
+type UDPMultiplexer struct {
+	conn     *net.UDPConn
+	sendPort int
+	opts     UDPOptions
+	streams  map[string]*MultiplexStream
+	done     chan struct{}
+	once     sync.Once
+	log      zerolog.Logger
+}
+
+// MultiplexStream is the telemetry stream of a single console.
+type MultiplexStream struct {
+	mux     *UDPMultiplexer
+	address string
+	packets chan []byte
+	done    chan struct{}
+	stalled bool
+	backoff time.Duration
+}
+
+// NewUDPMultiplexer listens for every console on a single socket configured
+// by the options, which apply to the heartbeats and stall detection of each
+// console.
+func NewUDPMultiplexer(hosts []string, sendPort int, opts UDPOptions, log zerolog.Logger) (*UDPMultiplexer, error) {
+	if len(hosts) == 0 {
+		return nil, errors.New("no consoles to multiplex")
+	}
+	opts, err := opts.withDefaults(sendPort)
+	if err != nil {
+		return nil, err
+	}
+
+	// the listener is in the same family as the first console
+	first, err := net.ResolveIPAddr("ip", hosts[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve console address %q: %w", hosts[0], err)
+	}
+	bind, err := bindAddress(opts, first.IP)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := net.ListenUDP(udpNetwork(first.IP), bind)
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup UDP listener: %w", err)
+	}
+
+	m, err := newMultiplexer(conn, hosts, sendPort, opts, log)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func newMultiplexer(conn *net.UDPConn, hosts []string, sendPort int, opts UDPOptions, log zerolog.Logger) (*UDPMultiplexer, error) {
+	if len(hosts) == 0 {
+		return nil, errors.New("no consoles to multiplex")
+	}
+	opts, err := opts.withDefaults(sendPort)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &UDPMultiplexer{
+		conn:     conn,
+		sendPort: sendPort,
+		opts:     opts,
+		streams:  map[string]*MultiplexStream{},
+		done:     make(chan struct{}),
+		log:      log,
+	}
+
+	for _, host := range hosts {
+		addr, err := net.ResolveIPAddr("ip", host)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve console address %q: %w", host, err)
+		}
+
+		address := addr.IP.String()
+		if _, ok := m.streams[address]; ok {
+			return nil, fmt.Errorf("console %q is listed more than once", host)
+		}
+		m.streams[address] = &MultiplexStream{
+			mux:     m,
+			address: address,
+			packets: make(chan []byte, multiplexQueueSize),
+			done:    m.done,
+		}
+	}
+
+	go m.sendHeartbeats()
+	go m.receive()
+
+	return m, nil
+}
+
+// Stream returns the stream for a console host or address, or nil if the
+// console is not multiplexed.
+func (m *UDPMultiplexer) Stream(address string) *MultiplexStream {
+	addr, err := net.ResolveIPAddr("ip", address)
+	if err != nil {
+		return nil
+	}
+
+	return m.streams[addr.IP.String()]
+}
+
+// Close stops the heartbeats and ends every stream.
+func (m *UDPMultiplexer) Close() error {
+	var err error
+	m.once.Do(func() {
+		close(m.done)
+		err = m.conn.Close()
+	})
+
+	return err
+}
+
+func (m *UDPMultiplexer) sendHeartbeats() {
+	ticker := time.NewTicker(m.opts.HeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		for address := range m.streams {
+			m.sendHeartbeat(address)
+		}
+
+		select {
+		case <-m.done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *UDPMultiplexer) sendHeartbeat(address string) {
+	m.log.Debug().Msgf("sending heartbeat to %s:%d", address, m.sendPort)
+
+	console := &net.UDPAddr{IP: net.ParseIP(address), Port: m.sendPort}
+	if console.IP.IsLinkLocalUnicast() {
+		console.Zone = m.opts.Interface
+	}
+
+	_, err := m.conn.WriteToUDP([]byte(m.opts.PacketFormat), console)
+	if err != nil {
+		m.log.Error().Err(err).Str("console", address).Msg("failed to send heartbeat")
+	}
+}
+
+func (m *UDPMultiplexer) receive() {
+	for {
+		buffer := make([]byte, 4096)
+		bufLen, addr, err := m.conn.ReadFromUDP(buffer)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			m.log.Debug().Err(err).Msg("failed to receive telemetry")
+			continue
+		}
+
+		stream, ok := m.streams[addr.IP.String()]
+		if !ok {
+			m.log.Debug().Str("address", addr.IP.String()).Msg("ignoring packet from unknown console")
+			continue
+		}
+
+		select {
+		case stream.packets <- buffer[:bufLen]:
+		default:
+			m.log.Warn().Str("console", stream.address).Msg("console stream is full, packet dropped")
+		}
+	}
+}
+
+// Address returns the address of the console.
+func (s *MultiplexStream) Address() string {
+	return s.address
+}
+
+// Read returns the next deciphered packet from the console, and io.EOF once
+// the multiplexer is closed. Heartbeats are resent to the console with an
+// increasing backoff while its stream is stalled.
+func (s *MultiplexStream) Read() (int, []byte, error) {
+	opts := s.mux.opts
+	for {
+		wait := opts.StallTimeout
+		if s.stalled {
+			wait = s.backoff
+		}
+		timer := time.NewTimer(wait)
+
+		select {
+		case packet := <-s.packets:
+			timer.Stop()
+			if s.stalled {
+				s.stalled = false
+				s.mux.log.Info().Str("console", s.address).Msg("telemetry stream resumed")
+			}
+
+			return s.decipher(packet)
+		case <-s.done:
+			timer.Stop()
+
+			return 0, nil, io.EOF
+		case <-timer.C:
+		}
+
+		s.mux.sendHeartbeat(s.address)
+
+		if s.stalled {
+			s.backoff = min(s.backoff*2, opts.MaxBackoff)
+			s.mux.log.Debug().Str("console", s.address).Dur("backoff", s.backoff).Msg("telemetry stream still stalled")
+			continue
+		}
+
+		s.stalled = true
+		s.backoff = opts.StallTimeout
+		s.mux.log.Warn().Str("console", s.address).Dur("timeout", wait).Msg("telemetry stream stalled")
+
+		return 0, nil, fmt.Errorf("%w: no telemetry received for %s", ErrStalled, wait)
+	}
+}
+
+func (s *MultiplexStream) decipher(packet []byte) (int, []byte, error) {
+	if len(packet) == 0 {
+		return 0, packet, fmt.Errorf("no data received")
+	}
+	if s.mux.opts.Decrypted {
+		return len(packet), packet, nil
+	}
+
+	decipheredPacket, err := utils.Salsa20Decode(packet)
+	if err != nil {
+		return 0, packet, fmt.Errorf("failed to decipher telemetry: %s", err.Error())
+	}
+
+	return len(packet), decipheredPacket, nil
+}
+
+// Close is a no-op, the shared socket is closed with the multiplexer.
+func (s *MultiplexStream) Close() error {
+	return nil
+}
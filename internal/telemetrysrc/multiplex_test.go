@@ -0,0 +1,134 @@
+package telemetrysrc
+
+import (
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/suite"
+)
+
+type MultiplexTestSuite struct {
+	suite.Suite
+	consoles []*net.UDPConn
+	mux      *UDPMultiplexer
+}
+
+func TestMultiplexTestSuite(t *testing.T) {
+	suite.Run(t, new(MultiplexTestSuite))
+}
+
+func (suite *MultiplexTestSuite) SetupTest() {
+	first, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	suite.Require().NoError(err)
+
+	// both consoles listen on the same port as they would on a real network
+	port := first.LocalAddr().(*net.UDPAddr).Port
+	second, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 2), Port: port})
+	suite.Require().NoError(err)
+	suite.consoles = []*net.UDPConn{first, second}
+
+	listener, err := net.ListenUDP("udp", &net.UDPAddr{})
+	suite.Require().NoError(err)
+
+	suite.mux, err = newMultiplexer(listener, []string{"127.0.0.1", "127.0.0.2"}, port, UDPOptions{
+		StallTimeout: 50 * time.Millisecond,
+		MaxBackoff:   100 * time.Millisecond,
+	}, zerolog.Nop())
+	suite.Require().NoError(err)
+}
+
+func (suite *MultiplexTestSuite) TearDownTest() {
+	suite.mux.Close()
+	for _, conn := range suite.consoles {
+		conn.Close()
+	}
+}
+
+// heartbeat waits for the multiplexer heartbeat and returns its address.
+func (suite *MultiplexTestSuite) heartbeat(console *net.UDPConn) *net.UDPAddr {
+	buffer := make([]byte, 16)
+	suite.Require().NoError(console.SetReadDeadline(time.Now().Add(time.Second)))
+	n, addr, err := console.ReadFromUDP(buffer)
+	suite.Require().NoError(err)
+	suite.Equal("A", string(buffer[:n]))
+
+	return &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: addr.Port}
+}
+
+func (suite *MultiplexTestSuite) TestPacketsAreRoutedByConsoleAddress() {
+	// Arrange
+	for i, console := range suite.consoles {
+		addr := suite.heartbeat(console)
+		packet := make([]byte, 296)
+		copy(packet, packetHeader)
+		packet[0x70] = byte(i + 1)
+		_, err := console.WriteToUDP(encryptPacket(packet, uint32(i)), addr)
+		suite.Require().NoError(err)
+	}
+
+	// Act
+	first := suite.mux.Stream("127.0.0.1")
+	second := suite.mux.Stream("127.0.0.2")
+
+	// Assert
+	suite.Require().NotNil(first)
+	suite.Require().NotNil(second)
+
+	bufLen, packet, err := first.Read()
+	suite.Require().NoError(err)
+	suite.Equal(296, bufLen)
+	suite.Equal(byte(1), packet[0x70])
+
+	bufLen, packet, err = second.Read()
+	suite.Require().NoError(err)
+	suite.Equal(296, bufLen)
+	suite.Equal(byte(2), packet[0x70])
+}
+
+func (suite *MultiplexTestSuite) TestSilentConsolesStallAndResume() {
+	// Arrange
+	stream := suite.mux.Stream("127.0.0.2")
+	suite.heartbeat(suite.consoles[1])
+
+	// Act
+	_, _, stallErr := stream.Read()
+	addr := suite.heartbeat(suite.consoles[1])
+
+	read := make(chan error, 1)
+	go func() {
+		_, _, err := stream.Read()
+		read <- err
+	}()
+
+	packet := make([]byte, 296)
+	copy(packet, packetHeader)
+	_, err := suite.consoles[1].WriteToUDP(encryptPacket(packet, 1), addr)
+	suite.Require().NoError(err)
+
+	// Assert
+	suite.ErrorIs(stallErr, ErrStalled)
+	suite.NoError(<-read)
+}
+
+func (suite *MultiplexTestSuite) TestUnknownConsolesHaveNoStream() {
+	// Act
+	stream := suite.mux.Stream("192.0.2.1")
+
+	// Assert
+	suite.Nil(stream)
+}
+
+func (suite *MultiplexTestSuite) TestStreamsEndWhenClosed() {
+	// Arrange
+	stream := suite.mux.Stream("127.0.0.2")
+
+	// Act
+	suite.Require().NoError(suite.mux.Close())
+	_, _, err := stream.Read()
+
+	// Assert
+	suite.ErrorIs(err, io.EOF)
+}
@@ -1,65 +1,128 @@
 package telemetrysrc
 
 import (
+	"errors"
 	"fmt"
-	"log"
 	"net"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/vwhitteron/gt-telemetry/internal/utils"
 )
 
+const (
+	DefaultHeartbeatInterval = 10 * time.Second
+	DefaultStallTimeout      = 3 * time.Second
+	DefaultMaxBackoff        = 30 * time.Second
+	DefaultPacketFormat      = "A"
+)
+
+// ErrStalled is returned by Read when no telemetry has arrived within the
+// stall timeout. Reading can continue and packets are returned as soon as the
+// stream resumes.
+var ErrStalled = errors.New("telemetry stream stalled")
+
+type UDPOptions struct {
+	// BindAddress is the local address to receive telemetry on, all
+	// interfaces by default.
+	BindAddress string
+	// Interface restricts the listener to the addresses of a network
+	// interface and sets the zone of link-local IPv6 consoles.
+	Interface string
+	// ReceivePort is the local port telemetry is received on, the console
+	// send port plus one by default.
+	ReceivePort int
+	// HeartbeatInterval is how often heartbeats are sent to the console.
+	HeartbeatInterval time.Duration
+	// StallTimeout is how long the stream can be silent before a heartbeat is
+	// resent and the stream is reported as stalled.
+	StallTimeout time.Duration
+	// MaxBackoff limits the interval between heartbeats while stalled, the
+	// interval doubles from the stall timeout after each attempt.
+	MaxBackoff time.Duration
+	// PacketFormat is the heartbeat sent to the console, which selects the
+	// packet format. A is the standard packet, B adds body motion and ~
+	// adds energy recovery.
+	PacketFormat string
+	// Decrypted reads packets that have already been deciphered, such as
+	// from a relay forwarding decrypted packets.
+	Decrypted bool
+}
+
 type UDPReader struct {
 	conn      *net.UDPConn
-	address   string
-	sendPort  int
+	console   *net.UDPAddr
+	opts      UDPOptions
 	closeFunc func() error
+	done      chan struct{}
+	once      sync.Once
 	log       zerolog.Logger
+	stalled   bool
+	backoff   time.Duration
 }
 
-func NewNetworkUDPReader(host string, sendPort int, log zerolog.Logger) *UDPReader {
+func NewNetworkUDPReader(host string, sendPort int, opts UDPOptions, log zerolog.Logger) (*UDPReader, error) {
 	log.Debug().Msg("creating UDP reader")
-	receivePort := sendPort + 1
-	addr, err := net.ResolveUDPAddr("udp", fmt.Sprintf(":%d", receivePort))
+	opts, err := opts.withDefaults(sendPort)
+	if err != nil {
+		return nil, err
+	}
+
+	console, err := net.ResolveUDPAddr("udp", net.JoinHostPort(host, strconv.Itoa(sendPort)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve console address: %w", err)
+	}
+	if console.IP.IsLinkLocalUnicast() && console.Zone == "" {
+		console.Zone = opts.Interface
+	}
+
+	bind, err := bindAddress(opts, console.IP)
 	if err != nil {
-		log.Fatal().Err(err).Msg("failed to resolve UDP address")
+		return nil, err
 	}
 
-	conn, err := net.ListenUDP("udp", addr)
+	conn, err := net.ListenUDP(udpNetwork(console.IP), bind)
 	if err != nil {
-		log.Fatal().Err(err).Msg("failed to setup UDP listener")
+		return nil, fmt.Errorf("failed to setup UDP listener: %w", err)
 	}
 
 	r := UDPReader{
 		conn:      conn,
-		address:   host,
-		sendPort:  sendPort,
+		console:   console,
+		opts:      opts,
 		closeFunc: conn.Close,
+		done:      make(chan struct{}),
 		log:       log,
 	}
 
-	ticker := time.NewTicker(10 * time.Second)
+	ticker := time.NewTicker(opts.HeartbeatInterval)
 	go func() {
+		defer ticker.Stop()
+
 		r.sendHeartbeat()
 
-		for range ticker.C {
-			r.sendHeartbeat()
+		for {
+			select {
+			case <-r.done:
+				return
+			case <-ticker.C:
+				r.sendHeartbeat()
+			}
 		}
 	}()
 
-	return &r
+	return &r, nil
 }
 
 func (r *UDPReader) Read() (int, []byte, error) {
-	buffer := make([]byte, 4096)
-	bufLen, _, err := r.conn.ReadFromUDP(buffer)
+	bufLen, buffer, err := r.readEncrypted()
 	if err != nil {
-		return 0, buffer, fmt.Errorf("failed to receive telemetry: %s", err.Error())
+		return 0, buffer, err
 	}
-
-	if len(buffer[:bufLen]) == 0 {
-		return 0, buffer, fmt.Errorf("no data received")
+	if r.opts.Decrypted {
+		return bufLen, buffer[:bufLen], nil
 	}
 
 	decipheredPacket, err := utils.Salsa20Decode(buffer[:bufLen])
@@ -70,22 +133,173 @@ func (r *UDPReader) Read() (int, []byte, error) {
 	return bufLen, decipheredPacket, nil
 }
 
+// readEncrypted receives a packet from the console without deciphering it.
+// Heartbeats are resent with an increasing backoff while the stream is
+// stalled.
+func (r *UDPReader) readEncrypted() (int, []byte, error) {
+	buffer := make([]byte, 4096)
+	for {
+		wait := r.opts.StallTimeout
+		if r.stalled {
+			wait = r.backoff
+		}
+		if err := r.conn.SetReadDeadline(time.Now().Add(wait)); err != nil {
+			r.log.Error().Err(err).Msg("failed to set read deadline")
+		}
+
+		bufLen, _, err := r.conn.ReadFromUDP(buffer)
+		if err != nil {
+			var netErr net.Error
+			if !errors.As(err, &netErr) || !netErr.Timeout() {
+				return 0, buffer, fmt.Errorf("failed to receive telemetry: %w", err)
+			}
+
+			r.sendHeartbeat()
+
+			if r.stalled {
+				r.backoff = min(r.backoff*2, r.opts.MaxBackoff)
+				r.log.Debug().Dur("backoff", r.backoff).Msg("telemetry stream still stalled")
+				continue
+			}
+
+			r.stalled = true
+			r.backoff = r.opts.StallTimeout
+			r.log.Warn().Dur("timeout", wait).Msg("telemetry stream stalled")
+
+			return 0, buffer, fmt.Errorf("%w: no telemetry received for %s", ErrStalled, wait)
+		}
+
+		if len(buffer[:bufLen]) == 0 {
+			return 0, buffer, fmt.Errorf("no data received")
+		}
+
+		if r.stalled {
+			r.stalled = false
+			r.log.Info().Msg("telemetry stream resumed")
+		}
+
+		return bufLen, buffer, nil
+	}
+}
+
+// Close stops the heartbeats and closes the socket, further calls do
+// nothing.
 func (r *UDPReader) Close() error {
-	return r.closeFunc()
+	var err error
+	r.once.Do(func() {
+		close(r.done)
+		err = r.closeFunc()
+	})
+
+	return err
+}
+
+// withDefaults fills in the options that are not set for a console sending
+// from the port.
+func (opts UDPOptions) withDefaults(sendPort int) (UDPOptions, error) {
+	if opts.ReceivePort == 0 {
+		opts.ReceivePort = sendPort + 1
+	}
+	if opts.HeartbeatInterval <= 0 {
+		opts.HeartbeatInterval = DefaultHeartbeatInterval
+	}
+	if opts.StallTimeout <= 0 {
+		opts.StallTimeout = DefaultStallTimeout
+	}
+	if opts.MaxBackoff < opts.StallTimeout {
+		opts.MaxBackoff = max(DefaultMaxBackoff, opts.StallTimeout)
+	}
+	if opts.PacketFormat == "" {
+		opts.PacketFormat = DefaultPacketFormat
+	}
+	if !isPacketFormat(opts.PacketFormat) {
+		return opts, fmt.Errorf("unknown packet format %q", opts.PacketFormat)
+	}
+
+	return opts, nil
+}
+
+// isPacketFormat reports whether the heartbeat payload requests one of the
+// packet formats sent by the console.
+func isPacketFormat(format string) bool {
+	switch format {
+	case "A", "B", "~":
+		return true
+	default:
+		return false
+	}
 }
 
 func (r *UDPReader) sendHeartbeat() {
-	r.log.Debug().Msgf("sending heartbeat to %s:%d", r.address, r.sendPort)
+	r.log.Debug().Msgf("sending heartbeat to %s", r.console)
 
-	_, err := r.conn.WriteToUDP([]byte("A"), &net.UDPAddr{
-		IP:   net.ParseIP(r.address),
-		Port: r.sendPort,
-	})
+	_, err := r.conn.WriteToUDP([]byte(r.opts.PacketFormat), r.console)
 	if err != nil {
-		log.Fatal(err)
+		r.log.Error().Err(err).Msg("failed to send heartbeat")
+	}
+}
+
+// bindAddress returns the local address to listen on, taking the first
+// address of the interface in the same family as the console when only an
+// interface is configured.
+func bindAddress(opts UDPOptions, console net.IP) (*net.UDPAddr, error) {
+	addr := &net.UDPAddr{Port: opts.ReceivePort}
+	if opts.BindAddress != "" {
+		ip, err := net.ResolveIPAddr("ip", opts.BindAddress)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve bind address: %w", err)
+		}
+		addr.IP = ip.IP
+		addr.Zone = ip.Zone
+
+		return addr, nil
 	}
-	err = r.conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+
+	if opts.Interface == "" {
+		return addr, nil
+	}
+
+	iface, err := net.InterfaceByName(opts.Interface)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find interface: %w", err)
+	}
+	addrs, err := iface.Addrs()
 	if err != nil {
-		log.Fatal(err)
+		return nil, fmt.Errorf("failed to list interface addresses: %w", err)
 	}
+
+	var linkLocal *net.UDPAddr
+	for _, a := range addrs {
+		ipNet, ok := a.(*net.IPNet)
+		if !ok || (ipNet.IP.To4() == nil) != (console.To4() == nil) {
+			continue
+		}
+
+		candidate := &net.UDPAddr{IP: ipNet.IP, Port: opts.ReceivePort}
+		if ipNet.IP.IsLinkLocalUnicast() {
+			candidate.Zone = opts.Interface
+			if linkLocal == nil {
+				linkLocal = candidate
+			}
+			// link-local addresses are only used when the console is too
+			if !console.IsLinkLocalUnicast() {
+				continue
+			}
+		}
+
+		return candidate, nil
+	}
+	if linkLocal != nil {
+		return linkLocal, nil
+	}
+
+	return nil, fmt.Errorf("interface %s has no address to reach %s", opts.Interface, console)
+}
+
+func udpNetwork(console net.IP) string {
+	if console.To4() != nil {
+		return "udp4"
+	}
+
+	return "udp6"
 }
@@ -0,0 +1,204 @@
+package telemetrysrc
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/suite"
+)
+
+type NetworkTestSuite struct {
+	suite.Suite
+}
+
+func TestNetworkTestSuite(t *testing.T) {
+	suite.Run(t, new(NetworkTestSuite))
+}
+
+// console listens on a loopback address in place of a PlayStation.
+func (suite *NetworkTestSuite) console(network string, ip net.IP) *net.UDPConn {
+	conn, err := net.ListenUDP(network, &net.UDPAddr{IP: ip})
+	if err != nil {
+		suite.T().Skipf("loopback %s unavailable: %s", network, err)
+	}
+
+	return conn
+}
+
+// freePort returns a local UDP port that is not in use.
+func (suite *NetworkTestSuite) freePort() int {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{})
+	suite.Require().NoError(err)
+	defer conn.Close()
+
+	return conn.LocalAddr().(*net.UDPAddr).Port
+}
+
+func (suite *NetworkTestSuite) heartbeat(console *net.UDPConn) *net.UDPAddr {
+	buffer := make([]byte, 16)
+	suite.Require().NoError(console.SetReadDeadline(time.Now().Add(time.Second)))
+	n, addr, err := console.ReadFromUDP(buffer)
+	suite.Require().NoError(err)
+	suite.Equal("A", string(buffer[:n]))
+
+	return addr
+}
+
+func (suite *NetworkTestSuite) packet() []byte {
+	packet := make([]byte, 296)
+	copy(packet, packetHeader)
+
+	return encryptPacket(packet, 1)
+}
+
+func (suite *NetworkTestSuite) TestOptionsConfigureTheListener() {
+	// Arrange
+	console := suite.console("udp4", net.IPv4(127, 0, 0, 1))
+	defer console.Close()
+	port := console.LocalAddr().(*net.UDPAddr).Port
+	receivePort := suite.freePort()
+
+	// Act
+	reader, err := NewNetworkUDPReader("127.0.0.1", port, UDPOptions{
+		BindAddress: "127.0.0.1",
+		ReceivePort: receivePort,
+	}, zerolog.Nop())
+	suite.Require().NoError(err)
+	defer reader.Close()
+
+	// Assert
+	addr := suite.heartbeat(console)
+	suite.Equal("127.0.0.1", addr.IP.String())
+	suite.Equal(receivePort, addr.Port)
+}
+
+func (suite *NetworkTestSuite) TestIPv6ConsolesAreSupported() {
+	// Arrange
+	console := suite.console("udp6", net.IPv6loopback)
+	defer console.Close()
+	port := console.LocalAddr().(*net.UDPAddr).Port
+
+	reader, err := NewNetworkUDPReader("::1", port, UDPOptions{ReceivePort: suite.freePort()}, zerolog.Nop())
+	suite.Require().NoError(err)
+	defer reader.Close()
+
+	// Act
+	addr := suite.heartbeat(console)
+	_, err = console.WriteToUDP(suite.packet(), addr)
+	suite.Require().NoError(err)
+	bufLen, _, err := reader.Read()
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(296, bufLen)
+}
+
+func (suite *NetworkTestSuite) TestSilentStreamsStallAndResume() {
+	// Arrange
+	console := suite.console("udp4", net.IPv4(127, 0, 0, 1))
+	defer console.Close()
+	port := console.LocalAddr().(*net.UDPAddr).Port
+
+	reader, err := NewNetworkUDPReader("127.0.0.1", port, UDPOptions{
+		ReceivePort:  suite.freePort(),
+		StallTimeout: 50 * time.Millisecond,
+		MaxBackoff:   100 * time.Millisecond,
+	}, zerolog.Nop())
+	suite.Require().NoError(err)
+	defer reader.Close()
+	suite.heartbeat(console)
+
+	// Act
+	_, _, stallErr := reader.Read()
+	addr := suite.heartbeat(console)
+
+	read := make(chan error, 1)
+	go func() {
+		_, _, err := reader.Read()
+		read <- err
+	}()
+
+	// the heartbeat is resent with a growing interval until the stream resumes
+	suite.heartbeat(console)
+	_, err = console.WriteToUDP(suite.packet(), addr)
+	suite.Require().NoError(err)
+
+	// Assert
+	suite.True(errors.Is(stallErr, ErrStalled))
+	suite.NoError(<-read)
+	suite.False(reader.stalled)
+}
+
+func (suite *NetworkTestSuite) TestHeartbeatRequestsThePacketFormat() {
+	// Arrange
+	console := suite.console("udp4", net.IPv4(127, 0, 0, 1))
+	defer console.Close()
+	port := console.LocalAddr().(*net.UDPAddr).Port
+
+	// Act
+	reader, err := NewNetworkUDPReader("127.0.0.1", port, UDPOptions{
+		ReceivePort:  suite.freePort(),
+		PacketFormat: "~",
+	}, zerolog.Nop())
+	suite.Require().NoError(err)
+	defer reader.Close()
+
+	// Assert
+	buffer := make([]byte, 16)
+	suite.Require().NoError(console.SetReadDeadline(time.Now().Add(time.Second)))
+	n, _, err := console.ReadFromUDP(buffer)
+	suite.Require().NoError(err)
+	suite.Equal("~", string(buffer[:n]))
+}
+
+func (suite *NetworkTestSuite) TestDecryptedPacketsAreReadAsTheyAre() {
+	// Arrange
+	console := suite.console("udp4", net.IPv4(127, 0, 0, 1))
+	defer console.Close()
+	port := console.LocalAddr().(*net.UDPAddr).Port
+
+	reader, err := NewNetworkUDPReader("127.0.0.1", port, UDPOptions{
+		ReceivePort: suite.freePort(),
+		Decrypted:   true,
+	}, zerolog.Nop())
+	suite.Require().NoError(err)
+	defer reader.Close()
+
+	packet := make([]byte, 296)
+	copy(packet, packetHeader)
+
+	// Act
+	addr := suite.heartbeat(console)
+	_, err = console.WriteToUDP(packet, addr)
+	suite.Require().NoError(err)
+	bufLen, buffer, err := reader.Read()
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(packet, buffer[:bufLen])
+}
+
+func (suite *NetworkTestSuite) TestClosingTwiceIsSafe() {
+	// Arrange
+	reader, err := NewNetworkUDPReader("127.0.0.1", suite.freePort(), UDPOptions{ReceivePort: suite.freePort()}, zerolog.Nop())
+	suite.Require().NoError(err)
+
+	// Act
+	first := reader.Close()
+	second := reader.Close()
+
+	// Assert
+	suite.NoError(first)
+	suite.NoError(second)
+}
+
+func (suite *NetworkTestSuite) TestUnknownPacketFormatsAreRejected() {
+	// Act
+	_, err := NewNetworkUDPReader("127.0.0.1", 33739, UDPOptions{PacketFormat: "C"}, zerolog.Nop())
+
+	// Assert
+	suite.ErrorContains(err, `unknown packet format "C"`)
+}
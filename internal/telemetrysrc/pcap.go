@@ -0,0 +1,478 @@
+package telemetrysrc
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/vwhitteron/gt-telemetry/internal/utils"
+)
+
+// Ports used by the telemetry stream, the console sends from 33739 to 33740
+var DefaultCapturePorts = []uint16{33739, 33740}
+
+const (
+	pcapMagicMicro   = 0xa1b2c3d4
+	pcapMagicNano    = 0xa1b23c4d
+	pcapngBlockSHB   = 0x0a0d0d0a
+	pcapngBlockIDB   = 0x00000001
+	pcapngBlockPB    = 0x00000002
+	pcapngBlockSPB   = 0x00000003
+	pcapngBlockEPB   = 0x00000006
+	pcapngByteOrder  = 0x1a2b3c4d
+	pcapngOptTSResol = 9
+	maxCaptureBlock  = 16 * 1024 * 1024
+)
+
+// Link layer header types
+const (
+	linkTypeNull     = 0
+	linkTypeEthernet = 1
+	linkTypeRaw      = 101
+	linkTypeLoop     = 108
+	linkTypeLinuxSLL = 113
+	linkTypeIPv4     = 228
+	linkTypeIPv6     = 229
+	linkTypeLinuxSL2 = 276
+)
+
+type capturedPacket struct {
+	timestamp time.Time
+	linkType  uint32
+	data      []byte
+}
+
+type pcapInterface struct {
+	linkType   uint32
+	resolution time.Duration
+	// ticks are finer than a nanosecond when divisor is set
+	divisor uint64
+}
+
+// PcapReader reads the telemetry packets from a pcap or pcapng capture of the
+// UDP stream, deciphering each payload and pacing the packets by their
+// capture timestamps.
+type PcapReader struct {
+	reader     *bufio.Reader
+	next       func() (capturedPacket, error)
+	byteOrder  binary.ByteOrder
+	interfaces []pcapInterface
+	linkType   uint32
+	resolution time.Duration
+	ports      map[uint16]bool
+	realtime   bool
+	lastStamp  time.Time
+	lastRead   time.Time
+	log        zerolog.Logger
+	closer     func() error
+}
+
+func NewPcapReader(content io.Reader, ports []uint16, realtime bool, log zerolog.Logger) (*PcapReader, error) {
+	if len(ports) == 0 {
+		ports = DefaultCapturePorts
+	}
+
+	r := &PcapReader{
+		reader:   bufio.NewReader(content),
+		ports:    map[uint16]bool{},
+		realtime: realtime,
+		log:      log,
+		closer:   func() error { return nil },
+	}
+	for _, port := range ports {
+		r.ports[port] = true
+	}
+
+	magic, err := r.reader.Peek(4)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read capture header: %w", err)
+	}
+
+	switch {
+	case binary.LittleEndian.Uint32(magic) == pcapngBlockSHB:
+		r.next = r.nextPcapng
+	case binary.LittleEndian.Uint32(magic) == pcapMagicMicro || binary.LittleEndian.Uint32(magic) == pcapMagicNano:
+		r.byteOrder = binary.LittleEndian
+		err = r.readPcapHeader()
+	case binary.BigEndian.Uint32(magic) == pcapMagicMicro || binary.BigEndian.Uint32(magic) == pcapMagicNano:
+		r.byteOrder = binary.BigEndian
+		err = r.readPcapHeader()
+	default:
+		return nil, fmt.Errorf("unrecognised capture format")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (r *PcapReader) Read() (int, []byte, error) {
+	for {
+		packet, err := r.next()
+		if err != nil {
+			return 0, nil, err
+		}
+
+		payload, ok := r.udpPayload(packet)
+		if !ok {
+			continue
+		}
+
+		decipheredPacket, err := utils.Salsa20Decode(payload)
+		if err != nil {
+			// heartbeats and unrelated traffic on the same ports
+			r.log.Trace().Err(err).Msg("skipping captured payload")
+			continue
+		}
+
+		r.pace(packet.timestamp)
+
+		return len(decipheredPacket), decipheredPacket, nil
+	}
+}
+
+func (r *PcapReader) Realtime() bool {
+	return r.realtime
+}
+
+func (r *PcapReader) Close() error {
+	return r.closer()
+}
+
+func (r *PcapReader) pace(timestamp time.Time) {
+	defer func() {
+		r.lastStamp = timestamp
+		r.lastRead = time.Now()
+	}()
+
+	if !r.realtime || r.lastStamp.IsZero() {
+		return
+	}
+
+	gap := timestamp.Sub(r.lastStamp)
+	if gap > time.Second {
+		// long pauses in the capture are skipped
+		gap = packetInterval
+	}
+
+	if wait := gap - time.Since(r.lastRead); wait > 0 {
+		time.Sleep(wait)
+	}
+}
+
+func (r *PcapReader) readPcapHeader() error {
+	header := make([]byte, 24)
+	if _, err := io.ReadFull(r.reader, header); err != nil {
+		return fmt.Errorf("failed to read pcap header: %w", err)
+	}
+
+	r.resolution = time.Microsecond
+	if r.byteOrder.Uint32(header[0:4]) == pcapMagicNano {
+		r.resolution = time.Nanosecond
+	}
+	r.linkType = r.byteOrder.Uint32(header[20:24]) & 0x0fffffff
+	r.next = r.nextPcap
+
+	return nil
+}
+
+func (r *PcapReader) nextPcap() (capturedPacket, error) {
+	header := make([]byte, 16)
+	if _, err := io.ReadFull(r.reader, header); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			r.log.Warn().Msg("capture ends with a truncated packet")
+			return capturedPacket{}, io.EOF
+		}
+		return capturedPacket{}, err
+	}
+
+	seconds := r.byteOrder.Uint32(header[0:4])
+	fraction := r.byteOrder.Uint32(header[4:8])
+	length := r.byteOrder.Uint32(header[8:12])
+	if length > maxCaptureBlock {
+		return capturedPacket{}, fmt.Errorf("captured packet length %d is too large", length)
+	}
+
+	data := make([]byte, length)
+	if _, err := io.ReadFull(r.reader, data); err != nil {
+		r.log.Warn().Msg("capture ends with a truncated packet")
+		return capturedPacket{}, io.EOF
+	}
+
+	return capturedPacket{
+		timestamp: time.Unix(int64(seconds), int64(fraction)*int64(r.resolution)),
+		linkType:  r.linkType,
+		data:      data,
+	}, nil
+}
+
+func (r *PcapReader) nextPcapng() (capturedPacket, error) {
+	for {
+		header := make([]byte, 8)
+		if _, err := io.ReadFull(r.reader, header); err != nil {
+			if errors.Is(err, io.ErrUnexpectedEOF) {
+				return capturedPacket{}, io.EOF
+			}
+			return capturedPacket{}, err
+		}
+
+		blockType := binary.LittleEndian.Uint32(header[0:4])
+		if blockType == pcapngBlockSHB {
+			// each section can change the byte order
+			order, err := r.reader.Peek(4)
+			if err != nil {
+				return capturedPacket{}, io.EOF
+			}
+			if binary.LittleEndian.Uint32(order) == pcapngByteOrder {
+				r.byteOrder = binary.LittleEndian
+			} else {
+				r.byteOrder = binary.BigEndian
+			}
+			r.interfaces = nil
+		} else if r.byteOrder == nil {
+			return capturedPacket{}, errors.New("pcapng block found before section header")
+		} else {
+			blockType = r.byteOrder.Uint32(header[0:4])
+		}
+
+		length := r.byteOrder.Uint32(header[4:8])
+		if length < 12 || length > maxCaptureBlock {
+			return capturedPacket{}, fmt.Errorf("invalid pcapng block length %d", length)
+		}
+
+		body := make([]byte, length-8)
+		if _, err := io.ReadFull(r.reader, body); err != nil {
+			r.log.Warn().Msg("capture ends with a truncated block")
+			return capturedPacket{}, io.EOF
+		}
+		// drop the trailing copy of the block length
+		body = body[:len(body)-4]
+
+		switch blockType {
+		case pcapngBlockIDB:
+			r.interfaces = append(r.interfaces, r.parseInterface(body))
+		case pcapngBlockEPB:
+			if packet, ok := r.parseEnhancedPacket(body); ok {
+				return packet, nil
+			}
+		case pcapngBlockPB:
+			if packet, ok := r.parseObsoletePacket(body); ok {
+				return packet, nil
+			}
+		case pcapngBlockSPB:
+			if len(body) >= 4 && len(r.interfaces) > 0 {
+				length := min(int(r.byteOrder.Uint32(body[0:4])), len(body)-4)
+				return capturedPacket{
+					linkType: r.interfaces[0].linkType,
+					data:     body[4 : 4+length],
+				}, nil
+			}
+		}
+	}
+}
+
+func (r *PcapReader) parseInterface(body []byte) pcapInterface {
+	iface := pcapInterface{
+		resolution: time.Microsecond,
+	}
+	if len(body) < 8 {
+		return iface
+	}
+	iface.linkType = uint32(r.byteOrder.Uint16(body[0:2]))
+
+	options := body[8:]
+	for len(options) >= 4 {
+		code := r.byteOrder.Uint16(options[0:2])
+		length := int(r.byteOrder.Uint16(options[2:4]))
+		if code == 0 || len(options) < 4+length {
+			break
+		}
+
+		if code == pcapngOptTSResol && length >= 1 {
+			value := options[4]
+			if value&0x80 != 0 {
+				iface.resolution, iface.divisor = pcapResolution(uint64(1) << (value & 0x7f))
+			} else {
+				iface.resolution, iface.divisor = pcapResolution(uint64(math.Pow10(int(value))))
+			}
+		}
+
+		options = options[4+(length+3)/4*4:]
+	}
+
+	return iface
+}
+
+// pcapResolution converts a number of ticks per second into a tick duration,
+// or a divisor when the ticks are finer than a nanosecond.
+func pcapResolution(ticksPerSecond uint64) (time.Duration, uint64) {
+	if ticksPerSecond == 0 {
+		return time.Microsecond, 0
+	}
+	if ticksPerSecond > uint64(time.Second) {
+		return 0, ticksPerSecond / uint64(time.Second)
+	}
+
+	return time.Second / time.Duration(ticksPerSecond), 0
+}
+
+func (r *PcapReader) interfaceTimestamp(id uint32, high uint32, low uint32) (pcapInterface, time.Time, bool) {
+	if int(id) >= len(r.interfaces) {
+		return pcapInterface{}, time.Time{}, false
+	}
+	iface := r.interfaces[id]
+
+	ticks := uint64(high)<<32 | uint64(low)
+	if iface.divisor > 0 {
+		return iface, time.Unix(0, int64(ticks/iface.divisor)), true
+	}
+	if iface.resolution == time.Nanosecond {
+		return iface, time.Unix(0, int64(ticks)), true
+	}
+	perSecond := uint64(time.Second / iface.resolution)
+
+	return iface, time.Unix(int64(ticks/perSecond), int64(ticks%perSecond)*int64(iface.resolution)), true
+}
+
+func (r *PcapReader) parseEnhancedPacket(body []byte) (capturedPacket, bool) {
+	if len(body) < 20 {
+		return capturedPacket{}, false
+	}
+
+	iface, timestamp, ok := r.interfaceTimestamp(r.byteOrder.Uint32(body[0:4]), r.byteOrder.Uint32(body[4:8]), r.byteOrder.Uint32(body[8:12]))
+	if !ok {
+		return capturedPacket{}, false
+	}
+
+	length := int(r.byteOrder.Uint32(body[12:16]))
+	if 20+length > len(body) {
+		return capturedPacket{}, false
+	}
+
+	return capturedPacket{
+		timestamp: timestamp,
+		linkType:  iface.linkType,
+		data:      body[20 : 20+length],
+	}, true
+}
+
+func (r *PcapReader) parseObsoletePacket(body []byte) (capturedPacket, bool) {
+	if len(body) < 20 {
+		return capturedPacket{}, false
+	}
+
+	iface, timestamp, ok := r.interfaceTimestamp(uint32(r.byteOrder.Uint16(body[0:2])), r.byteOrder.Uint32(body[4:8]), r.byteOrder.Uint32(body[8:12]))
+	if !ok {
+		return capturedPacket{}, false
+	}
+
+	length := int(r.byteOrder.Uint32(body[12:16]))
+	if 20+length > len(body) {
+		return capturedPacket{}, false
+	}
+
+	return capturedPacket{
+		timestamp: timestamp,
+		linkType:  iface.linkType,
+		data:      body[20 : 20+length],
+	}, true
+}
+
+// udpPayload strips the link, network and transport headers from a captured
+// frame, returning the payload of UDP datagrams on the telemetry ports.
+func (r *PcapReader) udpPayload(packet capturedPacket) ([]byte, bool) {
+	data := packet.data
+	var etherType uint16
+
+	switch packet.linkType {
+	case linkTypeEthernet:
+		if len(data) < 14 {
+			return nil, false
+		}
+		etherType = binary.BigEndian.Uint16(data[12:14])
+		data = data[14:]
+		// VLAN and QinQ tags
+		for (etherType == 0x8100 || etherType == 0x88a8) && len(data) >= 4 {
+			etherType = binary.BigEndian.Uint16(data[2:4])
+			data = data[4:]
+		}
+	case linkTypeLinuxSLL:
+		if len(data) < 16 {
+			return nil, false
+		}
+		etherType = binary.BigEndian.Uint16(data[14:16])
+		data = data[16:]
+	case linkTypeLinuxSL2:
+		if len(data) < 20 {
+			return nil, false
+		}
+		etherType = binary.BigEndian.Uint16(data[0:2])
+		data = data[20:]
+	case linkTypeNull, linkTypeLoop:
+		if len(data) < 4 {
+			return nil, false
+		}
+		data = data[4:]
+	case linkTypeRaw, linkTypeIPv4, linkTypeIPv6:
+	default:
+		return nil, false
+	}
+
+	if etherType == 0 && len(data) > 0 {
+		// the IP version is taken from the packet when there is no ether type
+		switch data[0] >> 4 {
+		case 4:
+			etherType = 0x0800
+		case 6:
+			etherType = 0x86dd
+		}
+	}
+
+	var transport []byte
+	switch etherType {
+	case 0x0800:
+		if len(data) < 20 {
+			return nil, false
+		}
+		headerLen := int(data[0]&0x0f) * 4
+		totalLen := int(binary.BigEndian.Uint16(data[2:4]))
+		fragment := binary.BigEndian.Uint16(data[6:8])
+		if data[9] != 17 || fragment&0x3fff != 0 || headerLen < 20 || len(data) < headerLen {
+			return nil, false
+		}
+		if totalLen >= headerLen && totalLen < len(data) {
+			data = data[:totalLen]
+		}
+		transport = data[headerLen:]
+	case 0x86dd:
+		if len(data) < 40 || data[6] != 17 {
+			return nil, false
+		}
+		transport = data[40:]
+	default:
+		return nil, false
+	}
+
+	if len(transport) < 8 {
+		return nil, false
+	}
+
+	srcPort := binary.BigEndian.Uint16(transport[0:2])
+	dstPort := binary.BigEndian.Uint16(transport[2:4])
+	if !r.ports[srcPort] && !r.ports[dstPort] {
+		return nil, false
+	}
+
+	payload := transport[8:]
+	if length := int(binary.BigEndian.Uint16(transport[4:6])); length >= 8 && length-8 < len(payload) {
+		payload = payload[:length-8]
+	}
+
+	return payload, true
+}
@@ -0,0 +1,234 @@
+package telemetrysrc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/suite"
+	"github.com/vwhitteron/gt-telemetry/internal/utils"
+)
+
+type PcapTestSuite struct {
+	suite.Suite
+	packets [][]byte
+}
+
+func TestPcapTestSuite(t *testing.T) {
+	suite.Run(t, new(PcapTestSuite))
+}
+
+func (suite *PcapTestSuite) SetupTest() {
+	suite.packets = nil
+	for seq := range 3 {
+		packet := make([]byte, 296)
+		copy(packet, packetHeader)
+		binary.LittleEndian.PutUint32(packet[0x70:], uint32(seq))
+		suite.packets = append(suite.packets, packet)
+	}
+}
+
+func encryptPacket(packet []byte, iv uint32) []byte {
+	encrypted, _ := utils.Salsa20Encode(packet, iv)
+
+	return encrypted
+}
+
+func udpDatagram(srcPort uint16, dstPort uint16, payload []byte) []byte {
+	datagram := binary.BigEndian.AppendUint16(nil, srcPort)
+	datagram = binary.BigEndian.AppendUint16(datagram, dstPort)
+	datagram = binary.BigEndian.AppendUint16(datagram, uint16(8+len(payload)))
+	datagram = binary.BigEndian.AppendUint16(datagram, 0)
+
+	return append(datagram, payload...)
+}
+
+func ipv4Ethernet(datagram []byte) []byte {
+	frame := make([]byte, 12)
+	frame = binary.BigEndian.AppendUint16(frame, 0x0800)
+
+	ip := []byte{0x45, 0, 0, 0, 0, 0, 0x40, 0, 64, 17, 0, 0, 192, 168, 1, 20, 192, 168, 1, 30}
+	binary.BigEndian.PutUint16(ip[2:], uint16(20+len(datagram)))
+
+	return append(append(frame, ip...), datagram...)
+}
+
+func ipv6VLANEthernet(datagram []byte) []byte {
+	frame := make([]byte, 12)
+	frame = binary.BigEndian.AppendUint16(frame, 0x8100)
+	frame = append(frame, 0, 10)
+	frame = binary.BigEndian.AppendUint16(frame, 0x86dd)
+
+	ip := make([]byte, 40)
+	ip[0] = 0x60
+	binary.BigEndian.PutUint16(ip[4:], uint16(len(datagram)))
+	ip[6] = 17
+
+	return append(append(frame, ip...), datagram...)
+}
+
+func classicPcap(frames [][]byte, start time.Time, gap time.Duration) []byte {
+	capture := binary.LittleEndian.AppendUint32(nil, pcapMagicMicro)
+	capture = binary.LittleEndian.AppendUint16(capture, 2)
+	capture = binary.LittleEndian.AppendUint16(capture, 4)
+	capture = append(capture, make([]byte, 8)...)
+	capture = binary.LittleEndian.AppendUint32(capture, 65535)
+	capture = binary.LittleEndian.AppendUint32(capture, linkTypeEthernet)
+
+	for i, frame := range frames {
+		stamp := start.Add(time.Duration(i) * gap)
+		capture = binary.LittleEndian.AppendUint32(capture, uint32(stamp.Unix()))
+		capture = binary.LittleEndian.AppendUint32(capture, uint32(stamp.Nanosecond()/1000))
+		capture = binary.LittleEndian.AppendUint32(capture, uint32(len(frame)))
+		capture = binary.LittleEndian.AppendUint32(capture, uint32(len(frame)))
+		capture = append(capture, frame...)
+	}
+
+	return capture
+}
+
+func pcapngBlock(blockType uint32, body []byte) []byte {
+	for len(body)%4 != 0 {
+		body = append(body, 0)
+	}
+
+	block := binary.LittleEndian.AppendUint32(nil, blockType)
+	block = binary.LittleEndian.AppendUint32(block, uint32(12+len(body)))
+	block = append(block, body...)
+
+	return binary.LittleEndian.AppendUint32(block, uint32(12+len(body)))
+}
+
+func pcapng(frames [][]byte, start time.Time, gap time.Duration) []byte {
+	shb := binary.LittleEndian.AppendUint32(nil, pcapngByteOrder)
+	shb = binary.LittleEndian.AppendUint16(shb, 1)
+	shb = binary.LittleEndian.AppendUint16(shb, 0)
+	shb = binary.LittleEndian.AppendUint64(shb, ^uint64(0))
+	capture := pcapngBlock(pcapngBlockSHB, shb)
+
+	// nanosecond timestamps
+	idb := binary.LittleEndian.AppendUint16(nil, linkTypeEthernet)
+	idb = binary.LittleEndian.AppendUint16(idb, 0)
+	idb = binary.LittleEndian.AppendUint32(idb, 65535)
+	idb = binary.LittleEndian.AppendUint16(idb, pcapngOptTSResol)
+	idb = binary.LittleEndian.AppendUint16(idb, 1)
+	idb = append(idb, 9, 0, 0, 0)
+	idb = append(idb, 0, 0, 0, 0)
+	capture = append(capture, pcapngBlock(pcapngBlockIDB, idb)...)
+
+	for i, frame := range frames {
+		stamp := uint64(start.Add(time.Duration(i) * gap).UnixNano())
+		epb := binary.LittleEndian.AppendUint32(nil, 0)
+		epb = binary.LittleEndian.AppendUint32(epb, uint32(stamp>>32))
+		epb = binary.LittleEndian.AppendUint32(epb, uint32(stamp))
+		epb = binary.LittleEndian.AppendUint32(epb, uint32(len(frame)))
+		epb = binary.LittleEndian.AppendUint32(epb, uint32(len(frame)))
+		epb = append(epb, frame...)
+		capture = append(capture, pcapngBlock(pcapngBlockEPB, epb)...)
+	}
+
+	return capture
+}
+
+func (suite *PcapTestSuite) readAll(reader *PcapReader) [][]byte {
+	packets := [][]byte{}
+	for {
+		bufLen, packet, err := reader.Read()
+		if err != nil {
+			suite.Require().ErrorContains(err, "EOF")
+			return packets
+		}
+		packets = append(packets, packet[:bufLen])
+	}
+}
+
+func (suite *PcapTestSuite) assertPackets(packets [][]byte) {
+	suite.Require().Len(packets, len(suite.packets))
+	for i, packet := range packets {
+		// the cipher seed is not restored when deciphering
+		suite.Equal(suite.packets[i][:0x40], packet[:0x40])
+		suite.Equal(suite.packets[i][0x44:], packet[0x44:])
+	}
+}
+
+func (suite *PcapTestSuite) TestClassicCapturesAreFilteredAndDeciphered() {
+	// Arrange
+	frames := [][]byte{
+		ipv4Ethernet(udpDatagram(33740, 33739, []byte("A"))),
+		ipv4Ethernet(udpDatagram(5353, 5353, encryptPacket(suite.packets[0], 1))),
+	}
+	for i, packet := range suite.packets {
+		frames = append(frames, ipv4Ethernet(udpDatagram(33739, 33740, encryptPacket(packet, uint32(100+i)))))
+	}
+	capture := classicPcap(frames, time.Unix(1700000000, 0), time.Millisecond)
+
+	// Act
+	reader, err := NewPcapReader(bytes.NewReader(capture), nil, false, zerolog.Nop())
+	suite.Require().NoError(err)
+
+	// Assert
+	suite.assertPackets(suite.readAll(reader))
+}
+
+func (suite *PcapTestSuite) TestPcapngCapturesWithVLANTaggedIPv6AreRead() {
+	// Arrange
+	frames := [][]byte{}
+	for i, packet := range suite.packets {
+		frames = append(frames, ipv6VLANEthernet(udpDatagram(33739, 33740, encryptPacket(packet, uint32(7+i)))))
+	}
+	capture := pcapng(frames, time.Unix(1700000000, 0), time.Millisecond)
+
+	// Act
+	reader, err := NewPcapReader(bytes.NewReader(capture), nil, false, zerolog.Nop())
+	suite.Require().NoError(err)
+
+	// Assert
+	suite.assertPackets(suite.readAll(reader))
+}
+
+func (suite *PcapTestSuite) TestPacketsArePacedByCaptureTimestamps() {
+	// Arrange
+	frames := [][]byte{}
+	for i, packet := range suite.packets {
+		frames = append(frames, ipv4Ethernet(udpDatagram(33739, 33740, encryptPacket(packet, uint32(i)))))
+	}
+	capture := pcapng(frames, time.Unix(1700000000, 0), 40*time.Millisecond)
+	reader, err := NewPcapReader(bytes.NewReader(capture), nil, true, zerolog.Nop())
+	suite.Require().NoError(err)
+
+	// Act
+	start := time.Now()
+	packets := suite.readAll(reader)
+
+	// Assert
+	suite.Len(packets, 3)
+	suite.GreaterOrEqual(time.Since(start), 80*time.Millisecond)
+}
+
+func (suite *PcapTestSuite) TestCustomPortsAreFiltered() {
+	// Arrange
+	frames := [][]byte{
+		ipv4Ethernet(udpDatagram(33739, 33740, encryptPacket(suite.packets[0], 1))),
+		ipv4Ethernet(udpDatagram(40000, 40001, encryptPacket(suite.packets[1], 2))),
+	}
+	capture := classicPcap(frames, time.Unix(1700000000, 0), time.Millisecond)
+
+	// Act
+	reader, err := NewPcapReader(bytes.NewReader(capture), []uint16{40001}, false, zerolog.Nop())
+	suite.Require().NoError(err)
+	packets := suite.readAll(reader)
+
+	// Assert
+	suite.Require().Len(packets, 1)
+	suite.Equal(suite.packets[1][0x70:0x74], packets[0][0x70:0x74])
+}
+
+func (suite *PcapTestSuite) TestUnknownFormatsAreRejected() {
+	// Act
+	_, err := NewPcapReader(bytes.NewReader([]byte("not a capture file")), nil, false, zerolog.Nop())
+
+	// Assert
+	suite.EqualError(err, "unrecognised capture format")
+}
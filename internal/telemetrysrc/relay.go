@@ -0,0 +1,208 @@
+package telemetrysrc
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/vwhitteron/gt-telemetry/internal/utils"
+)
+
+const (
+	DefaultRelayListenAddress     = ":33739"
+	DefaultRelaySubscriberTimeout = 30 * time.Second
+)
+
+type RelayOptions struct {
+	// ListenAddress receives heartbeats from downstream subscribers.
+	ListenAddress string
+	// SubscriberTimeout is how long a subscriber keeps receiving packets
+	// after its last heartbeat.
+	SubscriberTimeout time.Duration
+	// Decrypted forwards deciphered packets instead of the packets as they
+	// were received from the console. Subscribers using this library read
+	// them with a udp:// source with decrypted=true.
+	Decrypted bool
+	// Console configures the connection to the console.
+	Console UDPOptions
+}
+
+// RelayReader owns the heartbeat to the console and forwards every packet it
+// receives to the subscribers that have sent a heartbeat to the relay.
+// Subscribers register in the same way as they would with the console so an
+// unmodified client can be pointed at the relay instead.
+type RelayReader struct {
+	console     *UDPReader
+	listener    *net.UDPConn
+	opts        RelayOptions
+	log         zerolog.Logger
+	mu          sync.Mutex
+	subscribers map[string]*relaySubscriber
+	once        sync.Once
+}
+
+type relaySubscriber struct {
+	addr     *net.UDPAddr
+	lastSeen time.Time
+}
+
+func NewRelayReader(host string, sendPort int, opts RelayOptions, log zerolog.Logger) (*RelayReader, error) {
+	if opts.ListenAddress == "" {
+		opts.ListenAddress = DefaultRelayListenAddress
+	}
+
+	addr, err := net.ResolveUDPAddr("udp", opts.ListenAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve relay listen address: %w", err)
+	}
+
+	listener, err := net.ListenUDP("udp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup relay listener: %w", err)
+	}
+
+	console, err := NewNetworkUDPReader(host, sendPort, opts.Console, log)
+	if err != nil {
+		listener.Close()
+		return nil, err
+	}
+
+	r := newRelay(listener, opts, log)
+	r.console = console
+
+	return r, nil
+}
+
+func newRelay(listener *net.UDPConn, opts RelayOptions, log zerolog.Logger) *RelayReader {
+	if opts.SubscriberTimeout <= 0 {
+		opts.SubscriberTimeout = DefaultRelaySubscriberTimeout
+	}
+
+	r := &RelayReader{
+		listener:    listener,
+		opts:        opts,
+		log:         log,
+		subscribers: map[string]*relaySubscriber{},
+	}
+
+	go r.receiveHeartbeats()
+
+	return r
+}
+
+func (r *RelayReader) Read() (int, []byte, error) {
+	bufLen, buffer, err := r.console.readEncrypted()
+	if err != nil {
+		return 0, buffer, err
+	}
+
+	decipheredPacket, err := utils.Salsa20Decode(buffer[:bufLen])
+	if err != nil {
+		return 0, buffer, fmt.Errorf("failed to decipher telemetry: %s", err.Error())
+	}
+
+	if r.opts.Decrypted {
+		r.forward(decipheredPacket)
+	} else {
+		r.forward(buffer[:bufLen])
+	}
+
+	return bufLen, decipheredPacket, nil
+}
+
+// Subscribers returns the number of subscribers currently receiving packets.
+func (r *RelayReader) Subscribers() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.subscribers)
+}
+
+func (r *RelayReader) Close() error {
+	var err error
+	r.once.Do(func() {
+		err = r.listener.Close()
+		if r.console != nil {
+			if consoleErr := r.console.Close(); err == nil {
+				err = consoleErr
+			}
+		}
+	})
+
+	return err
+}
+
+func (r *RelayReader) receiveHeartbeats() {
+	buffer := make([]byte, 64)
+	for {
+		n, addr, err := r.listener.ReadFromUDP(buffer)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			r.log.Debug().Err(err).Msg("failed to receive subscriber heartbeat")
+			continue
+		}
+		if !isPacketFormat(string(buffer[:n])) || r.isOwnAddress(addr) {
+			continue
+		}
+
+		r.mu.Lock()
+		if _, ok := r.subscribers[addr.String()]; !ok {
+			r.log.Info().Str("subscriber", addr.String()).Msg("relay subscriber connected")
+		}
+		r.subscribers[addr.String()] = &relaySubscriber{
+			addr:     addr,
+			lastSeen: time.Now(),
+		}
+		r.mu.Unlock()
+	}
+}
+
+// isOwnAddress reports whether a datagram was sent by the relay itself, such
+// as its own heartbeat to the console coming back on a broadcast address.
+func (r *RelayReader) isOwnAddress(addr *net.UDPAddr) bool {
+	ports := []int{r.listener.LocalAddr().(*net.UDPAddr).Port}
+	if r.console != nil {
+		ports = append(ports, r.console.conn.LocalAddr().(*net.UDPAddr).Port)
+	}
+	if !slices.Contains(ports, addr.Port) {
+		return false
+	}
+	if addr.IP.IsLoopback() {
+		return true
+	}
+
+	local, err := net.InterfaceAddrs()
+	if err != nil {
+		return false
+	}
+	for _, a := range local {
+		if ipNet, ok := a.(*net.IPNet); ok && ipNet.IP.Equal(addr.IP) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *RelayReader) forward(packet []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key, subscriber := range r.subscribers {
+		if time.Since(subscriber.lastSeen) > r.opts.SubscriberTimeout {
+			r.log.Info().Str("subscriber", key).Msg("relay subscriber expired")
+			delete(r.subscribers, key)
+			continue
+		}
+
+		if _, err := r.listener.WriteToUDP(packet, subscriber.addr); err != nil {
+			r.log.Debug().Err(err).Str("subscriber", key).Msg("failed to forward telemetry")
+		}
+	}
+}
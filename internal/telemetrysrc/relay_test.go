@@ -0,0 +1,134 @@
+package telemetrysrc
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/suite"
+)
+
+type RelayTestSuite struct {
+	suite.Suite
+	relay *RelayReader
+}
+
+func TestRelayTestSuite(t *testing.T) {
+	suite.Run(t, new(RelayTestSuite))
+}
+
+func (suite *RelayTestSuite) SetupTest() {
+	listener, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	suite.Require().NoError(err)
+
+	suite.relay = newRelay(listener, RelayOptions{SubscriberTimeout: 200 * time.Millisecond}, zerolog.Nop())
+}
+
+func (suite *RelayTestSuite) TearDownTest() {
+	suite.relay.Close()
+}
+
+func (suite *RelayTestSuite) subscribe() *net.UDPConn {
+	conn, err := net.DialUDP("udp", nil, suite.relay.listener.LocalAddr().(*net.UDPAddr))
+	suite.Require().NoError(err)
+
+	_, err = conn.Write([]byte("A"))
+	suite.Require().NoError(err)
+
+	return conn
+}
+
+func (suite *RelayTestSuite) TestPacketsAreForwardedToEverySubscriber() {
+	// Arrange
+	first := suite.subscribe()
+	defer first.Close()
+	second := suite.subscribe()
+	defer second.Close()
+	suite.Eventually(func() bool { return suite.relay.Subscribers() == 2 }, time.Second, time.Millisecond)
+
+	// Act
+	suite.relay.forward([]byte("packet"))
+
+	// Assert
+	for _, conn := range []*net.UDPConn{first, second} {
+		buffer := make([]byte, 16)
+		suite.Require().NoError(conn.SetReadDeadline(time.Now().Add(time.Second)))
+		n, err := conn.Read(buffer)
+		suite.Require().NoError(err)
+		suite.Equal("packet", string(buffer[:n]))
+	}
+}
+
+func (suite *RelayTestSuite) TestSubscribersExpireWithoutHeartbeats() {
+	// Arrange
+	conn := suite.subscribe()
+	defer conn.Close()
+	suite.Eventually(func() bool { return suite.relay.Subscribers() == 1 }, time.Second, time.Millisecond)
+
+	// Act
+	time.Sleep(250 * time.Millisecond)
+	suite.relay.forward([]byte("packet"))
+
+	// Assert
+	suite.Equal(0, suite.relay.Subscribers())
+}
+
+func (suite *RelayTestSuite) TestRepeatedHeartbeatsRegisterOneSubscriber() {
+	// Arrange
+	conn := suite.subscribe()
+	defer conn.Close()
+
+	// Act
+	_, err := conn.Write([]byte("A"))
+	suite.Require().NoError(err)
+	time.Sleep(20 * time.Millisecond)
+
+	// Assert
+	suite.Equal(1, suite.relay.Subscribers())
+}
+
+func (suite *RelayTestSuite) TestOnlyHeartbeatsFromOtherHostsRegisterSubscribers() {
+	testCases := map[string]struct {
+		send func() error
+	}{
+		"unknown payload": {
+			send: func() error {
+				conn, err := net.DialUDP("udp", nil, suite.relay.listener.LocalAddr().(*net.UDPAddr))
+				if err != nil {
+					return err
+				}
+				defer conn.Close()
+				_, err = conn.Write([]byte("hello"))
+				return err
+			},
+		},
+		"own heartbeat": {
+			send: func() error {
+				_, err := suite.relay.listener.WriteToUDP([]byte("A"), suite.relay.listener.LocalAddr().(*net.UDPAddr))
+				return err
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		suite.Run(name, func() {
+			// Act
+			suite.Require().NoError(tc.send())
+			time.Sleep(20 * time.Millisecond)
+
+			// Assert
+			suite.Equal(0, suite.relay.Subscribers())
+		})
+	}
+}
+
+func (suite *RelayTestSuite) TestClosingTwiceIsSafe() {
+	// Act
+	first := suite.relay.Close()
+	second := suite.relay.Close()
+
+	// Assert
+	suite.NoError(first)
+	suite.NoError(second)
+}
@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type FilesTestSuite struct {
+	suite.Suite
+}
+
+func TestFilesTestSuite(t *testing.T) {
+	suite.Run(t, new(FilesTestSuite))
+}
+
+func (suite *FilesTestSuite) TestWriteFileAtomicReplacesTheFile() {
+	// Arrange
+	dir := suite.T().TempDir()
+	file := filepath.Join(dir, "store.json")
+	suite.Require().NoError(os.WriteFile(file, []byte("old"), 0o644))
+
+	// Act
+	err := WriteFileAtomic(file, []byte("new"))
+
+	// Assert
+	suite.Require().NoError(err)
+	data, err := os.ReadFile(file)
+	suite.Require().NoError(err)
+	suite.Equal("new", string(data))
+	entries, err := os.ReadDir(dir)
+	suite.Require().NoError(err)
+	suite.Len(entries, 1)
+}
+
+func (suite *FilesTestSuite) TestWriteFileAtomicKeepsTheFileWhenItFails() {
+	// Arrange
+	file := filepath.Join(suite.T().TempDir(), "missing", "store.json")
+
+	// Act
+	err := WriteFileAtomic(file, []byte("new"))
+
+	// Assert
+	suite.Error(err)
+	suite.NoFileExists(file)
+}
@@ -9,10 +9,32 @@ import (
 
 const cipherKey string = "Simulator Interface Packet GT7 ver 0.0"
 
+// Sizes of the packet formats requested with the A, B and ~ heartbeats. Each
+// format extends the previous one and is ciphered with a different nonce.
+const (
+	PacketSizeA     = 0x128
+	PacketSizeB     = 0x13C
+	PacketSizeTilde = 0x158
+)
+
+// nonceKey returns the value the seed is combined with to make the nonce of
+// a packet, which is chosen by the packet format.
+func nonceKey(size int) uint32 {
+	switch size {
+	case PacketSizeB:
+		return 0xDEADBEEF
+	case PacketSizeTilde:
+		return 0x55FABB4F
+	default:
+		return 0xDEADBEAF
+	}
+}
+
 func Salsa20Decode(dat []byte) ([]byte, error) {
 	datLen := len(dat)
-	if datLen < 32 {
-		return nil, fmt.Errorf("salsa20 data is too short: %d < 32", datLen)
+	// the seed of the nonce is stored at 0x40
+	if datLen < 0x44 {
+		return nil, fmt.Errorf("salsa20 data is too short: %d < %d", datLen, 0x44)
 	}
 
 	key := [32]byte{}
@@ -20,7 +42,7 @@ func Salsa20Decode(dat []byte) ([]byte, error) {
 
 	nonce := make([]byte, 8)
 	iv := binary.LittleEndian.Uint32(dat[0x40:0x44])
-	binary.LittleEndian.PutUint32(nonce, iv^0xDEADBEAF)
+	binary.LittleEndian.PutUint32(nonce, iv^nonceKey(datLen))
 	binary.LittleEndian.PutUint32(nonce[4:], iv)
 
 	ddata := make([]byte, len(dat))
@@ -32,3 +54,25 @@ func Salsa20Decode(dat []byte) ([]byte, error) {
 
 	return ddata, nil
 }
+
+// Salsa20Encode ciphers a deciphered packet in the same way as the console,
+// storing the seed in the packet so it can be read back by Salsa20Decode.
+func Salsa20Encode(dat []byte, iv uint32) ([]byte, error) {
+	datLen := len(dat)
+	if datLen < 0x44 {
+		return nil, fmt.Errorf("salsa20 data is too short: %d < %d", datLen, 0x44)
+	}
+
+	key := [32]byte{}
+	copy(key[:], cipherKey)
+
+	nonce := make([]byte, 8)
+	binary.LittleEndian.PutUint32(nonce, iv^nonceKey(datLen))
+	binary.LittleEndian.PutUint32(nonce[4:], iv)
+
+	edata := make([]byte, len(dat))
+	salsa20.XORKeyStream(edata, dat, nonce, &key)
+	binary.LittleEndian.PutUint32(edata[0x40:0x44], iv)
+
+	return edata, nil
+}
@@ -71,20 +71,21 @@ func (suite *Salsa20TestSuite) TestEmptySalsa20ContentReturnsNilWithError() {
 
 	// Assert
 	suite.Nil(gotValue)
-	suite.ErrorContains(err, "salsa20 data is too short: 0 < 32")
+	suite.ErrorContains(err, "salsa20 data is too short: 0 < 68")
 }
 
 func (suite *Salsa20TestSuite) TestTruncatedSalsa20ContentReturnsNilWithError() {
 	// Arrange
-	wantLen := 31
-	encodedValue := bytes.Repeat([]byte{0x00}, wantLen)
+	for _, wantLen := range []int{31, 0x43} {
+		encodedValue := bytes.Repeat([]byte{0x00}, wantLen)
 
-	// Act
-	gotValue, err := Salsa20Decode(encodedValue)
+		// Act
+		gotValue, err := Salsa20Decode(encodedValue)
 
-	// Assert
-	suite.Nil(gotValue)
-	suite.ErrorContains(err, "salsa20 data is too short: "+strconv.Itoa(wantLen)+" < 32")
+		// Assert
+		suite.Nil(gotValue)
+		suite.ErrorContains(err, "salsa20 data is too short: "+strconv.Itoa(wantLen)+" < 68")
+	}
 }
 
 func (suite *Salsa20TestSuite) TestInvalidSalsa20MagicValueReturnsNilWithError() {
@@ -111,3 +112,60 @@ func TestValidSalsa20ContentReturnsDecodedData(t *testing.T) {
 	// Assert
 	assert.Equal(t, wantValue, gotValue[0:4])
 }
+
+func (suite *Salsa20TestSuite) TestEncodedContentCanBeDecoded() {
+	// Arrange
+	packet := bytes.Repeat([]byte{0x5a}, standardPacketSize)
+	copy(packet, magicPacketHeader)
+
+	// Act
+	encodedValue, err := Salsa20Encode(packet, 0x12345678)
+	suite.Require().NoError(err)
+	gotValue, err := Salsa20Decode(encodedValue)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.NotEqual(packet, encodedValue)
+	suite.Equal(packet[:0x40], gotValue[:0x40])
+	suite.Equal(packet[0x44:], gotValue[0x44:])
+}
+
+func (suite *Salsa20TestSuite) TestEveryPacketFormatCanBeDecoded() {
+	testCases := map[string]int{
+		"format A": PacketSizeA,
+		"format B": PacketSizeB,
+		"format ~": PacketSizeTilde,
+	}
+
+	for name, size := range testCases {
+		suite.Run(name, func() {
+			// Arrange
+			packet := bytes.Repeat([]byte{0x5a}, size)
+			copy(packet, magicPacketHeader)
+
+			// Act
+			encodedValue, err := Salsa20Encode(packet, 0x12345678)
+			suite.Require().NoError(err)
+			gotValue, err := Salsa20Decode(encodedValue)
+
+			// Assert
+			suite.Require().NoError(err)
+			suite.Equal(packet[0x44:], gotValue[0x44:])
+		})
+	}
+}
+
+func (suite *Salsa20TestSuite) TestPacketFormatsUseDifferentNonces() {
+	// Arrange
+	packet := bytes.Repeat([]byte{0x5a}, PacketSizeTilde)
+	copy(packet, magicPacketHeader)
+
+	// Act
+	extended, err := Salsa20Encode(packet, 0x12345678)
+	suite.Require().NoError(err)
+	standard, err := Salsa20Encode(packet[:PacketSizeA], 0x12345678)
+	suite.Require().NoError(err)
+
+	// Assert
+	suite.NotEqual(standard[:4], extended[:4])
+}
@@ -18,6 +18,10 @@ func CelsiusToFahrenheit(c float32) float32 {
 	return c*1.8 + 32
 }
 
+func CelsiusToKelvin(c float32) float32 {
+	return c + 273.15
+}
+
 func MetersToFeet(m float32) float32 {
 	return m * 3.28084
 }
@@ -34,6 +38,10 @@ func MetersPerSecondToKilometersPerHour(mps float32) float32 {
 	return mps * 3.6
 }
 
+func KilometersPerHourToMetersPerSecond(kph float32) float32 {
+	return kph / 3.6
+}
+
 func MetersPerSecondToMilesPerHour(mps float32) float32 {
 	return mps / 0.44704
 }
@@ -41,3 +49,31 @@ func MetersPerSecondToMilesPerHour(mps float32) float32 {
 func RadiansPerSecondToRevolutionsPerMinute(rps float32) float32 {
 	return rps * (60 / (2 * math.Pi))
 }
+
+func LitresToUSGallons(l float32) float32 {
+	return l / 3.785412
+}
+
+func LitresToImperialGallons(l float32) float32 {
+	return l / 4.54609
+}
+
+func KilowattsToHorsepower(kw float32) float32 {
+	return kw / 0.7456999
+}
+
+func KilowattsToMetricHorsepower(kw float32) float32 {
+	return kw / 0.7354988
+}
+
+func NewtonMetersToPoundFeet(nm float32) float32 {
+	return nm / 1.355818
+}
+
+func NewtonMetersToKilogramForceMeters(nm float32) float32 {
+	return nm / 9.80665
+}
+
+func KilogramsToPounds(kg float32) float32 {
+	return kg / 0.4535924
+}
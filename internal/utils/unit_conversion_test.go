@@ -30,12 +30,21 @@ func (suite *UnitConversionTestSuite) TestUnitConversionFunctionsReturnCorrectVa
 		{BarToInHg, 1, 29.52998},
 		{BarToKPA, 1, 100},
 		{CelsiusToFahrenheit, 1, 33.8},
+		{CelsiusToKelvin, 1, 274.15},
 		{MetersToFeet, 1, 3.28084},
 		{MetersToInches, 1, 39.3701},
 		{MetersToMillimeters, 1, 1000},
 		{MetersPerSecondToKilometersPerHour, 1, 3.6},
+		{KilometersPerHourToMetersPerSecond, 36, 10},
 		{MetersPerSecondToMilesPerHour, 1, 2.2369363},
 		{RadiansPerSecondToRevolutionsPerMinute, 1, 9.549296},
+		{LitresToUSGallons, 1, 0.26417202},
+		{LitresToImperialGallons, 1, 0.21996924},
+		{KilowattsToHorsepower, 1, 1.341022},
+		{KilowattsToMetricHorsepower, 1, 1.3596215},
+		{NewtonMetersToPoundFeet, 1, 0.7375621},
+		{NewtonMetersToKilogramForceMeters, 1, 0.10197162},
+		{KilogramsToPounds, 1, 2.2046225},
 	}
 
 	for _, tc := range testCases {
@@ -0,0 +1,176 @@
+package telemetry
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const metricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Channels published as live gauges when no channels are configured
+var defaultMetricsChannels = []string{
+	"ground_speed",
+	"engine_rpm",
+	"fuel_level",
+	"tyre_temperature_fl",
+	"tyre_temperature_fr",
+	"tyre_temperature_rl",
+	"tyre_temperature_rr",
+}
+
+type MetricsOpts struct {
+	// Namespace is prepended to every metric name, defaults to gt_telemetry.
+	Namespace string
+	// LiveGauges publishes the latest value of the telemetry channels.
+	LiveGauges bool
+	// Channels selects the live gauges to publish, defaults to speed, RPM,
+	// fuel level and tyre temperatures.
+	Channels []string
+	Units    UnitSystem
+}
+
+type metricsHandler struct {
+	client    *GTClient
+	namespace string
+	channels  []Channel
+}
+
+// MetricsHandler returns an HTTP handler that publishes the client statistics
+// and optionally the live telemetry values in the Prometheus text format.
+func (c *GTClient) MetricsHandler(opts MetricsOpts) (http.Handler, error) {
+	if opts.Namespace == "" {
+		opts.Namespace = "gt_telemetry"
+	}
+
+	h := &metricsHandler{
+		client:    c,
+		namespace: opts.Namespace,
+	}
+
+	if opts.LiveGauges {
+		names := opts.Channels
+		if len(names) == 0 {
+			names = defaultMetricsChannels
+		}
+
+		channels, err := LookupChannels(opts.Units, names)
+		if err != nil {
+			return nil, err
+		}
+		h.channels = channels
+	}
+
+	return h, nil
+}
+
+func (h *metricsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", metricsContentType)
+
+	out := bufio.NewWriter(w)
+	defer out.Flush()
+
+	stats := h.client.Stats()
+
+	h.write(out, "packets_total", "counter", "Total number of telemetry packets received.", formatCount(stats.PacketsReceived))
+	h.write(out, "packets_dropped_total", "counter", "Number of telemetry packets missing from the sequence.", formatCount(stats.PacketsDropped))
+	h.write(out, "packets_duplicate_total", "counter", "Number of telemetry packets received more than once.", formatCount(stats.PacketsDuplicate))
+	h.write(out, "packets_out_of_order_total", "counter", "Number of telemetry packets received after a later packet.", formatCount(stats.PacketsOutOfOrder))
+	h.write(out, "packets_invalid_total", "counter", "Number of telemetry packets that failed to decode.", formatCount(stats.PacketsInvalid))
+	h.write(out, "sequence_resets_total", "counter", "Number of times the packet sequence restarted.", formatCount(stats.SequenceResets))
+	h.write(out, "packet_rate", "gauge", "Telemetry packet rate per second over the last five seconds.", formatMetricValue(stats.PacketRate))
+	h.write(out, "packet_rate_lifetime", "gauge", "Telemetry packet rate per second since the first packet.", formatMetricValue(stats.PacketRateLifetime))
+	h.write(out, "packet_rate_max", "gauge", "Highest telemetry packet rate per second over five seconds.", formatMetricValue(stats.PacketRateMax))
+	h.write(out, "decode_duration_max_seconds", "gauge", "Longest time taken to decode a telemetry packet.", formatMetricValue(stats.DecodeTime.Max.Seconds()))
+
+	name := h.namespace + "_jitter_seconds"
+	fmt.Fprintf(out, "# HELP %s Variation between packet inter-arrival times over the last five seconds.\n", name)
+	// the percentiles are gauges as a summary would also need the sum and
+	// count of every observation
+	fmt.Fprintf(out, "# TYPE %s gauge\n", name)
+	for _, p := range []struct {
+		percentile string
+		value      time.Duration
+	}{
+		{"50", stats.Jitter.P50},
+		{"90", stats.Jitter.P90},
+		{"99", stats.Jitter.P99},
+		{"100", stats.Jitter.Max},
+	} {
+		fmt.Fprintf(out, "%s{percentile=\"%s\"} %s\n", name, p.percentile, formatMetricValue(p.value.Seconds()))
+	}
+
+	name = h.namespace + "_decode_duration_seconds"
+	fmt.Fprintf(out, "# HELP %s Time taken to decode telemetry packets.\n", name)
+	fmt.Fprintf(out, "# TYPE %s histogram\n", name)
+	for _, bucket := range stats.DecodeTime.Buckets {
+		fmt.Fprintf(out, "%s_bucket{le=\"%s\"} %d\n", name, formatMetricValue(bucket.UpperBound.Seconds()), bucket.Count)
+	}
+	fmt.Fprintf(out, "%s_bucket{le=\"+Inf\"} %d\n", name, stats.DecodeTime.Count)
+	fmt.Fprintf(out, "%s_sum %s\n", name, formatMetricValue(stats.DecodeTime.Sum.Seconds()))
+	fmt.Fprintf(out, "%s_count %d\n", name, stats.DecodeTime.Count)
+
+	if len(h.channels) == 0 {
+		return
+	}
+
+	frame, ok := h.client.LastFrame()
+	if !ok {
+		return
+	}
+
+	for _, channel := range h.channels {
+		value, ok := metricValue(channel.Value(frame))
+		if !ok {
+			continue
+		}
+
+		help := "Live value of the " + strings.ReplaceAll(channel.Name, "_", " ") + " channel"
+		if channel.Unit != "" {
+			help += " in " + channel.Unit
+		}
+		h.write(out, channel.Name, "gauge", help+".", value)
+	}
+}
+
+func (h *metricsHandler) write(out *bufio.Writer, name string, kind string, help string, value string) {
+	name = h.namespace + "_" + name
+
+	fmt.Fprintf(out, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(out, "# TYPE %s %s\n", name, kind)
+	fmt.Fprintf(out, "%s %s\n", name, value)
+}
+
+// metricValue formats a channel value as a sample, string values and
+// channels that do not apply to the vehicle can not be published and are
+// reported as not ok.
+func metricValue(value any) (string, bool) {
+	switch v := value.(type) {
+	case nil:
+		return "", false
+	case float32:
+		return strconv.FormatFloat(float64(v), 'g', -1, 32), true
+	case float64:
+		return formatMetricValue(v), true
+	case bool:
+		if v {
+			return "1", true
+		}
+		return "0", true
+	case string:
+		return "", false
+	default:
+		return fmt.Sprint(v), true
+	}
+}
+
+func formatCount(value uint64) string {
+	return strconv.FormatUint(value, 10)
+}
+
+func formatMetricValue(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
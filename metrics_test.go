@@ -0,0 +1,114 @@
+package telemetry
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type MetricsTestSuite struct {
+	suite.Suite
+	client *GTClient
+}
+
+func TestMetricsTestSuite(t *testing.T) {
+	suite.Run(t, new(MetricsTestSuite))
+}
+
+func (suite *MetricsTestSuite) SetupTest() {
+	client, err := NewGTClient(GTClientOpts{
+		LogLevel:     "off",
+		StatsEnabled: true,
+	})
+	suite.Require().NoError(err)
+
+	suite.client = client
+}
+
+func (suite *MetricsTestSuite) scrape(opts MetricsOpts) string {
+	handler, err := suite.client.MetricsHandler(opts)
+	suite.Require().NoError(err)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+
+	suite.Equal(metricsContentType, recorder.Header().Get("Content-Type"))
+
+	return recorder.Body.String()
+}
+
+func (suite *MetricsTestSuite) TestMetricsHandlerPublishesPacketCounters() {
+	// Arrange
+	for _, id := range []uint32{1, 2, 5} {
+		suite.client.Telemetry.RawTelemetry.SequenceId = id
+		suite.client.collectStats(20 * time.Microsecond)
+	}
+
+	// Act
+	body := suite.scrape(MetricsOpts{})
+
+	// Assert
+	suite.Contains(body, "# TYPE gt_telemetry_packets_total counter\ngt_telemetry_packets_total 3\n")
+	suite.Contains(body, "gt_telemetry_packets_dropped_total 2\n")
+	suite.Contains(body, "gt_telemetry_packets_invalid_total 0\n")
+}
+
+func (suite *MetricsTestSuite) TestMetricsHandlerPublishesDecodeTimeHistogram() {
+	// Arrange
+	for i, decodeTime := range []time.Duration{5 * time.Microsecond, 40 * time.Microsecond, 20 * time.Millisecond} {
+		suite.client.Telemetry.RawTelemetry.SequenceId = uint32(i + 1)
+		suite.client.collectStats(decodeTime)
+	}
+	suite.client.Telemetry.RawTelemetry.SequenceId = 4
+	suite.client.collectStats(time.Microsecond)
+
+	// Act
+	body := suite.scrape(MetricsOpts{Namespace: "rig"})
+
+	// Assert
+	suite.Contains(body, "# TYPE rig_decode_duration_seconds histogram\n")
+	suite.Contains(body, "rig_decode_duration_seconds_bucket{le=\"1e-05\"} 2\n")
+	suite.Contains(body, "rig_decode_duration_seconds_bucket{le=\"5e-05\"} 3\n")
+	suite.Contains(body, "rig_decode_duration_seconds_bucket{le=\"0.01\"} 3\n")
+	suite.Contains(body, "rig_decode_duration_seconds_bucket{le=\"+Inf\"} 4\n")
+	suite.Contains(body, "rig_decode_duration_seconds_count 4\n")
+	suite.Contains(body, "rig_decode_duration_seconds_sum 0.020046\n")
+}
+
+func (suite *MetricsTestSuite) TestMetricsHandlerPublishesLiveGaugesWhenEnabled() {
+	// Arrange
+	suite.client.Telemetry.RawTelemetry.EngineRpm = 7250.5
+	suite.client.Telemetry.RawTelemetry.GroundSpeed = 10
+	suite.client.publish()
+
+	// Act
+	body := suite.scrape(MetricsOpts{LiveGauges: true})
+
+	// Assert
+	suite.Contains(body, "# HELP gt_telemetry_engine_rpm Live value of the engine rpm channel in rpm.\n")
+	suite.Contains(body, "gt_telemetry_engine_rpm 7250.5\n")
+	suite.Contains(body, "gt_telemetry_ground_speed 36\n")
+	suite.Contains(body, "gt_telemetry_tyre_temperature_rr 0\n")
+}
+
+func (suite *MetricsTestSuite) TestMetricsHandlerOmitsLiveGaugesByDefault() {
+	// Arrange
+	suite.client.publish()
+
+	// Act
+	body := suite.scrape(MetricsOpts{})
+
+	// Assert
+	suite.NotContains(body, "gt_telemetry_engine_rpm")
+}
+
+func (suite *MetricsTestSuite) TestMetricsHandlerWithUnknownChannelReturnsError() {
+	// Act
+	handler, err := suite.client.MetricsHandler(MetricsOpts{LiveGauges: true, Channels: []string{"nitrous"}})
+
+	// Assert
+	suite.Nil(handler)
+	suite.ErrorContains(err, `unknown channel "nitrous"`)
+}
@@ -0,0 +1,800 @@
+package telemetry
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Availability tells whether a value was reported and, when it was not, why.
+type Availability int
+
+const (
+	Available Availability = iota
+	// NoPacket is reported until a packet with the value has been decoded.
+	NoPacket
+	// NotSet is reported for values the game has not set yet, such as the
+	// lap times before a lap has been completed.
+	NotSet
+	// NotApplicable is reported for values that do not apply to the vehicle
+	// or its current state, such as the turbo boost of an electric vehicle.
+	NotApplicable
+)
+
+func (a Availability) String() string {
+	switch a {
+	case Available:
+		return "available"
+	case NoPacket:
+		return "no packet"
+	case NotSet:
+		return "not set"
+	case NotApplicable:
+		return "not applicable"
+	default:
+		return "unknown"
+	}
+}
+
+// Optional is a value that is only meaningful when it is available, which
+// tells a real zero apart from a value that was not reported.
+type Optional[T any] struct {
+	Value        T
+	Availability Availability
+}
+
+// Get returns the value and whether it is available.
+func (o Optional[T]) Get() (T, bool) {
+	return o.Value, o.Availability == Available
+}
+
+// OK reports whether the value is available.
+func (o Optional[T]) OK() bool {
+	return o.Availability == Available
+}
+
+// Or returns the value when it is available and the fallback otherwise.
+func (o Optional[T]) Or(fallback T) T {
+	if o.Availability != Available {
+		return fallback
+	}
+
+	return o.Value
+}
+
+func available[T any](value T) Optional[T] {
+	return Optional[T]{Value: value, Availability: Available}
+}
+
+func unavailable[T any](availability Availability) Optional[T] {
+	return Optional[T]{Availability: availability}
+}
+
+// OptionalValues presents the transformer values as Optional values, so
+// that missing packets and the placeholders the game sends for values it has
+// not set are not mistaken for real values. Values in other units than the
+// base unit and values that already report ok are not repeated.
+type OptionalValues struct {
+	t *transformer
+}
+
+// Optional returns the values of the transformer as Optional values.
+func (t *transformer) Optional() OptionalValues {
+	return OptionalValues{t: t}
+}
+
+// HasPacket reports whether a packet has been decoded.
+func (t *transformer) HasPacket() bool {
+	return t.RawTelemetry.Header != nil
+}
+
+// check returns the value when a packet has been decoded, or the reason it is
+// not available.
+func check[T any](o OptionalValues, value func() T) Optional[T] {
+	if !o.t.HasPacket() {
+		return unavailable[T](NoPacket)
+	}
+
+	return available(value())
+}
+
+// nonZero returns the value when a packet has been decoded, and reports it as
+// not set while it is zero.
+func nonZero[T comparable](o OptionalValues, value func() T) Optional[T] {
+	if !o.t.HasPacket() {
+		return unavailable[T](NoPacket)
+	}
+
+	v := value()
+	var zero T
+	if v == zero {
+		return unavailable[T](NotSet)
+	}
+
+	return available(v)
+}
+
+func (o OptionalValues) AngularVelocityVector() Optional[Vector] {
+	if o.t.RawTelemetry.AngularVelocityVector == nil {
+		return unavailable[Vector](NoPacket)
+	}
+
+	return available(o.t.AngularVelocityVector())
+}
+
+// BestLaptime is not set until a lap has been completed in the session.
+func (o OptionalValues) BestLaptime() Optional[time.Duration] {
+	return laptime(o, o.t.RawTelemetry.BestLaptime)
+}
+
+// LastLaptime is not set until a lap has been completed in the session.
+func (o OptionalValues) LastLaptime() Optional[time.Duration] {
+	return laptime(o, o.t.RawTelemetry.LastLaptime)
+}
+
+func laptime(o OptionalValues, milliseconds int32) Optional[time.Duration] {
+	switch {
+	case !o.t.HasPacket():
+		return unavailable[time.Duration](NoPacket)
+	case milliseconds < 0:
+		return unavailable[time.Duration](NotSet)
+	default:
+		return available(time.Duration(milliseconds) * time.Millisecond)
+	}
+}
+
+func (o OptionalValues) BrakePercent() Optional[float32] {
+	return check(o, o.t.BrakePercent)
+}
+
+func (o OptionalValues) ThrottlePercent() Optional[float32] {
+	return check(o, o.t.ThrottlePercent)
+}
+
+func (o OptionalValues) ClutchActuationPercent() Optional[float32] {
+	return check(o, o.t.ClutchActuationPercent)
+}
+
+func (o OptionalValues) ClutchEngagementPercent() Optional[float32] {
+	return check(o, o.t.ClutchEngagementPercent)
+}
+
+func (o OptionalValues) ClutchOutputRPM() Optional[float32] {
+	return check(o, o.t.ClutchOutputRPM)
+}
+
+func (o OptionalValues) EngineRPM() Optional[float32] {
+	return check(o, o.t.EngineRPM)
+}
+
+func (o OptionalValues) EngineRPMLight() Optional[RevLight] {
+	return check(o, o.t.EngineRPMLight)
+}
+
+// CalculatedVmax is not set until the game has calculated the top speed and
+// the tyre sizes are known.
+func (o OptionalValues) CalculatedVmax() Optional[Vmax] {
+	if o.t.RawTelemetry.TyreRadius == nil {
+		return unavailable[Vmax](NoPacket)
+	}
+
+	vmax := o.t.CalculatedVmax()
+	if vmax.Speed == 0 || vmax.RPM == 0 {
+		return unavailable[Vmax](NotSet)
+	}
+
+	return available(vmax)
+}
+
+func (o OptionalValues) CurrentLap() Optional[int16] {
+	return check(o, o.t.CurrentLap)
+}
+
+// RaceLaps is not set outside of races with a lap count.
+func (o OptionalValues) RaceLaps() Optional[uint16] {
+	return nonZero(o, o.t.RaceLaps)
+}
+
+func (o OptionalValues) SequenceID() Optional[uint32] {
+	return check(o, o.t.SequenceID)
+}
+
+func (o OptionalValues) TimeOfDay() Optional[time.Duration] {
+	return check(o, o.t.TimeOfDay)
+}
+
+func (o OptionalValues) Heading() Optional[float32] {
+	return check(o, o.t.Heading)
+}
+
+func (o OptionalValues) RideHeightMeters() Optional[float32] {
+	return check(o, o.t.RideHeightMeters)
+}
+
+// CurrentGear is not applicable while the gearbox is in neutral.
+func (o OptionalValues) CurrentGear() Optional[int] {
+	switch gear := o.t.RawTelemetry.TransmissionGear; {
+	case gear == nil:
+		return unavailable[int](NoPacket)
+	case gear.Current == 15:
+		return unavailable[int](NotApplicable)
+	default:
+		return available(int(gear.Current))
+	}
+}
+
+// CurrentGearRatio is not applicable in neutral and reverse.
+func (o OptionalValues) CurrentGearRatio() Optional[float32] {
+	gear, ok := o.CurrentGear().Get()
+	if !ok {
+		return unavailable[float32](o.CurrentGear().Availability)
+	}
+
+	ratios := o.t.Transmission().GearRatios
+	if gear < 1 || gear > len(ratios) {
+		return unavailable[float32](NotApplicable)
+	}
+
+	return available(ratios[gear-1])
+}
+
+func (o OptionalValues) CurrentGearString() Optional[string] {
+	if o.t.RawTelemetry.TransmissionGear == nil {
+		return unavailable[string](NoPacket)
+	}
+
+	return available(o.t.CurrentGearString())
+}
+
+// SuggestedGear is not set while the game is not suggesting a gear.
+func (o OptionalValues) SuggestedGear() Optional[uint64] {
+	switch gear := o.t.RawTelemetry.TransmissionGear; {
+	case gear == nil:
+		return unavailable[uint64](NoPacket)
+	case gear.Suggested == 15:
+		return unavailable[uint64](NotSet)
+	default:
+		return available(gear.Suggested)
+	}
+}
+
+// DifferentialRatio is not set until the top speed and tyre sizes are known.
+func (o OptionalValues) DifferentialRatio() Optional[float32] {
+	if !o.t.HasPacket() {
+		return unavailable[float32](NoPacket)
+	}
+
+	ratio := o.t.DifferentialRatio()
+	if ratio < 0 {
+		return unavailable[float32](NotSet)
+	}
+
+	return available(ratio)
+}
+
+func (o OptionalValues) Flags() Optional[Flags] {
+	if o.t.RawTelemetry.Flags == nil {
+		return unavailable[Flags](NoPacket)
+	}
+
+	return available(o.t.Flags())
+}
+
+func (o OptionalValues) GroundSpeedMetersPerSecond() Optional[float32] {
+	return check(o, o.t.GroundSpeedMetersPerSecond)
+}
+
+func (o OptionalValues) PositionalMapCoordinates() Optional[Vector] {
+	if o.t.RawTelemetry.MapPositionCoordinates == nil {
+		return unavailable[Vector](NoPacket)
+	}
+
+	return available(o.t.PositionalMapCoordinates())
+}
+
+// RaceEntrants is not set outside of races.
+func (o OptionalValues) RaceEntrants() Optional[int16] {
+	return position(o, o.t.RawTelemetry.RaceEntrants)
+}
+
+// StartingPosition is not set outside of races and once the race has started.
+func (o OptionalValues) StartingPosition() Optional[int16] {
+	return position(o, o.t.RawTelemetry.StartingPosition)
+}
+
+func position(o OptionalValues, value int16) Optional[int16] {
+	switch {
+	case !o.t.HasPacket():
+		return unavailable[int16](NoPacket)
+	case value < 0:
+		return unavailable[int16](NotSet)
+	default:
+		return available(value)
+	}
+}
+
+func (o OptionalValues) RotationVector() Optional[SymmetryAxes] {
+	if o.t.RawTelemetry.RotationAxes == nil {
+		return unavailable[SymmetryAxes](NoPacket)
+	}
+
+	return available(o.t.RotationVector())
+}
+
+func (o OptionalValues) SuspensionHeightMeters() Optional[CornerSet] {
+	if o.t.RawTelemetry.SuspensionHeight == nil {
+		return unavailable[CornerSet](NoPacket)
+	}
+
+	return available(o.t.SuspensionHeightMeters())
+}
+
+func (o OptionalValues) Transmission() Optional[Transmission] {
+	if o.t.RawTelemetry.TransmissionGearRatio == nil {
+		return unavailable[Transmission](NoPacket)
+	}
+
+	return available(o.t.Transmission())
+}
+
+func (o OptionalValues) TransmissionTopSpeedRatio() Optional[float32] {
+	return check(o, o.t.TransmissionTopSpeedRatio)
+}
+
+func (o OptionalValues) TyreDiameterMeters() Optional[CornerSet] {
+	if o.t.RawTelemetry.TyreRadius == nil {
+		return unavailable[CornerSet](NoPacket)
+	}
+
+	return available(o.t.TyreDiameterMeters())
+}
+
+func (o OptionalValues) TyreRadiusMeters() Optional[CornerSet] {
+	if o.t.RawTelemetry.TyreRadius == nil {
+		return unavailable[CornerSet](NoPacket)
+	}
+
+	return available(o.t.TyreRadiusMeters())
+}
+
+func (o OptionalValues) TyreTemperatureCelsius() Optional[CornerSet] {
+	if o.t.RawTelemetry.TyreTemperature == nil {
+		return unavailable[CornerSet](NoPacket)
+	}
+
+	return available(o.t.TyreTemperatureCelsius())
+}
+
+func (o OptionalValues) VelocityVector() Optional[Vector] {
+	if o.t.RawTelemetry.VelocityVector == nil {
+		return unavailable[Vector](NoPacket)
+	}
+
+	return available(o.t.VelocityVector())
+}
+
+func (o OptionalValues) WheelSpeedMetersPerSecond() Optional[CornerSet] {
+	if o.t.RawTelemetry.WheelRadiansPerSecond == nil || o.t.RawTelemetry.TyreRadius == nil {
+		return unavailable[CornerSet](NoPacket)
+	}
+
+	return available(o.t.WheelSpeedMetersPerSecond())
+}
+
+func (o OptionalValues) WheelSpeedRadiansPerSecond() Optional[CornerSet] {
+	if o.t.RawTelemetry.WheelRadiansPerSecond == nil {
+		return unavailable[CornerSet](NoPacket)
+	}
+
+	return available(o.t.WheelSpeedRadiansPerSecond())
+}
+
+// TyreSlipRatio is not set while the vehicle is stationary.
+func (o OptionalValues) TyreSlipRatio() Optional[CornerSet] {
+	return slip(o, o.t.TyreSlipRatio)
+}
+
+func (o OptionalValues) Drivetrain() Optional[Drivetrain] {
+	return check(o, o.t.Drivetrain)
+}
+
+func (o OptionalValues) DrivenTyreDiameterMeters() Optional[float32] {
+	if o.t.RawTelemetry.TyreRadius == nil {
+		return unavailable[float32](NoPacket)
+	}
+
+	return available(o.t.DrivenTyreDiameterMeters())
+}
+
+func (o OptionalValues) DrivenWheelSpeedMetersPerSecond() Optional[float32] {
+	if o.t.RawTelemetry.WheelRadiansPerSecond == nil || o.t.RawTelemetry.TyreRadius == nil {
+		return unavailable[float32](NoPacket)
+	}
+
+	return available(o.t.DrivenWheelSpeedMetersPerSecond())
+}
+
+// DrivenWheelSlipRatio is not set while the vehicle is stationary.
+func (o OptionalValues) DrivenWheelSlipRatio() Optional[float32] {
+	return slip(o, o.t.DrivenWheelSlipRatio)
+}
+
+func slip[T any](o OptionalValues, value func() T) Optional[T] {
+	switch {
+	case o.t.RawTelemetry.WheelRadiansPerSecond == nil || o.t.RawTelemetry.TyreRadius == nil:
+		return unavailable[T](NoPacket)
+	case o.t.GroundSpeedMetersPerSecond() == 0:
+		return unavailable[T](NotSet)
+	default:
+		return available(value())
+	}
+}
+
+// SuspensionVelocityMetersPerSecond is not set until the suspension has been
+// seen in consecutive packets.
+func (o OptionalValues) SuspensionVelocityMetersPerSecond() Optional[CornerSet] {
+	return suspensionValue(o, o.t.SuspensionVelocityMetersPerSecond)
+}
+
+// SuspensionBottomingOut is not set until the suspension has been seen in
+// consecutive packets.
+func (o OptionalValues) SuspensionBottomingOut() Optional[Corners[bool]] {
+	return suspensionValue(o, o.t.SuspensionBottomingOut)
+}
+
+// WheelsOffGround is not set until the suspension has been seen in
+// consecutive packets.
+func (o OptionalValues) WheelsOffGround() Optional[Corners[bool]] {
+	return suspensionValue(o, o.t.WheelsOffGround)
+}
+
+func suspensionValue[T any](o OptionalValues, value func() T) Optional[T] {
+	switch {
+	case o.t.RawTelemetry.SuspensionHeight == nil:
+		return unavailable[T](NoPacket)
+	case !o.t.suspension.measured:
+		return unavailable[T](NotSet)
+	default:
+		return available(value())
+	}
+}
+
+func (o OptionalValues) BodyRollDegrees() Optional[float32] {
+	if o.t.RawTelemetry.SuspensionHeight == nil {
+		return unavailable[float32](NoPacket)
+	}
+
+	return available(o.t.BodyRollDegrees())
+}
+
+func (o OptionalValues) BodyPitchDegrees() Optional[float32] {
+	if o.t.RawTelemetry.SuspensionHeight == nil {
+		return unavailable[float32](NoPacket)
+	}
+
+	return available(o.t.BodyPitchDegrees())
+}
+
+func (o OptionalValues) SuspensionLap() Optional[SuspensionLap] {
+	return check(o, o.t.SuspensionLap)
+}
+
+// FuelLevelPercent is not applicable to electric vehicles.
+func (o OptionalValues) FuelLevelPercent() Optional[float32] {
+	return combustionValue(o, o.t.FuelLevelPercent)
+}
+
+// FuelCapacityPercent is not applicable to electric vehicles.
+func (o OptionalValues) FuelCapacityPercent() Optional[float32] {
+	return combustionValue(o, o.t.FuelCapacityPercent)
+}
+
+// FuelLevel is not applicable to electric vehicles, and is not set when the
+// size of the fuel tank is not in the inventory.
+func (o OptionalValues) FuelLevel() Optional[Volume] {
+	switch fuel, ok := o.t.FuelLevel(); {
+	case !o.t.HasPacket():
+		return unavailable[Volume](NoPacket)
+	case !o.t.HasCombustionEngine():
+		return unavailable[Volume](NotApplicable)
+	case !ok:
+		return unavailable[Volume](NotSet)
+	default:
+		return available(fuel)
+	}
+}
+
+// TurboBoostBar is not applicable to electric vehicles.
+func (o OptionalValues) TurboBoostBar() Optional[float32] {
+	return combustionValue(o, o.t.TurboBoostBar)
+}
+
+// OilPressureKPA is not applicable to electric vehicles.
+func (o OptionalValues) OilPressureKPA() Optional[float32] {
+	return combustionValue(o, o.t.OilPressureKPA)
+}
+
+// OilTemperatureCelsius is not applicable to electric vehicles.
+func (o OptionalValues) OilTemperatureCelsius() Optional[float32] {
+	return combustionValue(o, o.t.OilTemperatureCelsius)
+}
+
+// WaterTemperatureCelsius is not applicable to electric vehicles.
+func (o OptionalValues) WaterTemperatureCelsius() Optional[float32] {
+	return combustionValue(o, o.t.WaterTemperatureCelsius)
+}
+
+func combustionValue(o OptionalValues, value func() float32) Optional[float32] {
+	switch {
+	case !o.t.HasPacket():
+		return unavailable[float32](NoPacket)
+	case !o.t.HasCombustionEngine():
+		return unavailable[float32](NotApplicable)
+	default:
+		return available(value())
+	}
+}
+
+// BatteryChargePercent is only applicable to electric vehicles.
+func (o OptionalValues) BatteryChargePercent() Optional[float32] {
+	return powertrainValue(o, o.t.BatteryChargePercent)
+}
+
+// EnergyRecovery is only applicable to electric and hybrid vehicles, and is
+// not set unless the console sends the ~ packet format.
+func (o OptionalValues) EnergyRecovery() Optional[float32] {
+	if o.t.HasPacket() && o.t.VehiclePowertrain() != PowertrainCombustion && o.t.RawTelemetry.Energy == nil {
+		return unavailable[float32](NotSet)
+	}
+
+	return powertrainValue(o, o.t.EnergyRecovery)
+}
+
+func powertrainValue(o OptionalValues, value func() (float32, bool)) Optional[float32] {
+	if !o.t.HasPacket() {
+		return unavailable[float32](NoPacket)
+	}
+
+	v, ok := value()
+	if !ok {
+		return unavailable[float32](NotApplicable)
+	}
+
+	return available(v)
+}
+
+func (o OptionalValues) VehicleID() Optional[uint32] {
+	return check(o, o.t.VehicleID)
+}
+
+func (o OptionalValues) VehiclePowertrain() Optional[Powertrain] {
+	return check(o, o.t.VehiclePowertrain)
+}
+
+// The vehicle details are not set for vehicles missing from the inventory,
+// and the specifications are not set when the inventory does not have them.
+
+func (o OptionalValues) VehicleManufacturer() Optional[string] {
+	return nonZero(o, o.t.VehicleManufacturer)
+}
+
+func (o OptionalValues) VehicleModel() Optional[string] {
+	return nonZero(o, o.t.VehicleModel)
+}
+
+func (o OptionalValues) VehicleYear() Optional[int] {
+	return nonZero(o, o.t.VehicleYear)
+}
+
+func (o OptionalValues) VehicleCategory() Optional[string] {
+	return nonZero(o, o.t.VehicleCategory)
+}
+
+func (o OptionalValues) VehicleType() Optional[string] {
+	return nonZero(o, o.t.VehicleType)
+}
+
+func (o OptionalValues) VehicleAspiration() Optional[string] {
+	return nonZero(o, o.t.VehicleAspiration)
+}
+
+func (o OptionalValues) VehicleAspirationExpanded() Optional[string] {
+	return nonZero(o, o.t.VehicleAspirationExpanded)
+}
+
+func (o OptionalValues) VehicleDrivetrain() Optional[string] {
+	return nonZero(o, o.t.VehicleDrivetrain)
+}
+
+func (o OptionalValues) VehicleHasOpenCockpit() Optional[bool] {
+	if !o.t.HasPacket() {
+		return unavailable[bool](NoPacket)
+	}
+	if _, ok := o.t.CurrentVehicle(); !ok {
+		return unavailable[bool](NotSet)
+	}
+
+	return available(o.t.VehicleHasOpenCockpit())
+}
+
+func (o OptionalValues) VehiclePower() Optional[Power] {
+	return nonZero(o, o.t.VehiclePower)
+}
+
+func (o OptionalValues) VehicleTorque() Optional[Torque] {
+	return nonZero(o, o.t.VehicleTorque)
+}
+
+func (o OptionalValues) VehicleWeight() Optional[Mass] {
+	return nonZero(o, o.t.VehicleWeight)
+}
+
+func (o OptionalValues) VehicleWeightDistribution() Optional[float32] {
+	return nonZero(o, o.t.VehicleWeightDistribution)
+}
+
+func (o OptionalValues) VehicleWheelbase() Optional[Length] {
+	return nonZero(o, o.t.VehicleWheelbase)
+}
+
+func (o OptionalValues) VehicleTrackWidthFront() Optional[Length] {
+	return nonZero(o, o.t.VehicleTrackWidthFront)
+}
+
+func (o OptionalValues) VehicleTrackWidthRear() Optional[Length] {
+	return nonZero(o, o.t.VehicleTrackWidthRear)
+}
+
+func (o OptionalValues) VehiclePerformancePoints() Optional[float32] {
+	return nonZero(o, o.t.VehiclePerformancePoints)
+}
+
+func (o OptionalValues) VehicleEngineLayout() Optional[string] {
+	return nonZero(o, o.t.VehicleEngineLayout)
+}
+
+func (o OptionalValues) VehicleDisplacement() Optional[Volume] {
+	return nonZero(o, o.t.VehicleDisplacement)
+}
+
+func (o OptionalValues) VehicleFuelTank() Optional[Volume] {
+	return nonZero(o, o.t.VehicleFuelTank)
+}
+
+// Lookup samples a channel from the frame along with whether the value is
+// available, so that a real zero can be told apart from a value that has not
+// been received, has not been set by the game or does not apply. The
+// availability follows the matching OptionalValues accessor.
+func (f Frame) Lookup(channel Channel) Optional[any] {
+	if !f.HasPacket() {
+		return unavailable[any](NoPacket)
+	}
+
+	if availability, ok := channelAvailability(channel.Name); ok {
+		if a := availability(f.Optional()); a != Available {
+			return unavailable[any](a)
+		}
+	}
+
+	value := channel.Value(f)
+	if value == nil {
+		return unavailable[any](NotApplicable)
+	}
+
+	return available(value)
+}
+
+// availabilityOf adapts an OptionalValues accessor for channelAvailabilities.
+func availabilityOf[T any](value func(o OptionalValues) Optional[T]) func(o OptionalValues) Availability {
+	return func(o OptionalValues) Availability {
+		return value(o).Availability
+	}
+}
+
+// channelAvailabilities holds the accessor each channel is sampled from, by
+// channel name without the corner or axis suffix.
+var channelAvailabilities = map[string]func(o OptionalValues) Availability{
+	"sequence_id":                  availabilityOf(OptionalValues.SequenceID),
+	"time_of_day":                  availabilityOf(OptionalValues.TimeOfDay),
+	"current_lap":                  availabilityOf(OptionalValues.CurrentLap),
+	"race_laps":                    availabilityOf(OptionalValues.RaceLaps),
+	"best_laptime":                 availabilityOf(OptionalValues.BestLaptime),
+	"last_laptime":                 availabilityOf(OptionalValues.LastLaptime),
+	"starting_position":            availabilityOf(OptionalValues.StartingPosition),
+	"race_entrants":                availabilityOf(OptionalValues.RaceEntrants),
+	"vehicle_id":                   availabilityOf(OptionalValues.VehicleID),
+	"vehicle_manufacturer":         availabilityOf(OptionalValues.VehicleManufacturer),
+	"vehicle_model":                availabilityOf(OptionalValues.VehicleModel),
+	"throttle":                     availabilityOf(OptionalValues.ThrottlePercent),
+	"brake":                        availabilityOf(OptionalValues.BrakePercent),
+	"clutch_actuation":             availabilityOf(OptionalValues.ClutchActuationPercent),
+	"clutch_engagement":            availabilityOf(OptionalValues.ClutchEngagementPercent),
+	"clutch_output_rpm":            availabilityOf(OptionalValues.ClutchOutputRPM),
+	"current_gear":                 availabilityOf(OptionalValues.CurrentGear),
+	"suggested_gear":               availabilityOf(OptionalValues.SuggestedGear),
+	"engine_rpm":                   availabilityOf(OptionalValues.EngineRPM),
+	"rev_light_rpm_min":            availabilityOf(OptionalValues.EngineRPMLight),
+	"rev_light_rpm_max":            availabilityOf(OptionalValues.EngineRPMLight),
+	"ground_speed":                 availabilityOf(OptionalValues.GroundSpeedMetersPerSecond),
+	"fuel_level":                   availabilityOf(OptionalValues.FuelLevelPercent),
+	"fuel_capacity":                availabilityOf(OptionalValues.FuelCapacityPercent),
+	"fuel_volume":                  availabilityOf(OptionalValues.FuelLevel),
+	"turbo_boost":                  availabilityOf(OptionalValues.TurboBoostBar),
+	"oil_pressure":                 availabilityOf(OptionalValues.OilPressureKPA),
+	"oil_temperature":              availabilityOf(OptionalValues.OilTemperatureCelsius),
+	"water_temperature":            availabilityOf(OptionalValues.WaterTemperatureCelsius),
+	"battery_charge":               availabilityOf(OptionalValues.BatteryChargePercent),
+	"energy_recovery":              availabilityOf(OptionalValues.EnergyRecovery),
+	"ride_height":                  availabilityOf(OptionalValues.RideHeightMeters),
+	"heading":                      availabilityOf(OptionalValues.Heading),
+	"transmission_top_speed_ratio": availabilityOf(OptionalValues.TransmissionTopSpeedRatio),
+	"transmission_gears":           availabilityOf(OptionalValues.Transmission),
+	"differential_ratio":           availabilityOf(OptionalValues.DifferentialRatio),
+	"vmax_speed":                   availabilityOf(OptionalValues.CalculatedVmax),
+	"vmax_rpm":                     availabilityOf(OptionalValues.CalculatedVmax),
+	"front_torque_split":           availabilityOf(OptionalValues.Drivetrain),
+	"driven_wheel_slip_ratio":      availabilityOf(OptionalValues.DrivenWheelSlipRatio),
+	"position":                     availabilityOf(OptionalValues.PositionalMapCoordinates),
+	"velocity":                     availabilityOf(OptionalValues.VelocityVector),
+	"angular_velocity":             availabilityOf(OptionalValues.AngularVelocityVector),
+	"rotation":                     availabilityOf(OptionalValues.RotationVector),
+	"tyre_temperature":             availabilityOf(OptionalValues.TyreTemperatureCelsius),
+	"tyre_radius":                  availabilityOf(OptionalValues.TyreRadiusMeters),
+	"suspension_height":            availabilityOf(OptionalValues.SuspensionHeightMeters),
+	"suspension_velocity":          availabilityOf(OptionalValues.SuspensionVelocityMetersPerSecond),
+	"suspension_bottoming":         availabilityOf(OptionalValues.SuspensionBottomingOut),
+	"wheel_off_ground":             availabilityOf(OptionalValues.WheelsOffGround),
+	"body_roll":                    availabilityOf(OptionalValues.BodyRollDegrees),
+	"body_pitch":                   availabilityOf(OptionalValues.BodyPitchDegrees),
+	"wheel_speed":                  availabilityOf(OptionalValues.WheelSpeedMetersPerSecond),
+	"wheel_rpm":                    availabilityOf(OptionalValues.WheelSpeedRadiansPerSecond),
+	"tyre_slip_ratio":              availabilityOf(OptionalValues.TyreSlipRatio),
+	"flag":                         availabilityOf(OptionalValues.Flags),
+}
+
+// channelAvailability returns the availability of a channel, looking up
+// corner, axis, gear and flag channels by the name they share.
+func channelAvailability(name string) (func(o OptionalValues) Availability, bool) {
+	if availability, ok := channelAvailabilities[name]; ok {
+		return availability, true
+	}
+
+	if gear, ok := strings.CutPrefix(name, "gear_ratio_"); ok {
+		n, err := strconv.Atoi(gear)
+		if err != nil {
+			return nil, false
+		}
+
+		return func(o OptionalValues) Availability {
+			transmission, ok := o.Transmission().Get()
+			switch {
+			case !ok:
+				return o.Transmission().Availability
+			case n > transmission.Gears:
+				return NotApplicable
+			default:
+				return Available
+			}
+		}, true
+	}
+
+	if base, _, ok := cutLast(name, "_"); ok {
+		if availability, ok := channelAvailabilities[base]; ok {
+			return availability, true
+		}
+	}
+	if flag, ok := strings.CutPrefix(name, "flag_"); ok && flag != "" {
+		return channelAvailabilities["flag"], true
+	}
+
+	return nil, false
+}
+
+func cutLast(s string, sep string) (before string, after string, found bool) {
+	i := strings.LastIndex(s, sep)
+	if i < 0 {
+		return s, "", false
+	}
+
+	return s[:i], s[i+len(sep):], true
+}
@@ -0,0 +1,263 @@
+package telemetry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/vwhitteron/gt-telemetry/internal/gttelemetry"
+	"github.com/vwhitteron/gt-telemetry/vehicles"
+)
+
+type OptionalTestSuite struct {
+	suite.Suite
+	transformer *transformer
+}
+
+func TestOptionalTestSuite(t *testing.T) {
+	suite.Run(t, new(OptionalTestSuite))
+}
+
+func (suite *OptionalTestSuite) SetupTest() {
+	inventory, err := vehicles.NewInventory()
+	suite.Require().NoError(err)
+
+	suite.transformer = NewTransformer(inventory)
+	suite.transformer.RawTelemetry = newRawTelemetry()
+}
+
+func (suite *OptionalTestSuite) TestOptionalHelpersReportAvailability() {
+	// Arrange
+	present := available(float32(0))
+	missing := unavailable[float32](NotSet)
+
+	// Act
+	value, ok := present.Get()
+	_, missingOk := missing.Get()
+
+	// Assert
+	suite.True(ok)
+	suite.Equal(float32(0), value)
+	suite.False(missingOk)
+	suite.Equal(float32(7), missing.Or(7))
+	suite.Equal(float32(0), present.Or(7))
+	suite.Equal("not set", missing.Availability.String())
+}
+
+func (suite *OptionalTestSuite) TestValuesAreNotAvailableBeforeTheFirstPacket() {
+	// Arrange
+	suite.transformer.RawTelemetry = gttelemetry.GranTurismoTelemetry{}
+	optional := suite.transformer.Optional()
+
+	// Act
+	availabilities := []Availability{
+		optional.AngularVelocityVector().Availability,
+		optional.BestLaptime().Availability,
+		optional.CurrentGear().Availability,
+		optional.CurrentGearRatio().Availability,
+		optional.Flags().Availability,
+		optional.GroundSpeedMetersPerSecond().Availability,
+		optional.StartingPosition().Availability,
+		optional.FuelLevelPercent().Availability,
+		optional.BatteryChargePercent().Availability,
+		optional.EngineRPM().Availability,
+		optional.ThrottlePercent().Availability,
+		optional.BrakePercent().Availability,
+		optional.ClutchActuationPercent().Availability,
+		optional.CurrentLap().Availability,
+		optional.RaceLaps().Availability,
+		optional.TimeOfDay().Availability,
+		optional.FuelCapacityPercent().Availability,
+		optional.FuelLevel().Availability,
+		optional.TyreSlipRatio().Availability,
+		optional.SuspensionVelocityMetersPerSecond().Availability,
+		optional.VehicleModel().Availability,
+	}
+
+	// Assert
+	for _, availability := range availabilities {
+		suite.Equal(NoPacket, availability)
+	}
+}
+
+func (suite *OptionalTestSuite) TestPlaceholdersAreReportedAsNotSet() {
+	testCases := map[string]struct {
+		arrange func(raw *gttelemetry.GranTurismoTelemetry)
+		value   func(o OptionalValues) Availability
+		want    Availability
+	}{
+		"best laptime not set": {
+			arrange: func(raw *gttelemetry.GranTurismoTelemetry) { raw.BestLaptime = -1 },
+			value:   func(o OptionalValues) Availability { return o.BestLaptime().Availability },
+			want:    NotSet,
+		},
+		"last laptime set": {
+			arrange: func(raw *gttelemetry.GranTurismoTelemetry) { raw.LastLaptime = 0 },
+			value:   func(o OptionalValues) Availability { return o.LastLaptime().Availability },
+			want:    Available,
+		},
+		"starting position not set": {
+			arrange: func(raw *gttelemetry.GranTurismoTelemetry) { raw.StartingPosition = -1 },
+			value:   func(o OptionalValues) Availability { return o.StartingPosition().Availability },
+			want:    NotSet,
+		},
+		"race entrants not set": {
+			arrange: func(raw *gttelemetry.GranTurismoTelemetry) { raw.RaceEntrants = -1 },
+			value:   func(o OptionalValues) Availability { return o.RaceEntrants().Availability },
+			want:    NotSet,
+		},
+		"no suggested gear": {
+			arrange: func(raw *gttelemetry.GranTurismoTelemetry) { raw.TransmissionGear.Suggested = 15 },
+			value:   func(o OptionalValues) Availability { return o.SuggestedGear().Availability },
+			want:    NotSet,
+		},
+		"neutral": {
+			arrange: func(raw *gttelemetry.GranTurismoTelemetry) { raw.TransmissionGear.Current = 15 },
+			value:   func(o OptionalValues) Availability { return o.CurrentGear().Availability },
+			want:    NotApplicable,
+		},
+		"reverse gear ratio": {
+			arrange: func(raw *gttelemetry.GranTurismoTelemetry) { raw.TransmissionGear.Current = 0 },
+			value:   func(o OptionalValues) Availability { return o.CurrentGearRatio().Availability },
+			want:    NotApplicable,
+		},
+		"unknown differential ratio": {
+			arrange: func(raw *gttelemetry.GranTurismoTelemetry) {},
+			value:   func(o OptionalValues) Availability { return o.DifferentialRatio().Availability },
+			want:    NotSet,
+		},
+		"not a race": {
+			arrange: func(raw *gttelemetry.GranTurismoTelemetry) { raw.RaceLaps = 0 },
+			value:   func(o OptionalValues) Availability { return o.RaceLaps().Availability },
+			want:    NotSet,
+		},
+		"stationary tyre slip": {
+			arrange: func(raw *gttelemetry.GranTurismoTelemetry) { raw.GroundSpeed = 0 },
+			value:   func(o OptionalValues) Availability { return o.TyreSlipRatio().Availability },
+			want:    NotSet,
+		},
+		"suspension velocity from a single packet": {
+			arrange: func(raw *gttelemetry.GranTurismoTelemetry) {},
+			value:   func(o OptionalValues) Availability { return o.SuspensionVelocityMetersPerSecond().Availability },
+			want:    NotSet,
+		},
+		"unknown vehicle model": {
+			arrange: func(raw *gttelemetry.GranTurismoTelemetry) { raw.VehicleId = 99999 },
+			value:   func(o OptionalValues) Availability { return o.VehicleModel().Availability },
+			want:    NotSet,
+		},
+		"unknown fuel tank": {
+			arrange: func(raw *gttelemetry.GranTurismoTelemetry) { raw.VehicleId = 82 },
+			value:   func(o OptionalValues) Availability { return o.FuelLevel().Availability },
+			want:    NotSet,
+		},
+		"electric vehicle fuel level": {
+			arrange: func(raw *gttelemetry.GranTurismoTelemetry) { raw.VehicleId = 3390 },
+			value:   func(o OptionalValues) Availability { return o.FuelLevelPercent().Availability },
+			want:    NotApplicable,
+		},
+		"electric vehicle energy recovery without the ~ packet": {
+			arrange: func(raw *gttelemetry.GranTurismoTelemetry) { raw.VehicleId = 3390 },
+			value:   func(o OptionalValues) Availability { return o.EnergyRecovery().Availability },
+			want:    NotSet,
+		},
+		"combustion vehicle energy recovery": {
+			arrange: func(raw *gttelemetry.GranTurismoTelemetry) { raw.VehicleId = 82 },
+			value:   func(o OptionalValues) Availability { return o.EnergyRecovery().Availability },
+			want:    NotApplicable,
+		},
+	}
+
+	for name, tc := range testCases {
+		suite.Run(name, func() {
+			// Arrange
+			suite.transformer.RawTelemetry = newRawTelemetry()
+			tc.arrange(&suite.transformer.RawTelemetry)
+
+			// Act
+			availability := tc.value(suite.transformer.Optional())
+
+			// Assert
+			suite.Equal(tc.want, availability)
+		})
+	}
+}
+
+func (suite *OptionalTestSuite) TestRealZeroValuesAreAvailable() {
+	// Arrange
+	suite.transformer.RawTelemetry.BestLaptime = 0
+	suite.transformer.RawTelemetry.TransmissionGear.Current = 1
+	suite.transformer.RawTelemetry.TransmissionGearRatio.Gear[0] = 3.5
+
+	// Act
+	laptime, laptimeOk := suite.transformer.Optional().BestLaptime().Get()
+	speed, speedOk := suite.transformer.Optional().GroundSpeedMetersPerSecond().Get()
+	ratio, ratioOk := suite.transformer.Optional().CurrentGearRatio().Get()
+
+	// Assert
+	suite.True(laptimeOk)
+	suite.Equal(time.Duration(0), laptime)
+	suite.True(speedOk)
+	suite.Equal(float32(0), speed)
+	suite.True(ratioOk)
+	suite.Equal(float32(3.5), ratio)
+}
+
+func (suite *OptionalTestSuite) TestChannelsCanBeLookedUpWithTheirAvailability() {
+	testCases := map[string]struct {
+		raw     gttelemetry.GranTurismoTelemetry
+		channel string
+		want    Availability
+	}{
+		"no packet":           {raw: gttelemetry.GranTurismoTelemetry{}, channel: "ground_speed", want: NoPacket},
+		"real zero":           {raw: newRawTelemetry(), channel: "ground_speed", want: Available},
+		"laptime not set":     {raw: newRawTelemetry(), channel: "best_laptime", want: NotSet},
+		"combustion only":     {raw: electricTelemetry(), channel: "fuel_level", want: NotApplicable},
+		"suggested gear seen": {raw: newRawTelemetry(), channel: "suggested_gear", want: Available},
+		"neutral":             {raw: neutralTelemetry(), channel: "current_gear", want: NotApplicable},
+		"missing gear":        {raw: newRawTelemetry(), channel: "gear_ratio_8", want: NotApplicable},
+		"single packet":       {raw: newRawTelemetry(), channel: "suspension_velocity_fl", want: NotSet},
+		"flag":                {raw: newRawTelemetry(), channel: "flag_game_paused", want: Available},
+	}
+
+	for name, tc := range testCases {
+		suite.Run(name, func() {
+			// Arrange
+			suite.transformer.RawTelemetry = tc.raw
+			frame := NewFrame(suite.transformer, time.Now())
+			channels, err := LookupChannels(UnitSystemMetric, []string{tc.channel})
+			suite.Require().NoError(err)
+
+			// Act
+			value := frame.Lookup(channels[0])
+
+			// Assert
+			suite.Equal(tc.want, value.Availability)
+		})
+	}
+}
+
+func (suite *OptionalTestSuite) TestEveryChannelFollowsAnAccessor() {
+	for _, channel := range Channels(UnitSystemMetric) {
+		// Act
+		_, ok := channelAvailability(channel.Name)
+
+		// Assert
+		suite.True(ok, "channel %q has no availability", channel.Name)
+	}
+}
+
+func neutralTelemetry() gttelemetry.GranTurismoTelemetry {
+	raw := newRawTelemetry()
+	raw.TransmissionGear.Current = 15
+
+	return raw
+}
+
+func electricTelemetry() gttelemetry.GranTurismoTelemetry {
+	raw := newRawTelemetry()
+	raw.VehicleId = 3390
+
+	return raw
+}
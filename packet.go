@@ -0,0 +1,174 @@
+package telemetry
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/vwhitteron/gt-telemetry/internal/gttelemetry"
+)
+
+const packetSize = 296
+
+var packetMagic = []byte{0x30, 0x53, 0x37, 0x47}
+
+// encodePacket writes decoded telemetry back out in the deciphered packet
+// layout so it can be read by the client. Missing nested values are written
+// as zero, and the motion and energy blocks extend the packet to the B or ~
+// format when they are set. An energy block without motion is not written.
+func encodePacket(raw *gttelemetry.GranTurismoTelemetry) []byte {
+	p := make([]byte, 0, packetSize)
+	p = append(p, packetMagic...)
+
+	f32 := func(v float32) {
+		p = binary.LittleEndian.AppendUint32(p, math.Float32bits(v))
+	}
+	vector := func(v *gttelemetry.GranTurismoTelemetry_Vector) {
+		if v == nil {
+			v = &gttelemetry.GranTurismoTelemetry_Vector{}
+		}
+		f32(v.VectorX)
+		f32(v.VectorY)
+		f32(v.VectorZ)
+	}
+	corners := func(c *gttelemetry.GranTurismoTelemetry_CornerSet) {
+		if c == nil {
+			c = &gttelemetry.GranTurismoTelemetry_CornerSet{}
+		}
+		f32(c.FrontLeft)
+		f32(c.FrontRight)
+		f32(c.RearLeft)
+		f32(c.RearRight)
+	}
+
+	position := raw.MapPositionCoordinates
+	if position == nil {
+		position = &gttelemetry.GranTurismoTelemetry_Coordinate{}
+	}
+	f32(position.CoordinateX)
+	f32(position.CoordinateY)
+	f32(position.CoordinateZ)
+
+	vector(raw.VelocityVector)
+
+	rotation := raw.RotationAxes
+	if rotation == nil {
+		rotation = &gttelemetry.GranTurismoTelemetry_SymmetryAxes{}
+	}
+	f32(rotation.Pitch)
+	f32(rotation.Yaw)
+	f32(rotation.Roll)
+
+	f32(raw.Heading)
+	vector(raw.AngularVelocityVector)
+	f32(raw.RideHeight)
+	f32(raw.EngineRpm)
+	f32(raw.Oiv)
+	f32(raw.FuelLevel)
+	f32(raw.FuelCapacity)
+	f32(raw.GroundSpeed)
+	f32(raw.ManifoldPressure)
+	f32(raw.OilPressure)
+	f32(raw.WaterTemperature)
+	f32(raw.OilTemperature)
+	corners(raw.TyreTemperature)
+
+	p = binary.LittleEndian.AppendUint32(p, raw.SequenceId)
+	p = binary.LittleEndian.AppendUint16(p, raw.CurrentLap)
+	p = binary.LittleEndian.AppendUint16(p, raw.RaceLaps)
+	p = binary.LittleEndian.AppendUint32(p, uint32(raw.BestLaptime))
+	p = binary.LittleEndian.AppendUint32(p, uint32(raw.LastLaptime))
+	p = binary.LittleEndian.AppendUint32(p, raw.TimeOfDay)
+	p = binary.LittleEndian.AppendUint16(p, uint16(raw.StartingPosition))
+	p = binary.LittleEndian.AppendUint16(p, uint16(raw.RaceEntrants))
+	p = binary.LittleEndian.AppendUint16(p, raw.RevLightRpmMin)
+	p = binary.LittleEndian.AppendUint16(p, raw.RevLightRpmMax)
+	p = binary.LittleEndian.AppendUint16(p, raw.CalculatedMaxSpeed)
+	p = binary.LittleEndian.AppendUint16(p, encodeFlags(raw.Flags))
+
+	gear := raw.TransmissionGear
+	if gear == nil {
+		gear = &gttelemetry.GranTurismoTelemetry_TransmissionGear{}
+	}
+	p = append(p, byte(gear.Current&0x0f)|byte(gear.Suggested&0x0f)<<4)
+
+	p = append(p, raw.Throttle, raw.Brake, 0)
+	vector(raw.RoadPlaneVector)
+	p = binary.LittleEndian.AppendUint32(p, raw.RoadPlaneDistance)
+	corners(raw.WheelRadiansPerSecond)
+	corners(raw.TyreRadius)
+	corners(raw.SuspensionHeight)
+	p = append(p, make([]byte, 32)...)
+	f32(raw.ClutchActuation)
+	f32(raw.ClutchEngagement)
+	f32(raw.CluchOutputRpm)
+	f32(raw.TransmissionTopSpeedRatio)
+
+	for i := range 8 {
+		ratio := float32(0)
+		if raw.TransmissionGearRatio != nil && i < len(raw.TransmissionGearRatio.Gear) {
+			ratio = raw.TransmissionGearRatio.Gear[i]
+		}
+		f32(ratio)
+	}
+
+	p = binary.LittleEndian.AppendUint32(p, raw.VehicleId)
+
+	if raw.Motion == nil {
+		return p
+	}
+	f32(raw.Motion.WheelRotation)
+	p = append(p, make([]byte, 4)...)
+	f32(raw.Motion.Sway)
+	f32(raw.Motion.Heave)
+	f32(raw.Motion.Surge)
+
+	if raw.Energy == nil {
+		return p
+	}
+	p = append(p, raw.Energy.ThrottleFiltered, raw.Energy.BrakeFiltered, 0, 0)
+	for i := range 4 {
+		torque := float32(0)
+		if i < len(raw.Energy.TorqueVector) {
+			torque = raw.Energy.TorqueVector[i]
+		}
+		f32(torque)
+	}
+	f32(raw.Energy.EnergyRecovery)
+	p = append(p, make([]byte, 4)...)
+
+	return p
+}
+
+func encodeFlags(flags *gttelemetry.GranTurismoTelemetry_Flags) uint16 {
+	if flags == nil {
+		return 0
+	}
+
+	bits := []bool{
+		flags.Live,
+		flags.GamePaused,
+		flags.Loading,
+		flags.InGear,
+		flags.HasTurbo,
+		flags.RevLimiterAlert,
+		flags.HandBrakeActive,
+		flags.HeadlightsActive,
+		flags.HighBeamActive,
+		flags.LowBeamActive,
+		flags.AsmActive,
+		flags.TcsActive,
+		flags.Flag13,
+		flags.Flag14,
+		flags.Flag15,
+		flags.Flag16,
+	}
+
+	value := uint16(0)
+	for i, set := range bits {
+		if set {
+			value |= 1 << i
+		}
+	}
+
+	return value
+}
@@ -0,0 +1,60 @@
+package telemetry
+
+// Powertrain is what drives the vehicle.
+type Powertrain string
+
+const (
+	PowertrainCombustion Powertrain = "combustion"
+	PowertrainElectric   Powertrain = "electric"
+	PowertrainHybrid     Powertrain = "hybrid"
+)
+
+// VehiclePowertrain returns what drives the vehicle, vehicles that are not in
+// the inventory are assumed to have a combustion engine.
+func (t *transformer) VehiclePowertrain() Powertrain {
+	t.updateVehicle()
+
+	switch {
+	case t.vehicle.Electric():
+		return PowertrainElectric
+	case t.vehicle.Hybrid():
+		return PowertrainHybrid
+	default:
+		return PowertrainCombustion
+	}
+}
+
+// HasCombustionEngine reports whether the fuel, boost, oil and water values
+// apply to the vehicle. The game reports them as zero or fills them with
+// other values for electric vehicles.
+func (t *transformer) HasCombustionEngine() bool {
+	return t.VehiclePowertrain() != PowertrainElectric
+}
+
+// BatteryChargePercent is the charge of the battery of an electric vehicle,
+// which the game reports in place of the fuel level. ok is false for
+// vehicles with a combustion engine.
+func (t *transformer) BatteryChargePercent() (float32, bool) {
+	if t.HasCombustionEngine() {
+		return 0, false
+	}
+
+	capacity := t.RawTelemetry.FuelCapacity
+	if capacity <= 0 {
+		return 0, false
+	}
+
+	return t.RawTelemetry.FuelLevel / capacity * 100, true
+}
+
+// EnergyRecovery is the energy recovered by regenerative braking as reported
+// by the game. ok is false for vehicles with a combustion engine alone and
+// when the console is not sending the ~ packet format.
+func (t *transformer) EnergyRecovery() (float32, bool) {
+	energy := t.RawTelemetry.Energy
+	if energy == nil || t.VehiclePowertrain() == PowertrainCombustion {
+		return 0, false
+	}
+
+	return energy.EnergyRecovery, true
+}
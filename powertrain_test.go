@@ -0,0 +1,146 @@
+package telemetry
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/vwhitteron/gt-telemetry/internal/gttelemetry"
+	"github.com/vwhitteron/gt-telemetry/vehicles"
+)
+
+type PowertrainTestSuite struct {
+	suite.Suite
+	transformer *transformer
+}
+
+func TestPowertrainTestSuite(t *testing.T) {
+	suite.Run(t, new(PowertrainTestSuite))
+}
+
+func (suite *PowertrainTestSuite) SetupTest() {
+	inventory, err := vehicles.NewInventory()
+	suite.Require().NoError(err)
+
+	suite.transformer = NewTransformer(inventory)
+	suite.transformer.RawTelemetry = newRawTelemetry()
+}
+
+func (suite *PowertrainTestSuite) TestPowertrainsAreResolvedFromTheInventory() {
+	testCases := map[string]struct {
+		vehicleID  uint32
+		want       Powertrain
+		combustion bool
+	}{
+		"combustion":      {vehicleID: 82, want: PowertrainCombustion, combustion: true},
+		"electric":        {vehicleID: 3390, want: PowertrainElectric},
+		"hybrid":          {vehicleID: 3459, want: PowertrainHybrid, combustion: true},
+		"unknown vehicle": {vehicleID: 99999, want: PowertrainCombustion, combustion: true},
+	}
+
+	for name, tc := range testCases {
+		suite.Run(name, func() {
+			// Arrange
+			suite.transformer.RawTelemetry.VehicleId = tc.vehicleID
+
+			// Act
+			powertrain := suite.transformer.VehiclePowertrain()
+			combustion := suite.transformer.HasCombustionEngine()
+
+			// Assert
+			suite.Equal(tc.want, powertrain)
+			suite.Equal(tc.combustion, combustion)
+		})
+	}
+}
+
+func (suite *PowertrainTestSuite) TestBatteryChargeIsOnlyReportedForElectricVehicles() {
+	// Arrange
+	suite.transformer.RawTelemetry.FuelLevel = 40
+	suite.transformer.RawTelemetry.FuelCapacity = 80
+
+	// Act
+	suite.transformer.RawTelemetry.VehicleId = 3390
+	charge, electricOk := suite.transformer.BatteryChargePercent()
+	suite.transformer.RawTelemetry.VehicleId = 3459
+	_, hybridOk := suite.transformer.BatteryChargePercent()
+
+	// Assert
+	suite.True(electricOk)
+	suite.Equal(float32(50), charge)
+	suite.False(hybridOk)
+}
+
+func (suite *PowertrainTestSuite) TestFuelLevelNeedsTheSizeOfTheTank() {
+	// Arrange
+	file := filepath.Join(suite.T().TempDir(), "tank.json")
+	suite.Require().NoError(os.WriteFile(file, []byte(`{"82": {"FuelTankLitres": 60}}`), 0o644))
+	inventory, err := vehicles.NewInventory(file)
+	suite.Require().NoError(err)
+	transformer := NewTransformer(inventory)
+	transformer.RawTelemetry.FuelLevel = 25
+
+	// Act
+	transformer.RawTelemetry.VehicleId = 82
+	fuel, knownOk := transformer.FuelLevel()
+	transformer.RawTelemetry.VehicleId = 3390
+	_, electricOk := transformer.FuelLevel()
+	transformer.RawTelemetry.VehicleId = 1
+	_, unknownOk := transformer.FuelLevel()
+
+	// Assert
+	suite.True(knownOk)
+	suite.Equal(Volume(15), fuel)
+	suite.False(electricOk)
+	suite.False(unknownOk)
+}
+
+func (suite *PowertrainTestSuite) TestExtendedPacketsAreDecoded() {
+	// Arrange
+	source := NewFuncSource(func(i int, frame *Frame) bool {
+		raw := &frame.RawTelemetry
+		raw.VehicleId = 3390
+		if i == 0 {
+			raw.Motion = &gttelemetry.GranTurismoTelemetry_Motion{WheelRotation: 0.25, Sway: 1.5, Heave: -0.5, Surge: 3}
+			raw.Energy = &gttelemetry.GranTurismoTelemetry_Energy{
+				ThrottleFiltered: 200,
+				BrakeFiltered:    10,
+				TorqueVector:     []float32{1, 2, 3, 4},
+				EnergyRecovery:   12.5,
+			}
+		}
+
+		return i < 2
+	})
+	client, err := NewGTClient(GTClientOpts{SourceReader: source, LogLevel: "off"})
+	suite.Require().NoError(err)
+	frames := client.Subscribe(4)
+
+	// Act
+	client.Run()
+
+	// Assert
+	received := []Frame{}
+	for frame := range frames {
+		received = append(received, frame)
+	}
+	suite.Require().Len(received, 2)
+
+	extended := received[0].RawTelemetry
+	suite.Require().NotNil(extended.Motion)
+	suite.Equal(float32(0.25), extended.Motion.WheelRotation)
+	suite.Equal(float32(3), extended.Motion.Surge)
+	suite.Require().NotNil(extended.Energy)
+	suite.Equal(uint8(200), extended.Energy.ThrottleFiltered)
+	suite.Equal([]float32{1, 2, 3, 4}, extended.Energy.TorqueVector)
+	recovery, ok := received[0].EnergyRecovery()
+	suite.True(ok)
+	suite.Equal(float32(12.5), recovery)
+
+	suite.Nil(received[1].RawTelemetry.Motion)
+	suite.Nil(received[1].RawTelemetry.Energy)
+	_, ok = received[1].EnergyRecovery()
+	suite.False(ok)
+}
@@ -0,0 +1,146 @@
+package telemetry
+
+import (
+	"math"
+	"slices"
+
+	"github.com/vwhitteron/gt-telemetry/setups"
+)
+
+// ride height is only sampled below this speed, while the suspension is
+// settled
+const setupRestSpeed = 0.1
+
+// setupTracker identifies the setup of the vehicle being driven from the
+// telemetry and records the laps driven with it.
+type setupTracker struct {
+	store       *setups.Store
+	vehicleID   uint32
+	observed    setups.Fingerprint
+	identified  bool
+	lap         int16
+	lastLaptime int32
+}
+
+func newSetupTracker(store *setups.Store) *setupTracker {
+	return &setupTracker{store: store}
+}
+
+// observe looks at a packet and sets the active setup of the transformer.
+// It returns true when the setup has changed, and created when it was not
+// seen before.
+func (s *setupTracker) observe(t *transformer) (changed bool, created bool, err error) {
+	id := t.RawTelemetry.VehicleId
+	if id != s.vehicleID {
+		s.vehicleID = id
+		s.observed = setups.Fingerprint{}
+		s.identified = false
+		s.lap = t.CurrentLap()
+		s.lastLaptime = t.RawTelemetry.LastLaptime
+		t.setup, t.setupKnown = setups.Setup{}, false
+	}
+
+	flags := t.Flags()
+	if id == 0 || flags.GamePaused || flags.Loading {
+		return false, false, nil
+	}
+
+	if err := s.recordLap(t); err != nil {
+		return false, false, err
+	}
+
+	observed, ok := s.fingerprint(t)
+	if !ok {
+		return false, false, nil
+	}
+	if s.identified && s.unchanged(observed) {
+		return false, false, nil
+	}
+
+	// a setup that fails to save is still used, and is not identified again
+	// until the observation changes so the error is only reported once
+	setup, created, err := s.store.Identify(int(id), observed)
+	s.observed = observed
+	s.identified = true
+	if setup.ID == "" {
+		t.setup, t.setupKnown = setups.Setup{}, false
+
+		return false, false, err
+	}
+
+	changed = !t.setupKnown || t.setup.ID != setup.ID
+	t.setup, t.setupKnown = setup, true
+
+	return changed, created, err
+}
+
+// unchanged reports whether the observed values match the identified setup.
+// The ride height is only seen at rest, so a packet without it matches as
+// long as the rest of the setup does.
+func (s *setupTracker) unchanged(observed setups.Fingerprint) bool {
+	if !observed.Matches(s.observed) {
+		return false
+	}
+
+	return observed.RideHeightMM == 0 || s.observed.RideHeightMM != 0
+}
+
+// fingerprint builds what can be seen of the setup in the packet, ok is false
+// before the gear ratios are known.
+func (s *setupTracker) fingerprint(t *transformer) (setups.Fingerprint, bool) {
+	transmission := t.Transmission()
+	if transmission.Gears == 0 {
+		return setups.Fingerprint{}, false
+	}
+
+	observed := setups.Fingerprint{
+		GearRatios: slices.Clone(transmission.GearRatios),
+	}
+
+	// the final drive is calculated from the top speed and tyre size
+	if t.RawTelemetry.CalculatedMaxSpeed > 0 && t.TyreRadiusMeters().RearLeft > 0 {
+		finalDrive := float64(t.DifferentialRatio())
+		if finalDrive > 0 && !math.IsInf(finalDrive, 0) {
+			observed.FinalDrive = float32(finalDrive)
+		}
+	}
+
+	if t.GroundSpeedMetersPerSecond() < setupRestSpeed && t.RideHeightMeters() > 0 {
+		observed.RideHeightMM = t.RideHeightMeters() * 1000
+	}
+
+	return observed, true
+}
+
+// recordLap stores the last lap with the active setup once the lap counter
+// has moved on and the lap time has been updated.
+func (s *setupTracker) recordLap(t *transformer) error {
+	lap := t.CurrentLap()
+	laptime := t.RawTelemetry.LastLaptime
+
+	if lap <= s.lap {
+		s.lap = lap
+		s.lastLaptime = laptime
+
+		return nil
+	}
+
+	if laptime <= 0 || laptime == s.lastLaptime {
+		return nil
+	}
+
+	completed := s.lap
+	s.lap = lap
+	s.lastLaptime = laptime
+
+	if !t.setupKnown || completed < 1 {
+		return nil
+	}
+
+	return s.store.RecordLap(setups.Lap{
+		VehicleID: int(s.vehicleID),
+		SetupID:   t.setup.ID,
+		Lap:       int(completed),
+		Time:      t.LastLaptime(),
+	})
+}
@@ -0,0 +1,170 @@
+package telemetry
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/vwhitteron/gt-telemetry/internal/gttelemetry"
+	"github.com/vwhitteron/gt-telemetry/setups"
+	"github.com/vwhitteron/gt-telemetry/vehicles"
+)
+
+type SetupTrackerTestSuite struct {
+	suite.Suite
+}
+
+func TestSetupTrackerTestSuite(t *testing.T) {
+	suite.Run(t, new(SetupTrackerTestSuite))
+}
+
+// session drives two laps with one setup, then changes the second gear and
+// drives another lap.
+func session(i int, frame *Frame) bool {
+	raw := &frame.RawTelemetry
+	raw.VehicleId = 82
+	copy(raw.TransmissionGearRatio.Gear, []float32{3.2, 2.1, 1.5, 1.2, 1.0, 0.8})
+	raw.RideHeight = 0.095
+	raw.CurrentLap = 1
+
+	switch {
+	case i < 10:
+		// waiting in the pit
+	case i < 20:
+		raw.GroundSpeed = 30
+		raw.RideHeight = 0.08
+	case i < 30:
+		raw.GroundSpeed = 30
+		raw.CurrentLap = 2
+		raw.LastLaptime = 90000
+		raw.TransmissionGearRatio.Gear[1] = 2.3
+		raw.RideHeight = 0.08
+	case i < 32:
+		raw.GroundSpeed = 30
+		raw.CurrentLap = 3
+		raw.LastLaptime = 89000
+		raw.TransmissionGearRatio.Gear[1] = 2.3
+	default:
+		return false
+	}
+
+	return true
+}
+
+func (suite *SetupTrackerTestSuite) TestSetupChangesAndLapsAreTracked() {
+	// Arrange
+	file := filepath.Join(suite.T().TempDir(), "setups.json")
+	client, err := NewGTClient(GTClientOpts{SourceReader: NewFuncSource(session), LogLevel: "off", Setups: file})
+	suite.Require().NoError(err)
+	events := client.Events(8)
+	frames := client.Subscribe(64)
+
+	// Act
+	client.Run()
+
+	// Assert
+	received := []StreamEvent{}
+	for event := range events {
+		received = append(received, event)
+	}
+	suite.Require().Len(received, 2)
+	for _, event := range received {
+		suite.Equal(SetupChanged, event.Type)
+		suite.Equal(uint32(82), event.VehicleID)
+	}
+	first, second := received[0].SetupID, received[1].SetupID
+	suite.NotEqual(first, second)
+
+	var last Frame
+	for frame := range frames {
+		last = frame
+	}
+	active, ok := last.ActiveSetup()
+	suite.Require().True(ok)
+	suite.Equal(second, active.ID)
+
+	store, err := setups.Open(file)
+	suite.Require().NoError(err)
+	setup, err := store.Get(first)
+	suite.Require().NoError(err)
+	suite.Require().NotNil(setup.Fingerprint)
+	suite.InDelta(95, setup.Fingerprint.RideHeightMM, 0.01)
+
+	firstLaps := store.Laps(first)
+	suite.Require().Len(firstLaps, 1)
+	suite.Equal(1, firstLaps[0].Lap)
+	suite.Equal(90*time.Second, firstLaps[0].Time)
+
+	secondLaps := store.Laps(second)
+	suite.Require().Len(secondLaps, 1)
+	suite.Equal(2, secondLaps[0].Lap)
+	suite.Equal(89*time.Second, secondLaps[0].Time)
+}
+
+func (suite *SetupTrackerTestSuite) TestSetupsThatFailToSaveAreOnlyReportedOnce() {
+	// Arrange
+	dir := filepath.Join(suite.T().TempDir(), "setups")
+	suite.Require().NoError(os.Mkdir(dir, 0o755))
+	store, err := setups.Open(filepath.Join(dir, "setups.json"))
+	suite.Require().NoError(err)
+	suite.Require().NoError(os.Remove(dir))
+	tracker := newSetupTracker(store)
+	transformer := NewTransformer(&vehicles.Inventory{})
+	transformer.RawTelemetry.VehicleId = 82
+	transformer.RawTelemetry.TransmissionGearRatio = &gttelemetry.GranTurismoTelemetry_GearRatio{
+		Gear: []float32{3.2, 2.1, 1.5, 1.2, 1.0, 0.8, 0, 0},
+	}
+
+	// Act
+	errs := 0
+	changes := 0
+	for range 10 {
+		changed, _, err := tracker.observe(transformer)
+		if err != nil {
+			errs++
+		}
+		if changed {
+			changes++
+		}
+	}
+
+	// Assert
+	suite.Equal(1, errs)
+	suite.Equal(1, changes)
+	suite.True(transformer.setupKnown)
+}
+
+func (suite *SetupTrackerTestSuite) TestDrivingAgainFindsTheSameSetup() {
+	// Arrange
+	file := filepath.Join(suite.T().TempDir(), "setups.json")
+	drive := func() string {
+		client, err := NewGTClient(GTClientOpts{SourceReader: NewFuncSource(func(i int, frame *Frame) bool {
+			return session(i, frame) && i < 10
+		}), LogLevel: "off", Setups: file})
+		suite.Require().NoError(err)
+		frames := client.Subscribe(64)
+		client.Run()
+
+		var last Frame
+		for frame := range frames {
+			last = frame
+		}
+		setup, ok := last.ActiveSetup()
+		suite.Require().True(ok)
+
+		return setup.ID
+	}
+
+	// Act
+	first := drive()
+	second := drive()
+
+	// Assert
+	suite.Equal(first, second)
+	store, err := setups.Open(file)
+	suite.Require().NoError(err)
+	suite.Len(store.Setups(82), 1)
+}
@@ -0,0 +1,192 @@
+package setups
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// ErrSetupNotFound is returned when a setup ID is not in the store.
+var ErrSetupNotFound = errors.New("setup not found")
+
+// Setup is a tuning sheet of a vehicle. Values that were not recorded are
+// zero.
+type Setup struct {
+	ID        string
+	VehicleID int
+	Name      string
+	Notes     string `json:",omitempty"`
+	Created   time.Time
+
+	GearRatios []float32 `json:",omitempty"`
+	FinalDrive float32   `json:",omitempty"`
+
+	RideHeightFrontMM float32 `json:",omitempty"`
+	RideHeightRearMM  float32 `json:",omitempty"`
+	// Spring rates are the natural frequency of the suspension.
+	SpringRateFrontHz      float32 `json:",omitempty"`
+	SpringRateRearHz       float32 `json:",omitempty"`
+	DamperCompressionFront float32 `json:",omitempty"`
+	DamperCompressionRear  float32 `json:",omitempty"`
+	DamperExpansionFront   float32 `json:",omitempty"`
+	DamperExpansionRear    float32 `json:",omitempty"`
+
+	LSDInitialTorque           float32 `json:",omitempty"`
+	LSDAccelerationSensitivity float32 `json:",omitempty"`
+	LSDBrakingSensitivity      float32 `json:",omitempty"`
+
+	DownforceFront float32 `json:",omitempty"`
+	DownforceRear  float32 `json:",omitempty"`
+
+	BallastKG float32 `json:",omitempty"`
+	// BallastPosition runs from -50 at the rear to 50 at the front.
+	BallastPosition float32 `json:",omitempty"`
+
+	// Fingerprint is how the setup appears in the telemetry, it is set once
+	// the setup has been driven.
+	Fingerprint *Fingerprint `json:",omitempty"`
+}
+
+func (s *Setup) validate() error {
+	errs := []error{}
+
+	if s.VehicleID < 0 {
+		errs = append(errs, fmt.Errorf("vehicle ID %d is negative", s.VehicleID))
+	}
+	if s.Name == "" {
+		errs = append(errs, errors.New("name is missing"))
+	}
+
+	values := []struct {
+		name  string
+		value float32
+	}{
+		{"FinalDrive", s.FinalDrive},
+		{"RideHeightFrontMM", s.RideHeightFrontMM},
+		{"RideHeightRearMM", s.RideHeightRearMM},
+		{"SpringRateFrontHz", s.SpringRateFrontHz},
+		{"SpringRateRearHz", s.SpringRateRearHz},
+		{"DamperCompressionFront", s.DamperCompressionFront},
+		{"DamperCompressionRear", s.DamperCompressionRear},
+		{"DamperExpansionFront", s.DamperExpansionFront},
+		{"DamperExpansionRear", s.DamperExpansionRear},
+		{"LSDInitialTorque", s.LSDInitialTorque},
+		{"LSDAccelerationSensitivity", s.LSDAccelerationSensitivity},
+		{"LSDBrakingSensitivity", s.LSDBrakingSensitivity},
+		{"DownforceFront", s.DownforceFront},
+		{"DownforceRear", s.DownforceRear},
+		{"BallastKG", s.BallastKG},
+	}
+	for _, v := range values {
+		if v.value < 0 {
+			errs = append(errs, fmt.Errorf("%s %g is negative", v.name, v.value))
+		}
+	}
+	for gear, ratio := range s.GearRatios {
+		if ratio < 0 {
+			errs = append(errs, fmt.Errorf("gear %d ratio %g is negative", gear+1, ratio))
+		}
+	}
+	if s.BallastPosition < -50 || s.BallastPosition > 50 {
+		errs = append(errs, fmt.Errorf("BallastPosition %g is not between -50 and 50", s.BallastPosition))
+	}
+
+	return errors.Join(errs...)
+}
+
+// sheet returns the part of the tuning sheet that can be seen in the
+// telemetry, ok is false when none of it was recorded.
+func (s *Setup) sheet() (Fingerprint, bool) {
+	sheet := Fingerprint{
+		GearRatios: gears(s.GearRatios),
+		FinalDrive: s.FinalDrive,
+	}
+
+	return sheet, len(sheet.GearRatios) > 0 || sheet.FinalDrive > 0
+}
+
+// Fingerprint is the part of a setup that can be observed in the telemetry.
+// Values that have not been observed are zero.
+type Fingerprint struct {
+	GearRatios []float32 `json:",omitempty"`
+	FinalDrive float32   `json:",omitempty"`
+	// RideHeightMM is the ride height of the vehicle at rest.
+	RideHeightMM float32 `json:",omitempty"`
+}
+
+// Tolerances for observed values, the final drive is calculated from the top
+// speed and tyre size so it is less precise than the gear ratios.
+const (
+	gearRatioTolerance  = 0.005
+	finalDriveTolerance = 0.01
+	rideHeightTolerance = 3
+)
+
+// Matches reports whether two fingerprints could be the same setup. Only the
+// values observed in both are compared.
+func (f Fingerprint) Matches(other Fingerprint) bool {
+	a, b := gears(f.GearRatios), gears(other.GearRatios)
+	if len(a) > 0 && len(b) > 0 {
+		if len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !within(a[i], b[i], gearRatioTolerance) {
+				return false
+			}
+		}
+	}
+
+	if f.FinalDrive > 0 && other.FinalDrive > 0 && !within(f.FinalDrive, other.FinalDrive, finalDriveTolerance) {
+		return false
+	}
+
+	if f.RideHeightMM > 0 && other.RideHeightMM > 0 && math.Abs(float64(f.RideHeightMM-other.RideHeightMM)) > rideHeightTolerance {
+		return false
+	}
+
+	return true
+}
+
+// merge fills the values of the fingerprint that were not observed before.
+func (f Fingerprint) merge(other Fingerprint) (Fingerprint, bool) {
+	changed := false
+	if len(gears(f.GearRatios)) == 0 && len(gears(other.GearRatios)) > 0 {
+		f.GearRatios = gears(other.GearRatios)
+		changed = true
+	}
+	if f.FinalDrive == 0 && other.FinalDrive > 0 {
+		f.FinalDrive = other.FinalDrive
+		changed = true
+	}
+	if f.RideHeightMM == 0 && other.RideHeightMM > 0 {
+		f.RideHeightMM = other.RideHeightMM
+		changed = true
+	}
+
+	return f, changed
+}
+
+// gears drops the unused gears from the end of a list of ratios.
+func gears(ratios []float32) []float32 {
+	n := len(ratios)
+	for n > 0 && ratios[n-1] <= 0 {
+		n--
+	}
+
+	return ratios[:n]
+}
+
+func within(a float32, b float32, tolerance float64) bool {
+	return math.Abs(float64(a-b)) <= tolerance*math.Abs(float64(b))
+}
+
+// Lap is a lap driven with a setup.
+type Lap struct {
+	VehicleID int
+	SetupID   string
+	Lap       int
+	Time      time.Duration
+	Recorded  time.Time
+}
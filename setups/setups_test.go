@@ -0,0 +1,103 @@
+package setups
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type SetupsTestSuite struct {
+	suite.Suite
+}
+
+func TestSetupsTestSuite(t *testing.T) {
+	suite.Run(t, new(SetupsTestSuite))
+}
+
+func (suite *SetupsTestSuite) TestFingerprintsMatchWithinTolerances() {
+	base := Fingerprint{
+		GearRatios:   []float32{3.2, 2.1, 1.5, 1.2, 1.0, 0.8, 0, 0},
+		FinalDrive:   4.1,
+		RideHeightMM: 95,
+	}
+
+	testCases := map[string]struct {
+		other   Fingerprint
+		matches bool
+	}{
+		"identical": {
+			other:   base,
+			matches: true,
+		},
+		"small differences": {
+			other:   Fingerprint{GearRatios: []float32{3.201, 2.1, 1.5, 1.2, 1.0, 0.8}, FinalDrive: 4.12, RideHeightMM: 97},
+			matches: true,
+		},
+		"unobserved values": {
+			other:   Fingerprint{GearRatios: []float32{3.2, 2.1, 1.5, 1.2, 1.0, 0.8}},
+			matches: true,
+		},
+		"different gear ratio": {
+			other:   Fingerprint{GearRatios: []float32{3.2, 2.2, 1.5, 1.2, 1.0, 0.8}},
+			matches: false,
+		},
+		"different gear count": {
+			other:   Fingerprint{GearRatios: []float32{3.2, 2.1, 1.5, 1.2, 1.0}},
+			matches: false,
+		},
+		"different final drive": {
+			other:   Fingerprint{FinalDrive: 3.9},
+			matches: false,
+		},
+		"different ride height": {
+			other:   Fingerprint{RideHeightMM: 85},
+			matches: false,
+		},
+	}
+
+	for name, tc := range testCases {
+		suite.Run(name, func() {
+			// Act
+			forward := base.Matches(tc.other)
+			backward := tc.other.Matches(base)
+
+			// Assert
+			suite.Equal(tc.matches, forward)
+			suite.Equal(tc.matches, backward)
+		})
+	}
+}
+
+func (suite *SetupsTestSuite) TestInvalidSetupsAreRejected() {
+	testCases := map[string]struct {
+		setup Setup
+		err   string
+	}{
+		"missing name": {
+			setup: Setup{VehicleID: 82},
+			err:   "name is missing",
+		},
+		"negative value": {
+			setup: Setup{VehicleID: 82, Name: "Wet", SpringRateFrontHz: -1},
+			err:   "SpringRateFrontHz -1 is negative",
+		},
+		"negative gear ratio": {
+			setup: Setup{VehicleID: 82, Name: "Wet", GearRatios: []float32{3, -2}},
+			err:   "gear 2 ratio -2 is negative",
+		},
+		"ballast position": {
+			setup: Setup{VehicleID: 82, Name: "Wet", BallastPosition: 60},
+			err:   "BallastPosition 60 is not between -50 and 50",
+		},
+	}
+
+	for name, tc := range testCases {
+		suite.Run(name, func() {
+			// Act
+			err := tc.setup.validate()
+
+			// Assert
+			suite.ErrorContains(err, tc.err)
+		})
+	}
+}
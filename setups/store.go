@@ -0,0 +1,288 @@
+package setups
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/vwhitteron/gt-telemetry/internal/utils"
+)
+
+// Store keeps the setups of every vehicle and the laps driven with them in a
+// JSON file. A store without a file keeps them in memory only.
+type Store struct {
+	file   string
+	mu     sync.RWMutex
+	setups []Setup
+	laps   []Lap
+}
+
+type storeFile struct {
+	Setups []Setup
+	Laps   []Lap
+}
+
+// Open loads the setups in the file, a missing file is created when the
+// first setup is stored.
+func Open(file string) (*Store, error) {
+	store := &Store{file: file}
+	if file == "" {
+		return store, nil
+	}
+
+	data, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read setups: %w", err)
+	}
+
+	contents := storeFile{}
+	if err := json.Unmarshal(data, &contents); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal setups %s: %w", file, err)
+	}
+
+	errs := []error{}
+	ids := map[string]bool{}
+	for _, setup := range contents.Setups {
+		if ids[setup.ID] {
+			errs = append(errs, fmt.Errorf("%s: setup %s: ID is used more than once", file, setup.ID))
+		}
+		ids[setup.ID] = true
+
+		if err := setup.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: setup %s: %w", file, setup.ID, err))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	store.setups = contents.Setups
+	store.laps = contents.Laps
+
+	return store, nil
+}
+
+// Add stores a new setup and returns it with its ID, which is generated from
+// the vehicle ID when it is empty.
+func (s *Store) Add(setup Setup) (Setup, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.add(setup)
+}
+
+func (s *Store) add(setup Setup) (Setup, error) {
+	if setup.ID == "" {
+		setup.ID = s.nextID(setup.VehicleID)
+	}
+	if _, ok := s.index(setup.ID); ok {
+		return Setup{}, fmt.Errorf("setup %s already exists", setup.ID)
+	}
+	if setup.Created.IsZero() {
+		setup.Created = time.Now()
+	}
+	if err := setup.validate(); err != nil {
+		return Setup{}, fmt.Errorf("setup %s: %w", setup.ID, err)
+	}
+
+	s.setups = append(s.setups, setup)
+
+	return setup, s.save()
+}
+
+// Update replaces a stored setup with the same ID.
+func (s *Store) Update(setup Setup) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i, ok := s.index(setup.ID)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrSetupNotFound, setup.ID)
+	}
+	if err := setup.validate(); err != nil {
+		return fmt.Errorf("setup %s: %w", setup.ID, err)
+	}
+
+	s.setups[i] = setup
+
+	return s.save()
+}
+
+// Remove deletes a setup, the laps driven with it are kept.
+func (s *Store) Remove(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i, ok := s.index(id)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrSetupNotFound, id)
+	}
+
+	s.setups = append(s.setups[:i], s.setups[i+1:]...)
+
+	return s.save()
+}
+
+func (s *Store) Get(id string) (Setup, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	i, ok := s.index(id)
+	if !ok {
+		return Setup{}, fmt.Errorf("%w: %s", ErrSetupNotFound, id)
+	}
+
+	return s.setups[i], nil
+}
+
+// Setups returns the setups of a vehicle in the order they were created.
+func (s *Store) Setups(vehicleID int) []Setup {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	setups := []Setup{}
+	for _, setup := range s.setups {
+		if setup.VehicleID == vehicleID {
+			setups = append(setups, setup)
+		}
+	}
+	sort.SliceStable(setups, func(a, b int) bool {
+		return setups[a].Created.Before(setups[b].Created)
+	})
+
+	return setups
+}
+
+// Identify finds the setup of a vehicle matching what was observed in the
+// telemetry. A setup that has been driven before is matched by its
+// fingerprint, otherwise by its tuning sheet. When nothing matches a new
+// setup is added for the user to fill in, and created is true.
+func (s *Store) Identify(vehicleID int, observed Fingerprint) (setup Setup, created bool, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	observed.GearRatios = slices.Clone(gears(observed.GearRatios))
+
+	// the most recently created setup wins when several match
+	for i := len(s.setups) - 1; i >= 0; i-- {
+		setup := &s.setups[i]
+		if setup.VehicleID != vehicleID || setup.Fingerprint == nil || !setup.Fingerprint.Matches(observed) {
+			continue
+		}
+
+		if merged, changed := setup.Fingerprint.merge(observed); changed {
+			setup.Fingerprint = &merged
+
+			return *setup, false, s.save()
+		}
+
+		return *setup, false, nil
+	}
+
+	for i := len(s.setups) - 1; i >= 0; i-- {
+		setup := &s.setups[i]
+		if setup.VehicleID != vehicleID || setup.Fingerprint != nil {
+			continue
+		}
+
+		sheet, ok := setup.sheet()
+		if !ok || !sheet.Matches(observed) {
+			continue
+		}
+
+		setup.Fingerprint = &observed
+
+		return *setup, false, s.save()
+	}
+
+	now := time.Now()
+	detected, err := s.add(Setup{
+		VehicleID:   vehicleID,
+		Name:        "Detected " + now.Format("2006-01-02 15:04"),
+		Created:     now,
+		GearRatios:  observed.GearRatios,
+		FinalDrive:  observed.FinalDrive,
+		Fingerprint: &observed,
+	})
+
+	return detected, err == nil, err
+}
+
+// RecordLap stores a lap driven with a setup.
+func (s *Store) RecordLap(lap Lap) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.index(lap.SetupID); !ok {
+		return fmt.Errorf("%w: %s", ErrSetupNotFound, lap.SetupID)
+	}
+	if lap.Recorded.IsZero() {
+		lap.Recorded = time.Now()
+	}
+
+	s.laps = append(s.laps, lap)
+
+	return s.save()
+}
+
+// Laps returns the laps driven with a setup in the order they were recorded.
+func (s *Store) Laps(setupID string) []Lap {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	laps := []Lap{}
+	for _, lap := range s.laps {
+		if lap.SetupID == setupID {
+			laps = append(laps, lap)
+		}
+	}
+
+	return laps
+}
+
+// index must be called with the lock held.
+func (s *Store) index(id string) (int, bool) {
+	for i, setup := range s.setups {
+		if setup.ID == id {
+			return i, true
+		}
+	}
+
+	return 0, false
+}
+
+// nextID must be called with the lock held.
+func (s *Store) nextID(vehicleID int) string {
+	for n := 1; ; n++ {
+		id := fmt.Sprintf("%d-%d", vehicleID, n)
+		if _, ok := s.index(id); !ok {
+			return id
+		}
+	}
+}
+
+// save writes the store to its file. It must be called with the lock held.
+func (s *Store) save() error {
+	if s.file == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(storeFile{Setups: s.setups, Laps: s.laps}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal setups: %w", err)
+	}
+
+	if err := utils.WriteFileAtomic(s.file, append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write setups: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,160 @@
+package setups
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type StoreTestSuite struct {
+	suite.Suite
+	file  string
+	store *Store
+}
+
+func TestStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(StoreTestSuite))
+}
+
+func (suite *StoreTestSuite) SetupTest() {
+	suite.file = filepath.Join(suite.T().TempDir(), "setups.json")
+
+	store, err := Open(suite.file)
+	suite.Require().NoError(err)
+	suite.store = store
+}
+
+var sixSpeed = []float32{3.2, 2.1, 1.5, 1.2, 1.0, 0.8, 0, 0}
+
+func (suite *StoreTestSuite) TestSetupsArePersisted() {
+	// Arrange
+	sheet := Setup{
+		VehicleID:         82,
+		Name:              "Qualifying",
+		GearRatios:        []float32{3.2, 2.1, 1.5, 1.2, 1.0, 0.8},
+		FinalDrive:        4.1,
+		RideHeightFrontMM: 90,
+		RideHeightRearMM:  95,
+		LSDInitialTorque:  10,
+		DownforceFront:    300,
+		BallastKG:         20,
+		BallastPosition:   -10,
+	}
+
+	// Act
+	added, err := suite.store.Add(sheet)
+	suite.Require().NoError(err)
+	suite.Require().NoError(suite.store.RecordLap(Lap{VehicleID: 82, SetupID: added.ID, Lap: 1, Time: 92 * time.Second}))
+	reopened, err := Open(suite.file)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal("82-1", added.ID)
+	suite.False(added.Created.IsZero())
+
+	stored, err := reopened.Get(added.ID)
+	suite.Require().NoError(err)
+	suite.Equal(sheet.Name, stored.Name)
+	suite.Equal(sheet.GearRatios, stored.GearRatios)
+	suite.Equal(sheet.BallastPosition, stored.BallastPosition)
+
+	laps := reopened.Laps(added.ID)
+	suite.Require().Len(laps, 1)
+	suite.Equal(92*time.Second, laps[0].Time)
+	suite.False(laps[0].Recorded.IsZero())
+}
+
+func (suite *StoreTestSuite) TestSetupsAreListedByVehicle() {
+	// Arrange
+	_, err := suite.store.Add(Setup{VehicleID: 82, Name: "Dry"})
+	suite.Require().NoError(err)
+	_, err = suite.store.Add(Setup{VehicleID: 1234, Name: "Other car"})
+	suite.Require().NoError(err)
+	_, err = suite.store.Add(Setup{VehicleID: 82, Name: "Wet"})
+	suite.Require().NoError(err)
+
+	// Act
+	setups := suite.store.Setups(82)
+
+	// Assert
+	suite.Require().Len(setups, 2)
+	suite.Equal("82-1", setups[0].ID)
+	suite.Equal("Dry", setups[0].Name)
+	suite.Equal("82-2", setups[1].ID)
+	suite.Equal("Wet", setups[1].Name)
+}
+
+func (suite *StoreTestSuite) TestUnknownSetupsAreReported() {
+	// Act
+	_, getErr := suite.store.Get("82-9")
+	removeErr := suite.store.Remove("82-9")
+	lapErr := suite.store.RecordLap(Lap{SetupID: "82-9"})
+
+	// Assert
+	suite.True(errors.Is(getErr, ErrSetupNotFound))
+	suite.True(errors.Is(removeErr, ErrSetupNotFound))
+	suite.True(errors.Is(lapErr, ErrSetupNotFound))
+}
+
+func (suite *StoreTestSuite) TestUnseenSetupsAreDetected() {
+	// Act
+	first, firstCreated, err := suite.store.Identify(82, Fingerprint{GearRatios: sixSpeed, FinalDrive: 4.1})
+	suite.Require().NoError(err)
+	again, againCreated, err := suite.store.Identify(82, Fingerprint{GearRatios: sixSpeed, FinalDrive: 4.11, RideHeightMM: 95})
+	suite.Require().NoError(err)
+	changed, changedCreated, err := suite.store.Identify(82, Fingerprint{GearRatios: sixSpeed, FinalDrive: 3.8})
+	suite.Require().NoError(err)
+
+	// Assert
+	suite.True(firstCreated)
+	suite.Equal([]float32{3.2, 2.1, 1.5, 1.2, 1.0, 0.8}, first.GearRatios)
+	suite.False(againCreated)
+	suite.Equal(first.ID, again.ID)
+	suite.Equal(float32(95), again.Fingerprint.RideHeightMM)
+	suite.True(changedCreated)
+	suite.NotEqual(first.ID, changed.ID)
+	suite.Len(suite.store.Setups(82), 2)
+}
+
+func (suite *StoreTestSuite) TestRecordedSheetsAreMatchedWhenFirstDriven() {
+	// Arrange
+	sheet, err := suite.store.Add(Setup{VehicleID: 82, Name: "Long gears", GearRatios: []float32{3.0, 2.0, 1.4, 1.1, 0.9, 0.7}})
+	suite.Require().NoError(err)
+	_, err = suite.store.Add(Setup{VehicleID: 82, Name: "Untouched sheet"})
+	suite.Require().NoError(err)
+
+	// Act
+	short, shortCreated, err := suite.store.Identify(82, Fingerprint{GearRatios: sixSpeed})
+	suite.Require().NoError(err)
+	long, longCreated, err := suite.store.Identify(82, Fingerprint{GearRatios: []float32{3.0, 2.0, 1.4, 1.1, 0.9, 0.7, 0, 0}, RideHeightMM: 100})
+	suite.Require().NoError(err)
+
+	// Assert
+	suite.True(shortCreated)
+	suite.NotEqual(sheet.ID, short.ID)
+	suite.False(longCreated)
+	suite.Equal(sheet.ID, long.ID)
+
+	stored, err := suite.store.Get(sheet.ID)
+	suite.Require().NoError(err)
+	suite.Require().NotNil(stored.Fingerprint)
+	suite.Equal(float32(100), stored.Fingerprint.RideHeightMM)
+}
+
+func (suite *StoreTestSuite) TestInvalidFilesAreRejected() {
+	// Arrange
+	content := `{"Setups": [{"ID": "82-1", "VehicleID": 82, "Name": "Dry"}, {"ID": "82-1", "VehicleID": 82, "Name": "", "BallastKG": -5}]}`
+	suite.Require().NoError(os.WriteFile(suite.file, []byte(content), 0o644))
+
+	// Act
+	_, err := Open(suite.file)
+
+	// Assert
+	suite.ErrorContains(err, "setup 82-1: ID is used more than once")
+	suite.ErrorContains(err, "setup 82-1: name is missing")
+	suite.ErrorContains(err, "BallastKG -5 is negative")
+}
@@ -0,0 +1,301 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/vwhitteron/gt-telemetry/internal/telemetrysrc"
+)
+
+// Source provides telemetry packets to the client. Read returns the length
+// and content of the next deciphered packet, and io.EOF once the source has no
+// more packets so the client stops running.
+type Source interface {
+	Read() (int, []byte, error)
+	Close() error
+}
+
+// SourceFactory creates a source from the client's source URL. Options for
+// the source are taken from the URL query parameters.
+type SourceFactory func(sourceURL *url.URL, log zerolog.Logger) (Source, error)
+
+var (
+	sourcesMu sync.RWMutex
+	sources   = map[string]SourceFactory{
+		"udp":   newUDPSource,
+		"file":  newFileSource,
+		"relay": newRelaySource,
+		"pcap":  newPcapSource,
+	}
+)
+
+// RegisterSource makes a source available to clients for URLs with the given
+// scheme, replacing any existing factory for the scheme.
+func RegisterSource(scheme string, factory SourceFactory) {
+	if scheme == "" || factory == nil {
+		panic("telemetry: RegisterSource requires a scheme and factory")
+	}
+
+	sourcesMu.Lock()
+	defer sourcesMu.Unlock()
+
+	sources[strings.ToLower(scheme)] = factory
+}
+
+// SourceSchemes returns the URL schemes of the registered sources.
+func SourceSchemes() []string {
+	sourcesMu.RLock()
+	defer sourcesMu.RUnlock()
+
+	schemes := make([]string, 0, len(sources))
+	for scheme := range sources {
+		schemes = append(schemes, scheme)
+	}
+	sort.Strings(schemes)
+
+	return schemes
+}
+
+func lookupSource(scheme string) (SourceFactory, error) {
+	sourcesMu.RLock()
+	defer sourcesMu.RUnlock()
+
+	factory, ok := sources[strings.ToLower(scheme)]
+	if !ok {
+		return nil, fmt.Errorf("unknown source URL scheme %q", scheme)
+	}
+
+	return factory, nil
+}
+
+func openSource(source string, log zerolog.Logger) (Source, error) {
+	sourceURL, err := url.Parse(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse source URL: %w", err)
+	}
+
+	factory, err := lookupSource(sourceURL.Scheme)
+	if err != nil {
+		return nil, err
+	}
+
+	return factory(sourceURL, log)
+}
+
+// isRealtime reports whether packets from a source arrive at the console's
+// packet rate, replay files can be read as fast as possible with
+// realtime=false.
+func isRealtime(source string) bool {
+	sourceURL, err := url.Parse(source)
+	if err != nil {
+		return true
+	}
+
+	return sourceURL.Query().Get("realtime") != "false"
+}
+
+func splitHostPort(sourceURL *url.URL) (string, int, error) {
+	host, portStr, err := net.SplitHostPort(sourceURL.Host)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to parse source address: %w", err)
+	}
+
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to parse port: %w", err)
+	}
+
+	return host, port, nil
+}
+
+func newUDPSource(sourceURL *url.URL, log zerolog.Logger) (Source, error) {
+	if sourceURL.Hostname() == "auto" {
+		return newAutoUDPSource(sourceURL, log)
+	}
+
+	host, port, err := splitHostPort(sourceURL)
+	if err != nil {
+		return nil, err
+	}
+
+	opts, err := udpOptions(sourceURL.Query())
+	if err != nil {
+		return nil, err
+	}
+	// only a relay sends deciphered packets, so the option is not shared
+	// with the console connection of relay sources
+	opts.Decrypted = sourceURL.Query().Get("decrypted") == "true"
+
+	reader, err := telemetrysrc.NewNetworkUDPReader(host, port, opts, log)
+	if err != nil {
+		return nil, err
+	}
+
+	return reader, nil
+}
+
+// udpOptions reads the console connection options from source URL query
+// parameters.
+func udpOptions(query url.Values) (telemetrysrc.UDPOptions, error) {
+	opts := telemetrysrc.UDPOptions{
+		BindAddress:  query.Get("bind"),
+		Interface:    query.Get("interface"),
+		PacketFormat: query.Get("packet"),
+	}
+
+	if value := query.Get("receive_port"); value != "" {
+		port, err := strconv.ParseUint(value, 10, 16)
+		if err != nil {
+			return opts, fmt.Errorf("failed to parse receive port: %w", err)
+		}
+		opts.ReceivePort = int(port)
+	}
+
+	durations := map[string]*time.Duration{
+		"heartbeat":     &opts.HeartbeatInterval,
+		"stall_timeout": &opts.StallTimeout,
+		"max_backoff":   &opts.MaxBackoff,
+	}
+	for name, duration := range durations {
+		value := query.Get(name)
+		if value == "" {
+			continue
+		}
+
+		var err error
+		*duration, err = time.ParseDuration(value)
+		if err != nil {
+			return opts, fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+	}
+
+	return opts, nil
+}
+
+// newAutoUDPSource discovers a console on the local network and reads from
+// the first one that replies.
+func newAutoUDPSource(sourceURL *url.URL, log zerolog.Logger) (Source, error) {
+	port := defaultConsolePort
+	if sourceURL.Port() != "" {
+		var err error
+		port, err = strconv.Atoi(sourceURL.Port())
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse port: %w", err)
+		}
+	}
+
+	timeout := defaultDiscoveryTimeout
+	if value := sourceURL.Query().Get("timeout"); value != "" {
+		var err error
+		timeout, err = time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse discovery timeout: %w", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	opts, err := udpOptions(sourceURL.Query())
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info().Msg("discovering PlayStation on the local network")
+	consoles, err := discover(ctx, port, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(consoles) == 0 {
+		return nil, ErrConsoleNotFound
+	}
+	log.Info().Str("address", consoles[0]).Msg("discovered PlayStation")
+
+	reader, err := telemetrysrc.NewNetworkUDPReader(consoles[0], port, opts, log)
+	if err != nil {
+		return nil, err
+	}
+
+	return reader, nil
+}
+
+func newFileSource(sourceURL *url.URL, log zerolog.Logger) (Source, error) {
+	realtime := sourceURL.Query().Get("realtime") != "false"
+
+	reader, err := telemetrysrc.NewFileReader(sourceURL.Host+sourceURL.Path, realtime, log)
+	if err != nil {
+		return nil, err
+	}
+
+	return reader, nil
+}
+
+func newRelaySource(sourceURL *url.URL, log zerolog.Logger) (Source, error) {
+	host, port, err := splitHostPort(sourceURL)
+	if err != nil {
+		return nil, err
+	}
+
+	query := sourceURL.Query()
+	console, err := udpOptions(query)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := telemetrysrc.RelayOptions{
+		ListenAddress: query.Get("listen"),
+		Decrypted:     query.Get("decrypted") == "true",
+		Console:       console,
+	}
+	if timeout := query.Get("timeout"); timeout != "" {
+		opts.SubscriberTimeout, err = time.ParseDuration(timeout)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse subscriber timeout: %w", err)
+		}
+	}
+
+	reader, err := telemetrysrc.NewRelayReader(host, port, opts, log)
+	if err != nil {
+		return nil, err
+	}
+
+	return reader, nil
+}
+
+func newPcapSource(sourceURL *url.URL, log zerolog.Logger) (Source, error) {
+	query := sourceURL.Query()
+
+	var ports []uint16
+	if list := query.Get("ports"); list != "" {
+		for _, value := range strings.Split(list, ",") {
+			port, err := strconv.ParseUint(strings.TrimSpace(value), 10, 16)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse capture port %q: %w", value, err)
+			}
+			ports = append(ports, uint16(port))
+		}
+	}
+
+	fh, err := os.Open(sourceURL.Host + sourceURL.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open capture: %w", err)
+	}
+
+	reader, err := telemetrysrc.NewPcapReader(fh, ports, query.Get("realtime") != "false", log)
+	if err != nil {
+		fh.Close()
+		return nil, err
+	}
+
+	return &fileSource{Source: reader, file: fh}, nil
+}
@@ -0,0 +1,170 @@
+package telemetry
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/vwhitteron/gt-telemetry/internal/gttelemetry"
+	"github.com/vwhitteron/gt-telemetry/internal/telemetrysrc"
+	"github.com/vwhitteron/gt-telemetry/vehicles"
+)
+
+// realtimeSource is implemented by sources that know whether their packets
+// arrive at the console packet rate.
+type realtimeSource interface {
+	Realtime() bool
+}
+
+// PacketSource delivers a fixed set of deciphered packets and then ends.
+type PacketSource struct {
+	packets [][]byte
+	next    int
+}
+
+func NewPacketSource(packets [][]byte) *PacketSource {
+	return &PacketSource{
+		packets: packets,
+	}
+}
+
+func (s *PacketSource) Read() (int, []byte, error) {
+	if s.next >= len(s.packets) {
+		return 0, nil, io.EOF
+	}
+
+	packet := s.packets[s.next]
+	s.next++
+
+	return len(packet), packet, nil
+}
+
+func (s *PacketSource) Realtime() bool {
+	return false
+}
+
+func (s *PacketSource) Close() error {
+	return nil
+}
+
+// FuncSource builds packets with a function, which is useful for feeding
+// deterministic telemetry into a client. The function is called with the
+// packet index and a frame with the sequence ID already set, and returns
+// false to end the source.
+type FuncSource struct {
+	fn    func(i int, frame *Frame) bool
+	index int
+}
+
+func NewFuncSource(fn func(i int, frame *Frame) bool) *FuncSource {
+	return &FuncSource{
+		fn: fn,
+	}
+}
+
+func (s *FuncSource) Read() (int, []byte, error) {
+	frame := NewFrame(NewTransformer(&vehicles.Inventory{}), time.Now())
+	frame.RawTelemetry = newRawTelemetry()
+	frame.RawTelemetry.SequenceId = uint32(s.index)
+
+	if !s.fn(s.index, &frame) {
+		return 0, nil, io.EOF
+	}
+	s.index++
+
+	packet := encodePacket(&frame.RawTelemetry)
+
+	return len(packet), packet, nil
+}
+
+func (s *FuncSource) Realtime() bool {
+	return false
+}
+
+func (s *FuncSource) Close() error {
+	return nil
+}
+
+// NewReaderSource reads a plain or compressed replay from a reader. Packets
+// are paced at the console packet rate when realtime is set.
+func NewReaderSource(r io.Reader, realtime bool) (Source, error) {
+	reader, err := telemetrysrc.NewReplayReader(r, realtime, zerolog.Nop())
+	if err != nil {
+		return nil, err
+	}
+
+	return reader, nil
+}
+
+// NewFSSource reads a replay file from a file system such as an embed.FS.
+func NewFSSource(fsys fs.FS, name string, realtime bool) (Source, error) {
+	fh, err := fsys.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open replay: %w", err)
+	}
+
+	reader, err := telemetrysrc.NewReplayReader(fh, realtime, zerolog.Nop())
+	if err != nil {
+		fh.Close()
+		return nil, err
+	}
+
+	return &fileSource{Source: reader, file: fh}, nil
+}
+
+// NewPcapSource reads the telemetry stream from a pcap or pcapng capture,
+// deciphering each packet. Packets are paced by their capture timestamps when
+// realtime is set. The default telemetry ports are used when none are given.
+func NewPcapSource(r io.Reader, realtime bool, ports ...uint16) (Source, error) {
+	reader, err := telemetrysrc.NewPcapReader(r, ports, realtime, zerolog.Nop())
+	if err != nil {
+		return nil, err
+	}
+
+	return reader, nil
+}
+
+// fileSource closes the underlying file along with the source.
+type fileSource struct {
+	Source
+	file io.Closer
+}
+
+func (s *fileSource) Realtime() bool {
+	if rs, ok := s.Source.(realtimeSource); ok {
+		return rs.Realtime()
+	}
+
+	return true
+}
+
+func (s *fileSource) Close() error {
+	_ = s.Source.Close()
+
+	return s.file.Close()
+}
+
+// newRawTelemetry returns telemetry with every nested value allocated so the
+// fields can be set directly.
+func newRawTelemetry() gttelemetry.GranTurismoTelemetry {
+	return gttelemetry.GranTurismoTelemetry{
+		Header:                 &gttelemetry.GranTurismoTelemetry_Header{Magic: packetMagic},
+		MapPositionCoordinates: &gttelemetry.GranTurismoTelemetry_Coordinate{},
+		VelocityVector:         &gttelemetry.GranTurismoTelemetry_Vector{},
+		RotationAxes:           &gttelemetry.GranTurismoTelemetry_SymmetryAxes{},
+		AngularVelocityVector:  &gttelemetry.GranTurismoTelemetry_Vector{},
+		TyreTemperature:        &gttelemetry.GranTurismoTelemetry_CornerSet{},
+		Flags:                  &gttelemetry.GranTurismoTelemetry_Flags{},
+		TransmissionGear:       &gttelemetry.GranTurismoTelemetry_TransmissionGear{},
+		RoadPlaneVector:        &gttelemetry.GranTurismoTelemetry_Vector{},
+		WheelRadiansPerSecond:  &gttelemetry.GranTurismoTelemetry_CornerSet{},
+		TyreRadius:             &gttelemetry.GranTurismoTelemetry_CornerSet{},
+		SuspensionHeight:       &gttelemetry.GranTurismoTelemetry_CornerSet{},
+		TransmissionGearRatio:  &gttelemetry.GranTurismoTelemetry_GearRatio{Gear: make([]float32, 8)},
+		BestLaptime:            -1,
+		LastLaptime:            -1,
+	}
+}
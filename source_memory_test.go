@@ -0,0 +1,330 @@
+package telemetry
+
+import (
+	"bytes"
+	"os"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/kaitai-io/kaitai_struct_go_runtime/kaitai"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/vwhitteron/gt-telemetry/internal/gttelemetry"
+)
+
+type MemorySourceTestSuite struct {
+	suite.Suite
+	replay []byte
+}
+
+func TestMemorySourceTestSuite(t *testing.T) {
+	suite.Run(t, new(MemorySourceTestSuite))
+}
+
+func (suite *MemorySourceTestSuite) SetupSuite() {
+	replay, err := os.ReadFile("examples/simple/replay.gtz")
+	suite.Require().NoError(err)
+
+	suite.replay = replay
+}
+
+func (suite *MemorySourceTestSuite) run(source Source) []Frame {
+	client, err := NewGTClient(GTClientOpts{
+		SourceReader: source,
+		LogLevel:     "off",
+	})
+	suite.Require().NoError(err)
+
+	frames := client.Subscribe(8192)
+	client.Run()
+
+	collected := []Frame{}
+	for frame := range frames {
+		collected = append(collected, frame)
+	}
+
+	return collected
+}
+
+func (suite *MemorySourceTestSuite) TestFuncSourceFramesAreDecodedByTheClient() {
+	// Arrange
+	source := NewFuncSource(func(i int, frame *Frame) bool {
+		frame.RawTelemetry.EngineRpm = float32(1000 * (i + 1))
+		frame.RawTelemetry.TyreTemperature.FrontLeft = 85.5
+		frame.RawTelemetry.Flags.Live = true
+		frame.RawTelemetry.Flags.TcsActive = true
+		frame.RawTelemetry.TransmissionGear.Current = 3
+		frame.RawTelemetry.TransmissionGear.Suggested = 4
+		frame.RawTelemetry.TransmissionGearRatio.Gear[0] = 3.5
+		frame.RawTelemetry.Throttle = 255
+		frame.RawTelemetry.VehicleId = 1234
+
+		return i < 3
+	})
+
+	// Act
+	frames := suite.run(source)
+
+	// Assert
+	suite.Require().Len(frames, 3)
+	for i, frame := range frames {
+		suite.Equal(uint32(i), frame.SequenceID())
+		suite.Equal(float32(1000*(i+1)), frame.EngineRPM())
+	}
+	frame := frames[2]
+	suite.Equal(float32(85.5), frame.TyreTemperatureCelsius().FrontLeft)
+	suite.True(frame.Flags().Live)
+	suite.True(frame.Flags().TCSActive)
+	suite.False(frame.Flags().GamePaused)
+	suite.Equal(3, frame.CurrentGear())
+	suite.Equal(uint64(4), frame.SuggestedGear())
+	suite.Equal(float32(3.5), frame.RawTelemetry.TransmissionGearRatio.Gear[0])
+	suite.Equal(float32(100), frame.ThrottlePercent())
+	suite.Equal(uint32(1234), frame.VehicleID())
+}
+
+func (suite *MemorySourceTestSuite) TestEncodedPacketsMatchThePacketLayout() {
+	// Arrange
+	raw := newRawTelemetry()
+	raw.SequenceId = 77
+	raw.CurrentLap = 2
+	raw.RaceEntrants = -1
+	raw.Oiv = 42
+
+	// Act
+	packet := encodePacket(&raw)
+	decoded := gttelemetry.NewGranTurismoTelemetry()
+	err := decoded.Read(kaitai.NewStream(bytes.NewReader(packet)), nil, nil)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Len(packet, packetSize)
+	suite.Equal(uint32(77), decoded.SequenceId)
+	suite.Equal(uint16(2), decoded.CurrentLap)
+	suite.Equal(int16(-1), decoded.RaceEntrants)
+	suite.Equal(int32(-1), decoded.BestLaptime)
+	suite.Equal(float32(42), decoded.Oiv)
+}
+
+func (suite *MemorySourceTestSuite) TestReaderSourceReadsACompressedReplay() {
+	// Arrange
+	source, err := NewReaderSource(bytes.NewReader(suite.replay), false)
+	suite.Require().NoError(err)
+
+	// Act
+	frames := suite.run(source)
+
+	// Assert
+	suite.Len(frames, 6421)
+}
+
+func (suite *MemorySourceTestSuite) TestFSSourceReadsAnEmbeddedReplay() {
+	// Arrange
+	fsys := fstest.MapFS{
+		"replays/session.gtz": &fstest.MapFile{Data: suite.replay},
+	}
+	source, err := NewFSSource(fsys, "replays/session.gtz", false)
+	suite.Require().NoError(err)
+
+	// Act
+	frames := suite.run(source)
+
+	// Assert
+	suite.Len(frames, 6421)
+}
+
+func (suite *MemorySourceTestSuite) TestFSSourceReportsMissingFiles() {
+	// Act
+	_, err := NewFSSource(fstest.MapFS{}, "missing.gtz", false)
+
+	// Assert
+	suite.ErrorContains(err, "failed to open replay")
+}
+
+func (suite *MemorySourceTestSuite) TestPacketSourceEndsAfterTheLastPacket() {
+	// Arrange
+	raw := newRawTelemetry()
+	packet := encodePacket(&raw)
+	source := NewPacketSource([][]byte{packet, packet})
+
+	// Act
+	frames := suite.run(source)
+
+	// Assert
+	suite.Len(frames, 2)
+}
+
+func (suite *MemorySourceTestSuite) TestClosingTheClientStopsAnEndlessSource() {
+	// Arrange
+	client, err := NewGTClient(GTClientOpts{
+		SourceReader: NewFuncSource(func(i int, frame *Frame) bool { return true }),
+		LogLevel:     "off",
+	})
+	suite.Require().NoError(err)
+	frames := client.Subscribe(1)
+	go client.Run()
+	<-frames
+
+	// Act
+	err = client.Close()
+
+	// Assert
+	suite.NoError(err)
+	for range frames {
+	}
+	suite.True(client.Stopped())
+}
+
+// closeCounter counts the calls to Close of a source.
+type closeCounter struct {
+	Source
+	closes int
+}
+
+func (c *closeCounter) Close() error {
+	c.closes++
+
+	return c.Source.Close()
+}
+
+func (suite *MemorySourceTestSuite) TestSourcesAreClosedOnce() {
+	testCases := map[string]struct {
+		packets int
+		close   bool
+	}{
+		"closed by the client": {packets: -1, close: true},
+		"source ended":         {packets: 3},
+		"closed after ending":  {packets: 3, close: true},
+	}
+
+	for name, tc := range testCases {
+		suite.Run(name, func() {
+			// Arrange
+			source := &closeCounter{Source: NewFuncSource(func(i int, frame *Frame) bool {
+				return tc.packets < 0 || i < tc.packets
+			})}
+			client, err := NewGTClient(GTClientOpts{SourceReader: source, LogLevel: "off"})
+			suite.Require().NoError(err)
+			frames := client.Subscribe(1)
+			done := make(chan error, 1)
+			go func() {
+				done <- client.Run()
+			}()
+
+			// Act
+			if tc.packets < 0 {
+				<-frames
+				suite.Require().NoError(client.Close())
+			}
+			for range frames {
+			}
+			suite.Require().NoError(<-done)
+			if tc.close {
+				suite.Require().NoError(client.Close())
+			}
+
+			// Assert
+			suite.Equal(1, source.closes)
+		})
+	}
+}
+
+func (suite *MemorySourceTestSuite) TestClosingTheClientBeforeRunFinishesImmediately() {
+	// Arrange
+	client, err := NewGTClient(GTClientOpts{
+		SourceReader: NewFuncSource(func(i int, frame *Frame) bool { return true }),
+		LogLevel:     "off",
+	})
+	suite.Require().NoError(err)
+	frames := client.Subscribe(1)
+
+	// Act
+	suite.Require().NoError(client.Close())
+	client.Run()
+
+	// Assert
+	_, ok := <-frames
+	suite.False(ok)
+}
+
+func (suite *MemorySourceTestSuite) TestClosingTheClientReleasesASlowSubscriber() {
+	// Arrange
+	client, err := NewGTClient(GTClientOpts{
+		SourceReader: NewFuncSource(func(i int, frame *Frame) bool { return true }),
+		LogLevel:     "off",
+	})
+	suite.Require().NoError(err)
+	client.Subscribe(0)
+	stopped := make(chan struct{})
+	go func() {
+		client.Run()
+		close(stopped)
+	}()
+	suite.Eventually(func() bool {
+		_, ok := client.LastFrame()
+		return ok
+	}, time.Second, time.Millisecond)
+
+	// Act
+	err = client.Close()
+
+	// Assert
+	suite.NoError(err)
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		suite.Fail("client did not stop")
+	}
+}
+
+func (suite *MemorySourceTestSuite) TestUnsubscribingReleasesTheOtherSubscribers() {
+	// Arrange
+	client, err := NewGTClient(GTClientOpts{
+		SourceReader: NewFuncSource(func(i int, frame *Frame) bool { return i < 100 }),
+		LogLevel:     "off",
+	})
+	suite.Require().NoError(err)
+	abandoned := client.Subscribe(0)
+	frames := client.Subscribe(1)
+	go client.Run()
+
+	// Act
+	client.Unsubscribe(abandoned)
+
+	// Assert
+	received := 0
+	for range frames {
+		received++
+	}
+	suite.Equal(100, received)
+	_, ok := <-abandoned
+	suite.False(ok)
+}
+
+func (suite *MemorySourceTestSuite) TestFramesCanBeReadFromSeveralGoroutines() {
+	// Arrange
+	source := NewFuncSource(func(i int, frame *Frame) bool {
+		frame.RawTelemetry.VehicleId = 99999
+		return i < 1
+	})
+	frames := suite.run(source)
+	suite.Require().NotEmpty(frames)
+	frame := frames[0]
+
+	// Act
+	var wg sync.WaitGroup
+	models := make([]string, 2)
+	for i := range models {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			models[i] = frame.VehicleModel()
+		}()
+	}
+	wg.Wait()
+
+	// Assert
+	suite.Equal(models[0], models[1])
+}
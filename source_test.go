@@ -0,0 +1,150 @@
+package telemetry
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/suite"
+)
+
+type SourceTestSuite struct {
+	suite.Suite
+	packets [][]byte
+}
+
+func TestSourceTestSuite(t *testing.T) {
+	suite.Run(t, new(SourceTestSuite))
+}
+
+func (suite *SourceTestSuite) SetupSuite() {
+	replay, err := openSource("file://examples/simple/replay.gtz?realtime=false", zerolog.Nop())
+	suite.Require().NoError(err)
+	defer replay.Close()
+
+	for len(suite.packets) < 5 {
+		bufLen, packet, err := replay.Read()
+		suite.Require().NoError(err)
+		if bufLen > 0 {
+			suite.packets = append(suite.packets, packet[:bufLen])
+		}
+	}
+}
+
+func (suite *SourceTestSuite) TestRegisteredSourcesAreUsedForTheirScheme() {
+	// Arrange
+	var received *url.URL
+	RegisterSource("test-packets", func(sourceURL *url.URL, _ zerolog.Logger) (Source, error) {
+		received = sourceURL
+		return NewPacketSource(suite.packets), nil
+	})
+
+	client, err := NewGTClient(GTClientOpts{
+		Source:   "test-packets://capture?realtime=false",
+		LogLevel: "off",
+	})
+	suite.Require().NoError(err)
+
+	// Act
+	frames := client.Subscribe(len(suite.packets))
+	client.Run()
+
+	count := 0
+	for range frames {
+		count++
+	}
+
+	// Assert
+	suite.Equal(len(suite.packets), count)
+	suite.Equal("capture", received.Host)
+	suite.Equal("false", received.Query().Get("realtime"))
+	suite.True(client.Stopped())
+}
+
+func (suite *SourceTestSuite) TestSchemesAreCaseInsensitive() {
+	// Arrange
+	RegisterSource("Test-Case", func(_ *url.URL, _ zerolog.Logger) (Source, error) {
+		return NewPacketSource(nil), nil
+	})
+
+	// Act
+	_, err := NewGTClient(GTClientOpts{Source: "TEST-CASE://", LogLevel: "off"})
+
+	// Assert
+	suite.NoError(err)
+	suite.Contains(SourceSchemes(), "test-case")
+}
+
+func (suite *SourceTestSuite) TestUnknownSchemesAreRejected() {
+	// Act
+	_, err := NewGTClient(GTClientOpts{Source: "carrier-pigeon://coop", LogLevel: "off"})
+
+	// Assert
+	suite.EqualError(err, `unknown source URL scheme "carrier-pigeon"`)
+}
+
+func (suite *SourceTestSuite) TestRunReportsSourcesThatFailToOpen() {
+	// Arrange
+	client, err := NewGTClient(GTClientOpts{Source: "file://missing.gtz", LogLevel: "off"})
+	suite.Require().NoError(err)
+	frames := client.Subscribe(1)
+
+	// Act
+	err = client.Run()
+
+	// Assert
+	suite.ErrorContains(err, "failed to open telemetry source")
+	_, open := <-frames
+	suite.False(open)
+	suite.True(client.Stopped())
+}
+
+func (suite *SourceTestSuite) TestClosingAClientReadingFromAConsole() {
+	// Arrange
+	console, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	suite.Require().NoError(err)
+	defer console.Close()
+	receiver, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	suite.Require().NoError(err)
+	receivePort := receiver.LocalAddr().(*net.UDPAddr).Port
+	receiver.Close()
+
+	client, err := NewGTClient(GTClientOpts{
+		Source:   fmt.Sprintf("udp://%s?receive_port=%d", console.LocalAddr(), receivePort),
+		LogLevel: "off",
+	})
+	suite.Require().NoError(err)
+	done := make(chan error, 1)
+	go func() {
+		done <- client.Run()
+	}()
+
+	// the heartbeat shows the source is open
+	suite.Require().NoError(console.SetReadDeadline(time.Now().Add(time.Second)))
+	_, _, err = console.ReadFromUDP(make([]byte, 16))
+	suite.Require().NoError(err)
+
+	// Act
+	closeErr := client.Close()
+
+	// Assert
+	suite.NoError(closeErr)
+	select {
+	case err := <-done:
+		suite.NoError(err)
+	case <-time.After(time.Second):
+		suite.Fail("client did not stop")
+	}
+	suite.True(client.Stopped())
+}
+
+func (suite *SourceTestSuite) TestBuiltInSourcesAreRegistered() {
+	// Act
+	schemes := SourceSchemes()
+
+	// Assert
+	suite.Subset(schemes, []string{"file", "relay", "udp"})
+}
@@ -0,0 +1,351 @@
+package telemetry
+
+import (
+	"math"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	// statsWindow is the period covered by the sliding window rates and
+	// jitter percentiles
+	statsWindow = 5 * time.Second
+	// Samples kept in the window regardless of the packet rate
+	maxWindowSamples = 1024
+	// Sequence gaps larger than this are treated as the stream restarting
+	// rather than as dropped packets
+	maxSequenceGap = 3600
+	// Number of recent sequence IDs tracked to tell duplicates from late
+	// packets
+	sequenceHistory = 64
+)
+
+// Upper bounds of the decode time histogram buckets
+var decodeTimeBuckets = []time.Duration{
+	10 * time.Microsecond,
+	25 * time.Microsecond,
+	50 * time.Microsecond,
+	100 * time.Microsecond,
+	250 * time.Microsecond,
+	500 * time.Microsecond,
+	1 * time.Millisecond,
+	2500 * time.Microsecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+}
+
+// Stats is a snapshot of the client packet statistics.
+type Stats struct {
+	Enabled bool
+	// PacketsReceived counts every packet read from the source, including
+	// duplicates.
+	PacketsReceived uint64
+	// PacketsDropped counts sequence IDs that have not been received, late
+	// packets are removed from the count when they arrive.
+	PacketsDropped    uint64
+	PacketsDuplicate  uint64
+	PacketsOutOfOrder uint64
+	PacketsInvalid    uint64
+	// SequenceResets counts jumps in the sequence ID too large to be dropped
+	// packets, such as the game restarting the stream.
+	SequenceResets uint64
+	// PacketRate is the packets per second over the sliding window.
+	PacketRate float64
+	// PacketRateLifetime is the packets per second since the first packet.
+	PacketRateLifetime float64
+	// PacketRateMax is the highest sliding window packet rate.
+	PacketRateMax float64
+	// Jitter is the variation between consecutive packet inter-arrival
+	// times over the sliding window.
+	Jitter      JitterStats
+	DecodeTime  DecodeTimeStats
+	FirstPacket time.Time
+	LastPacket  time.Time
+}
+
+// LegacyStatistics holds the packet statistics in the form of the
+// Statistics field of the client from before Stats was added.
+type LegacyStatistics struct {
+	DecodeTimeAvg     time.Duration
+	DecodeTimeMax     time.Duration
+	PacketRateAvg     int
+	PacketRateCurrent int
+	PacketRateMax     int
+	PacketsDropped    int
+	PacketsInvalid    int
+	PacketsTotal      int
+}
+
+type JitterStats struct {
+	P50 time.Duration
+	P90 time.Duration
+	P99 time.Duration
+	Max time.Duration
+}
+
+type DecodeTimeStats struct {
+	Mean    time.Duration
+	Max     time.Duration
+	Sum     time.Duration
+	Count   uint64
+	Buckets []HistogramBucket
+}
+
+// HistogramBucket counts the observations less than or equal to the upper
+// bound.
+type HistogramBucket struct {
+	UpperBound time.Duration
+	Count      uint64
+}
+
+type statsSample struct {
+	arrival time.Time
+	jitter  time.Duration
+	// the first two packets have no previous interval to compare with
+	hasJitter bool
+}
+
+type statistics struct {
+	mu      sync.Mutex
+	enabled bool
+
+	received   uint64
+	dropped    uint64
+	duplicate  uint64
+	outOfOrder uint64
+	invalid    uint64
+	resets     uint64
+
+	started      bool
+	lastSequence uint32
+	// bit n is set when lastSequence-n has been received
+	recent uint64
+
+	first        time.Time
+	last         time.Time
+	unique       uint64
+	lastInterval time.Duration
+	window       []statsSample
+	rateMax      float64
+
+	decodeCounts []uint64
+	decodeSum    time.Duration
+	decodeCount  uint64
+	decodeMax    time.Duration
+}
+
+func newStatistics(enabled bool) *statistics {
+	return &statistics{
+		enabled:      enabled,
+		decodeCounts: make([]uint64, len(decodeTimeBuckets)),
+	}
+}
+
+// observe records a decoded packet with its sequence ID and arrival time.
+func (s *statistics) observe(sequence uint32, arrival time.Time, decodeTime time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.received++
+
+	if !s.track(sequence) {
+		return
+	}
+
+	s.observeDecodeTime(decodeTime)
+	s.observeArrival(arrival)
+}
+
+func (s *statistics) observeInvalid() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.invalid++
+}
+
+// track updates the sequence counters and reports whether the packet is new.
+func (s *statistics) track(sequence uint32) bool {
+	if !s.started {
+		s.started = true
+		s.lastSequence = sequence
+		s.recent = 1
+
+		return true
+	}
+
+	// the signed difference handles the sequence ID wrapping around
+	delta := int64(int32(sequence - s.lastSequence))
+
+	switch {
+	case delta == 0:
+		s.duplicate++
+		return false
+	case delta > maxSequenceGap || delta < -maxSequenceGap:
+		s.resets++
+		s.lastSequence = sequence
+		s.recent = 1
+	case delta > 0:
+		s.dropped += uint64(delta - 1)
+		s.lastSequence = sequence
+		if delta >= sequenceHistory {
+			s.recent = 1
+		} else {
+			s.recent = s.recent<<delta | 1
+		}
+	default:
+		age := -delta
+		if age < sequenceHistory {
+			if s.recent&(1<<age) != 0 {
+				s.duplicate++
+				return false
+			}
+			s.recent |= 1 << age
+		}
+		s.outOfOrder++
+		if s.dropped > 0 {
+			s.dropped--
+		}
+	}
+
+	return true
+}
+
+func (s *statistics) observeDecodeTime(decodeTime time.Duration) {
+	for i, bound := range decodeTimeBuckets {
+		if decodeTime <= bound {
+			s.decodeCounts[i]++
+		}
+	}
+	s.decodeSum += decodeTime
+	s.decodeCount++
+	s.decodeMax = max(s.decodeMax, decodeTime)
+}
+
+func (s *statistics) observeArrival(arrival time.Time) {
+	s.unique++
+	if s.first.IsZero() {
+		s.first = arrival
+	}
+
+	sample := statsSample{arrival: arrival}
+	if !s.last.IsZero() {
+		interval := arrival.Sub(s.last)
+		if s.lastInterval > 0 {
+			sample.jitter = interval - s.lastInterval
+			if sample.jitter < 0 {
+				sample.jitter = -sample.jitter
+			}
+			sample.hasJitter = true
+		}
+		s.lastInterval = interval
+	}
+	s.last = arrival
+
+	s.window = append(s.window, sample)
+	cutoff := arrival.Add(-statsWindow)
+	trim := 0
+	for trim < len(s.window)-1 && (s.window[trim].arrival.Before(cutoff) || len(s.window)-trim > maxWindowSamples) {
+		trim++
+	}
+	s.window = s.window[trim:]
+
+	// short spans would overstate the rate of a burst of packets
+	if span := arrival.Sub(s.window[0].arrival); span >= statsWindow/2 {
+		s.rateMax = max(s.rateMax, float64(len(s.window)-1)/span.Seconds())
+	}
+}
+
+// snapshot returns a copy of the statistics that is safe to read while the
+// client is running.
+func (s *statistics) snapshot() Stats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := Stats{
+		Enabled:           s.enabled,
+		PacketsReceived:   s.received,
+		PacketsDropped:    s.dropped,
+		PacketsDuplicate:  s.duplicate,
+		PacketsOutOfOrder: s.outOfOrder,
+		PacketsInvalid:    s.invalid,
+		SequenceResets:    s.resets,
+		PacketRateMax:     s.rateMax,
+		FirstPacket:       s.first,
+		LastPacket:        s.last,
+		DecodeTime: DecodeTimeStats{
+			Max:     s.decodeMax,
+			Sum:     s.decodeSum,
+			Count:   s.decodeCount,
+			Buckets: make([]HistogramBucket, len(decodeTimeBuckets)),
+		},
+	}
+
+	for i, bound := range decodeTimeBuckets {
+		stats.DecodeTime.Buckets[i] = HistogramBucket{UpperBound: bound, Count: s.decodeCounts[i]}
+	}
+	if s.decodeCount > 0 {
+		stats.DecodeTime.Mean = s.decodeSum / time.Duration(s.decodeCount)
+	}
+
+	if span := s.last.Sub(s.first); span > 0 {
+		stats.PacketRateLifetime = float64(s.unique-1) / span.Seconds()
+	}
+
+	if len(s.window) > 1 {
+		if span := s.window[len(s.window)-1].arrival.Sub(s.window[0].arrival); span > 0 {
+			stats.PacketRate = float64(len(s.window)-1) / span.Seconds()
+		}
+
+	}
+
+	jitters := make([]time.Duration, 0, len(s.window))
+	for _, sample := range s.window {
+		if sample.hasJitter {
+			jitters = append(jitters, sample.jitter)
+		}
+	}
+	if len(jitters) > 0 {
+		slices.Sort(jitters)
+		stats.Jitter = JitterStats{
+			P50: percentile(jitters, 0.5),
+			P90: percentile(jitters, 0.9),
+			P99: percentile(jitters, 0.99),
+			Max: jitters[len(jitters)-1],
+		}
+	}
+
+	return stats
+}
+
+// legacy updates the statistics in their old form without the jitter
+// percentiles, which are too costly to work out for every packet. The old
+// total did not count duplicate packets.
+func (s *statistics) legacy(l *LegacyStatistics) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l.PacketsTotal = int(s.unique)
+	l.PacketsDropped = int(s.dropped)
+	l.PacketsInvalid = int(s.invalid)
+	l.DecodeTimeMax = s.decodeMax
+	if s.decodeCount > 0 {
+		l.DecodeTimeAvg = s.decodeSum / time.Duration(s.decodeCount)
+	}
+	if span := s.last.Sub(s.first); span > 0 {
+		l.PacketRateAvg = int(float64(s.unique-1) / span.Seconds())
+	}
+	if len(s.window) > 1 {
+		if span := s.window[len(s.window)-1].arrival.Sub(s.window[0].arrival); span > 0 {
+			l.PacketRateCurrent = int(float64(len(s.window)-1) / span.Seconds())
+		}
+	}
+	l.PacketRateMax = int(s.rateMax)
+}
+
+// percentile returns the nearest-rank percentile of sorted durations.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+
+	return sorted[max(rank, 0)]
+}
@@ -0,0 +1,183 @@
+package telemetry
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type StatsTestSuite struct {
+	suite.Suite
+	stats *statistics
+	start time.Time
+}
+
+func TestStatsTestSuite(t *testing.T) {
+	suite.Run(t, new(StatsTestSuite))
+}
+
+func (suite *StatsTestSuite) SetupTest() {
+	suite.stats = newStatistics(true)
+	suite.start = time.Unix(1700000000, 0)
+}
+
+// observeAll records packets arriving at a fixed interval.
+func (suite *StatsTestSuite) observeAll(sequences []uint32, interval time.Duration) {
+	for i, sequence := range sequences {
+		suite.stats.observe(sequence, suite.start.Add(time.Duration(i)*interval), 20*time.Microsecond)
+	}
+}
+
+func (suite *StatsTestSuite) TestSequenceCounters() {
+	tests := map[string]struct {
+		sequences  []uint32
+		dropped    uint64
+		duplicate  uint64
+		outOfOrder uint64
+		resets     uint64
+	}{
+		"in order":               {sequences: []uint32{1, 2, 3, 4}},
+		"gap":                    {sequences: []uint32{1, 2, 6}, dropped: 3},
+		"duplicate":              {sequences: []uint32{1, 2, 2, 3}, duplicate: 1},
+		"late packet":            {sequences: []uint32{1, 3, 2, 4}, outOfOrder: 1},
+		"late duplicate":         {sequences: []uint32{1, 3, 2, 2, 4}, duplicate: 1, outOfOrder: 1},
+		"wrap around":            {sequences: []uint32{math.MaxUint32 - 1, math.MaxUint32, 0, 1}},
+		"wrap around with a gap": {sequences: []uint32{math.MaxUint32, 2}, dropped: 2},
+		"restart":                {sequences: []uint32{50000, 50001, 1, 2}, resets: 1},
+	}
+
+	for name, tc := range tests {
+		suite.Run(name, func() {
+			// Arrange
+			suite.SetupTest()
+
+			// Act
+			suite.observeAll(tc.sequences, 16*time.Millisecond)
+			stats := suite.stats.snapshot()
+
+			// Assert
+			suite.Equal(uint64(len(tc.sequences)), stats.PacketsReceived)
+			suite.Equal(tc.dropped, stats.PacketsDropped)
+			suite.Equal(tc.duplicate, stats.PacketsDuplicate)
+			suite.Equal(tc.outOfOrder, stats.PacketsOutOfOrder)
+			suite.Equal(tc.resets, stats.SequenceResets)
+		})
+	}
+}
+
+func (suite *StatsTestSuite) TestRatesAreMeasuredOverTheWindowAndLifetime() {
+	// Arrange
+	sequences := []uint32{}
+	for i := range 600 {
+		sequences = append(sequences, uint32(i+1))
+	}
+	// ten seconds at 60Hz followed by a second at 30Hz
+	suite.observeAll(sequences, time.Second/60)
+	for i := range 30 {
+		arrival := suite.start.Add(10*time.Second + time.Duration(i+1)*time.Second/30)
+		suite.stats.observe(uint32(601+i), arrival, 20*time.Microsecond)
+	}
+
+	// Act
+	stats := suite.stats.snapshot()
+
+	// Assert
+	suite.InDelta(54, stats.PacketRate, 0.5)
+	suite.InDelta(57.3, stats.PacketRateLifetime, 0.5)
+	suite.InDelta(60, stats.PacketRateMax, 0.5)
+	suite.Equal(suite.start, stats.FirstPacket)
+}
+
+func (suite *StatsTestSuite) TestLegacyStatisticsMatchTheSnapshot() {
+	// Arrange
+	suite.observeAll([]uint32{1, 2, 4, 4, 5}, time.Second/60)
+	suite.stats.observeInvalid()
+	legacy := &LegacyStatistics{}
+
+	// Act
+	suite.stats.legacy(legacy)
+
+	// Assert
+	stats := suite.stats.snapshot()
+	suite.Equal(int(stats.PacketsReceived-stats.PacketsDuplicate), legacy.PacketsTotal)
+	suite.Equal(4, legacy.PacketsTotal)
+	suite.Equal(1, legacy.PacketsDropped)
+	suite.Equal(1, legacy.PacketsInvalid)
+	suite.Equal(stats.DecodeTime.Mean, legacy.DecodeTimeAvg)
+	suite.Equal(int(stats.PacketRate), legacy.PacketRateCurrent)
+	suite.Equal(int(stats.PacketRateLifetime), legacy.PacketRateAvg)
+}
+
+func (suite *StatsTestSuite) TestJitterPercentiles() {
+	// Arrange
+	arrival := suite.start
+	for i := range 100 {
+		interval := 16 * time.Millisecond
+		if i%10 == 9 {
+			interval = 20 * time.Millisecond
+		}
+		arrival = arrival.Add(interval)
+		suite.stats.observe(uint32(i+1), arrival, 20*time.Microsecond)
+	}
+
+	// Act
+	stats := suite.stats.snapshot()
+
+	// Assert
+	suite.Equal(time.Duration(0), stats.Jitter.P50)
+	suite.Equal(4*time.Millisecond, stats.Jitter.P90)
+	suite.Equal(4*time.Millisecond, stats.Jitter.P99)
+	suite.Equal(4*time.Millisecond, stats.Jitter.Max)
+}
+
+func (suite *StatsTestSuite) TestDecodeTimesExcludeDuplicates() {
+	// Arrange
+	for i, decodeTime := range []time.Duration{10 * time.Microsecond, 30 * time.Microsecond, time.Millisecond} {
+		suite.stats.observe(uint32(i%2+1), suite.start, decodeTime)
+	}
+
+	// Act
+	stats := suite.stats.snapshot()
+
+	// Assert
+	suite.Equal(uint64(2), stats.DecodeTime.Count)
+	suite.Equal(20*time.Microsecond, stats.DecodeTime.Mean)
+	suite.Equal(30*time.Microsecond, stats.DecodeTime.Max)
+	suite.Equal(uint64(1), stats.DecodeTime.Buckets[0].Count)
+}
+
+func (suite *StatsTestSuite) TestStatsCanBeReadWhileTheClientRuns() {
+	// Arrange
+	client, err := NewGTClient(GTClientOpts{
+		LogLevel:     "off",
+		StatsEnabled: true,
+		SourceReader: NewFuncSource(func(i int, _ *Frame) bool {
+			return i < 500
+		}),
+	})
+	suite.Require().NoError(err)
+
+	done := make(chan struct{})
+	go func() {
+		client.Run()
+		close(done)
+	}()
+
+	// Act
+	for running := true; running; {
+		select {
+		case <-done:
+			running = false
+		default:
+			_ = client.Stats()
+		}
+	}
+	stats := client.Stats()
+
+	// Assert
+	suite.Equal(uint64(500), stats.PacketsReceived)
+	suite.Zero(stats.PacketsDropped)
+	suite.Equal(uint64(500), stats.DecodeTime.Count)
+}
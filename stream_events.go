@@ -0,0 +1,135 @@
+package telemetry
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/vwhitteron/gt-telemetry/internal/telemetrysrc"
+)
+
+// ErrStreamStalled is returned by sources when no telemetry has arrived for
+// a while. Sources can wrap it to have the client report the stall as an
+// event, reading continues until the stream resumes.
+var ErrStreamStalled = telemetrysrc.ErrStalled
+
+type StreamEventType int
+
+const (
+	StreamStalled StreamEventType = iota + 1
+	StreamResumed
+	UnknownVehicle
+	SetupChanged
+)
+
+func (t StreamEventType) String() string {
+	switch t {
+	case StreamStalled:
+		return "stalled"
+	case StreamResumed:
+		return "resumed"
+	case UnknownVehicle:
+		return "unknown vehicle"
+	case SetupChanged:
+		return "setup changed"
+	default:
+		return "unknown"
+	}
+}
+
+type StreamEvent struct {
+	Type StreamEventType
+	Time time.Time
+	// Downtime is how long the stream was stalled for, set on resume.
+	Downtime time.Duration
+	// VehicleID is the vehicle that is not in the inventory, or whose setup
+	// has changed.
+	VehicleID uint32
+	// SetupID is the setup the vehicle is now driven with.
+	SetupID string
+}
+
+func (c *GTClient) streamStalled() {
+	c.subscribersMu.Lock()
+	defer c.subscribersMu.Unlock()
+
+	if !c.stalledAt.IsZero() {
+		return
+	}
+	c.stalledAt = time.Now()
+
+	c.emit(StreamEvent{Type: StreamStalled, Time: c.stalledAt})
+}
+
+func (c *GTClient) streamResumed() {
+	c.subscribersMu.Lock()
+	defer c.subscribersMu.Unlock()
+
+	if c.stalledAt.IsZero() {
+		return
+	}
+	now := time.Now()
+	downtime := now.Sub(c.stalledAt)
+	c.stalledAt = time.Time{}
+
+	c.emit(StreamEvent{Type: StreamResumed, Time: now, Downtime: downtime})
+}
+
+func (c *GTClient) unknownVehicle(id uint32) {
+	c.subscribersMu.Lock()
+	defer c.subscribersMu.Unlock()
+
+	c.emit(StreamEvent{Type: UnknownVehicle, Time: time.Now(), VehicleID: id})
+}
+
+func (c *GTClient) setupChanged(setupID string, vehicleID uint32) {
+	c.subscribersMu.Lock()
+	defer c.subscribersMu.Unlock()
+
+	c.emit(StreamEvent{Type: SetupChanged, Time: time.Now(), VehicleID: vehicleID, SetupID: setupID})
+}
+
+// emit must be called with the subscribers lock held.
+func (c *GTClient) emit(event StreamEvent) {
+	for _, ch := range c.events {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// withConsoleOptions adds the console connection options of the client to a
+// network source URL without replacing query parameters already set.
+func withConsoleOptions(sourceURL *url.URL, opts GTClientOpts) string {
+	if sourceURL.Scheme != "udp" && sourceURL.Scheme != "relay" {
+		return opts.Source
+	}
+
+	query := sourceURL.Query()
+	set := func(name string, value string) {
+		if value != "" && !query.Has(name) {
+			query.Set(name, value)
+		}
+	}
+	duration := func(d time.Duration) string {
+		if d <= 0 {
+			return ""
+		}
+		return d.String()
+	}
+
+	set("bind", opts.BindAddress)
+	set("interface", opts.Interface)
+	if opts.ReceivePort > 0 {
+		set("receive_port", strconv.Itoa(opts.ReceivePort))
+	}
+	set("heartbeat", duration(opts.HeartbeatInterval))
+	set("stall_timeout", duration(opts.StallTimeout))
+	set("max_backoff", duration(opts.MaxBackoff))
+	set("packet", opts.PacketFormat)
+
+	sourceURL.RawQuery = query.Encode()
+
+	return sourceURL.String()
+}
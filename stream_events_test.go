@@ -0,0 +1,108 @@
+package telemetry
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type StreamEventsTestSuite struct {
+	suite.Suite
+}
+
+func TestStreamEventsTestSuite(t *testing.T) {
+	suite.Run(t, new(StreamEventsTestSuite))
+}
+
+// stallingSource stalls twice before delivering a packet.
+type stallingSource struct {
+	reads int
+}
+
+func (s *stallingSource) Read() (int, []byte, error) {
+	s.reads++
+	switch s.reads {
+	case 1, 2:
+		return 0, nil, fmt.Errorf("%w: no telemetry received", ErrStreamStalled)
+	case 3:
+		raw := newRawTelemetry()
+		packet := encodePacket(&raw)
+		return len(packet), packet, nil
+	default:
+		return 0, nil, io.EOF
+	}
+}
+
+func (s *stallingSource) Realtime() bool {
+	return false
+}
+
+func (s *stallingSource) Close() error {
+	return nil
+}
+
+func (suite *StreamEventsTestSuite) TestStallsAndResumesAreReported() {
+	// Arrange
+	client, err := NewGTClient(GTClientOpts{SourceReader: &stallingSource{}, LogLevel: "off"})
+	suite.Require().NoError(err)
+	events := client.Events(4)
+
+	// Act
+	client.Run()
+
+	received := []StreamEvent{}
+	for event := range events {
+		received = append(received, event)
+	}
+
+	// Assert
+	suite.Require().Len(received, 2)
+	suite.Equal(StreamStalled, received[0].Type)
+	suite.Equal(StreamResumed, received[1].Type)
+	suite.False(received[1].Time.Before(received[0].Time))
+	suite.Equal(received[1].Time.Sub(received[0].Time), received[1].Downtime)
+}
+
+func (suite *StreamEventsTestSuite) TestConsoleOptionsAreAddedToNetworkSources() {
+	// Act
+	client, err := NewGTClient(GTClientOpts{
+		Source:            "udp://[fe80::1%25eth0]:33739?stall_timeout=2s",
+		LogLevel:          "off",
+		BindAddress:       "192.168.1.10",
+		ReceivePort:       40000,
+		HeartbeatInterval: 5 * time.Second,
+		StallTimeout:      time.Second,
+	})
+	suite.Require().NoError(err)
+	sourceURL, err := url.Parse(client.source)
+	suite.Require().NoError(err)
+
+	// Assert
+	query := sourceURL.Query()
+	suite.Equal("fe80::1%eth0", sourceURL.Hostname())
+	suite.Equal("192.168.1.10", query.Get("bind"))
+	suite.Equal("40000", query.Get("receive_port"))
+	suite.Equal("5s", query.Get("heartbeat"))
+	suite.Equal("2s", query.Get("stall_timeout"))
+	suite.False(query.Has("interface"))
+}
+
+func (suite *StreamEventsTestSuite) TestConsoleOptionsAreParsedFromTheSourceURL() {
+	// Arrange
+	sourceURL, err := url.Parse("udp://192.168.1.20:33739?interface=eth0&receive_port=40001&heartbeat=2s&max_backoff=1m")
+	suite.Require().NoError(err)
+
+	// Act
+	opts, err := udpOptions(sourceURL.Query())
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal("eth0", opts.Interface)
+	suite.Equal(40001, opts.ReceivePort)
+	suite.Equal(2*time.Second, opts.HeartbeatInterval)
+	suite.Equal(time.Minute, opts.MaxBackoff)
+}
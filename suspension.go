@@ -0,0 +1,310 @@
+package telemetry
+
+import (
+	"math"
+)
+
+const (
+	// packets per second sent by the game, used to time the suspension
+	// movement between packets
+	suspensionPacketRate = 60
+	// sequence gaps larger than this restart the suspension velocity
+	suspensionMaxGap = 6
+	// compression speed in meters per second that stops dead within a packet
+	// when the suspension bottoms out
+	bottomingVelocity = 0.25
+	// a suspension only bottoms out within this distance in meters of the
+	// shortest it has been seen
+	bottomingMargin = 0.005
+	// a suspension within this distance in meters of the longest it has been
+	// seen and barely moving hangs with the wheel off the ground
+	offGroundMargin      = 0.002
+	offGroundMaxVelocity = 0.02
+	// travel in meters to be seen before the longest suspension is taken as
+	// the wheel hanging free
+	offGroundMinTravel = 0.02
+	offGroundMinSpeed  = 5
+	// used for the body angles of vehicles without dimensions in the
+	// inventory
+	defaultWheelbase  = 2.6
+	defaultTrackWidth = 1.55
+)
+
+// Upper bounds in meters per second of the damper histogram bands, the last
+// band holds everything faster
+var damperBandLimits = [...]float32{0.025, 0.05, 0.1, 0.2}
+
+// DamperBands is the number of bands in a damper histogram.
+const DamperBands = len(damperBandLimits) + 1
+
+// DamperBandLimit returns the upper bound in meters per second of a damper
+// histogram band, the last band is unbounded.
+func DamperBandLimit(band int) float32 {
+	if band < 0 || band >= len(damperBandLimits) {
+		return float32(math.Inf(1))
+	}
+
+	return damperBandLimits[band]
+}
+
+// DamperHistogram counts the packets in each band of suspension velocity,
+// split between bump as the suspension compresses and rebound as it extends.
+type DamperHistogram struct {
+	Bump    [DamperBands]int
+	Rebound [DamperBands]int
+}
+
+func (h DamperHistogram) BumpSamples() int {
+	return sum(h.Bump)
+}
+
+func (h DamperHistogram) ReboundSamples() int {
+	return sum(h.Rebound)
+}
+
+// BumpShare is the share of the packets with the suspension moving that were
+// in bump, from 0 to 1.
+func (h DamperHistogram) BumpShare() float32 {
+	bump, rebound := h.BumpSamples(), h.ReboundSamples()
+	if bump+rebound == 0 {
+		return 0
+	}
+
+	return float32(bump) / float32(bump+rebound)
+}
+
+func (h *DamperHistogram) observe(velocity float32) {
+	speed := float32(math.Abs(float64(velocity)))
+	band := len(damperBandLimits)
+	for i, limit := range damperBandLimits {
+		if speed <= limit {
+			band = i
+			break
+		}
+	}
+
+	switch {
+	case velocity < 0:
+		h.Bump[band]++
+	case velocity > 0:
+		h.Rebound[band]++
+	}
+}
+
+func sum(counts [DamperBands]int) int {
+	total := 0
+	for _, count := range counts {
+		total += count
+	}
+
+	return total
+}
+
+// SuspensionLap summarises the suspension movement over a lap.
+type SuspensionLap struct {
+	Lap int16
+	// Samples is the number of packets the suspension velocity was known.
+	Samples int
+	Dampers Corners[DamperHistogram]
+	// BottomingOut and OffGround count the times each corner bottomed out
+	// and each wheel left the ground.
+	BottomingOut    Corners[int]
+	OffGround       Corners[int]
+	MaxRollDegrees  float32
+	MaxPitchDegrees float32
+}
+
+// suspensionDynamics tracks the suspension movement between packets. It only
+// holds values so that frames keep the state at the time they were taken.
+type suspensionDynamics struct {
+	vehicleID uint32
+	started   bool
+	sequence  uint32
+	height    Corners[float32]
+	velocity  Corners[float32]
+	// measured is set when the velocity is known from consecutive packets
+	measured bool
+	// range of suspension travel seen for the vehicle
+	shortest   Corners[float32]
+	longest    Corners[float32]
+	bottoming  Corners[bool]
+	offGround  Corners[bool]
+	lap        SuspensionLap
+	lastLap    SuspensionLap
+	hasLastLap bool
+}
+
+// observeSuspension updates the suspension movement from the current packet.
+func (t *transformer) observeSuspension() {
+	s := &t.suspension
+	id := t.RawTelemetry.VehicleId
+	if id != s.vehicleID {
+		*s = suspensionDynamics{vehicleID: id, lap: SuspensionLap{Lap: t.CurrentLap()}}
+	}
+
+	flags := t.Flags()
+	if t.RawTelemetry.SuspensionHeight == nil || flags.GamePaused || flags.Loading {
+		s.restart()
+		return
+	}
+
+	if lap := t.CurrentLap(); lap != s.lap.Lap {
+		if lap > s.lap.Lap && s.lap.Samples > 0 {
+			s.lastLap, s.hasLastLap = s.lap, true
+		}
+		s.lap = SuspensionLap{Lap: lap}
+	}
+
+	height := cornersOf[float32](t.SuspensionHeightMeters())
+	sequence := t.SequenceID()
+	gap := int64(int32(sequence - s.sequence))
+	moving := s.started && gap > 0 && gap <= suspensionMaxGap
+	if s.started && gap <= 0 {
+		return
+	}
+
+	previous := s.velocity
+	s.started, s.sequence = true, sequence
+	s.bottoming = Corners[bool]{}
+	s.travel(height)
+	s.measured = moving
+	if !moving {
+		s.height = height
+		s.velocity = Corners[float32]{}
+		return
+	}
+
+	interval := float32(gap) / suspensionPacketRate
+	groundSpeed := t.GroundSpeedMetersPerSecond()
+	for i := range 4 {
+		velocity := (*height.at(i) - *s.height.at(i)) / interval
+		*s.velocity.at(i) = velocity
+		s.lap.Dampers.at(i).observe(velocity)
+
+		// the bump stop halts the compression within a packet
+		if *previous.at(i) < -bottomingVelocity && velocity >= 0 &&
+			*height.at(i)-*s.shortest.at(i) <= bottomingMargin {
+			*s.bottoming.at(i) = true
+			*s.lap.BottomingOut.at(i)++
+		}
+
+		hanging := *s.longest.at(i)-*s.shortest.at(i) >= offGroundMinTravel &&
+			*s.longest.at(i)-*height.at(i) <= offGroundMargin &&
+			float32(math.Abs(float64(velocity))) <= offGroundMaxVelocity &&
+			groundSpeed >= offGroundMinSpeed
+		if hanging && !*s.offGround.at(i) {
+			*s.lap.OffGround.at(i)++
+		}
+		*s.offGround.at(i) = hanging
+	}
+	s.height = height
+
+	s.lap.Samples++
+	s.lap.MaxRollDegrees = max(s.lap.MaxRollDegrees, float32(math.Abs(float64(t.BodyRollDegrees()))))
+	s.lap.MaxPitchDegrees = max(s.lap.MaxPitchDegrees, float32(math.Abs(float64(t.BodyPitchDegrees()))))
+}
+
+// restart clears the movement so that the next packet only sets the heights.
+func (s *suspensionDynamics) restart() {
+	s.started = false
+	s.measured = false
+	s.velocity = Corners[float32]{}
+	s.bottoming = Corners[bool]{}
+	s.offGround = Corners[bool]{}
+}
+
+func (s *suspensionDynamics) travel(height Corners[float32]) {
+	for i := range 4 {
+		if *s.shortest.at(i) == 0 || *height.at(i) < *s.shortest.at(i) {
+			*s.shortest.at(i) = *height.at(i)
+		}
+		*s.longest.at(i) = max(*s.longest.at(i), *height.at(i))
+	}
+}
+
+// SuspensionVelocityMetersPerSecond is how fast the suspension at each corner
+// moved since the previous packet, negative in bump as the suspension
+// compresses and positive in rebound as it extends.
+func (t *transformer) SuspensionVelocityMetersPerSecond() CornerSet {
+	v := t.suspension.velocity
+
+	return CornerSet{
+		FrontLeft:  v.FrontLeft,
+		FrontRight: v.FrontRight,
+		RearLeft:   v.RearLeft,
+		RearRight:  v.RearRight,
+	}
+}
+
+// BodyRollDegrees is the roll of the body from the difference between the
+// suspension heights on each side, positive when the right side is lower.
+func (t *transformer) BodyRollDegrees() float32 {
+	height := t.SuspensionHeightMeters()
+	left := (height.FrontLeft + height.RearLeft) / 2
+	right := (height.FrontRight + height.RearRight) / 2
+
+	track := (t.VehicleTrackWidthFront() + t.VehicleTrackWidthRear()) / 2
+	if t.VehicleTrackWidthFront() == 0 || t.VehicleTrackWidthRear() == 0 {
+		track = defaultTrackWidth
+	}
+
+	return degrees(math.Atan2(float64(left-right), float64(track)))
+}
+
+// BodyPitchDegrees is the pitch of the body from the difference between the
+// front and rear suspension heights, positive when the nose is lower.
+func (t *transformer) BodyPitchDegrees() float32 {
+	height := t.SuspensionHeightMeters()
+	front := (height.FrontLeft + height.FrontRight) / 2
+	rear := (height.RearLeft + height.RearRight) / 2
+
+	wheelbase := t.VehicleWheelbase()
+	if wheelbase == 0 {
+		wheelbase = defaultWheelbase
+	}
+
+	return degrees(math.Atan2(float64(rear-front), float64(wheelbase)))
+}
+
+func degrees(radians float64) float32 {
+	return float32(radians * 180 / math.Pi)
+}
+
+// SuspensionBottomingOut reports the corners where the suspension hit the
+// bump stop in the current packet, seen as fast compression that stops dead
+// between two packets.
+func (t *transformer) SuspensionBottomingOut() Corners[bool] {
+	return t.suspension.bottoming
+}
+
+// WheelsOffGround reports the wheels hanging free, seen as the suspension
+// resting at the longest it has been while the vehicle is moving.
+func (t *transformer) WheelsOffGround() Corners[bool] {
+	return t.suspension.offGround
+}
+
+// SuspensionLap summarises the suspension movement over the lap in progress.
+func (t *transformer) SuspensionLap() SuspensionLap {
+	return t.suspension.lap
+}
+
+// LastSuspensionLap summarises the suspension movement over the last lap
+// completed, ok is false until a lap has been completed with the vehicle.
+func (t *transformer) LastSuspensionLap() (SuspensionLap, bool) {
+	return t.suspension.lastLap, t.suspension.hasLastLap
+}
+
+// at returns the value at a corner, counting front left, front right, rear
+// left and rear right from 0.
+func (c *Corners[Q]) at(corner int) *Q {
+	switch corner {
+	case 0:
+		return &c.FrontLeft
+	case 1:
+		return &c.FrontRight
+	case 2:
+		return &c.RearLeft
+	default:
+		return &c.RearRight
+	}
+}
@@ -0,0 +1,172 @@
+package telemetry
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/vwhitteron/gt-telemetry/internal/gttelemetry"
+	"github.com/vwhitteron/gt-telemetry/vehicles"
+)
+
+type SuspensionTestSuite struct {
+	suite.Suite
+	transformer *transformer
+	sequence    uint32
+}
+
+func TestSuspensionTestSuite(t *testing.T) {
+	suite.Run(t, new(SuspensionTestSuite))
+}
+
+func (suite *SuspensionTestSuite) SetupTest() {
+	inventory, err := vehicles.NewInventory()
+	suite.Require().NoError(err)
+
+	suite.transformer = NewTransformer(inventory)
+	suite.transformer.RawTelemetry = newRawTelemetry()
+	suite.transformer.RawTelemetry.VehicleId = 82
+	suite.transformer.RawTelemetry.CurrentLap = 1
+	suite.sequence = 100
+}
+
+// feed observes a packet for each front left suspension height with the
+// other corners held still.
+func (suite *SuspensionTestSuite) feed(heights ...float32) {
+	for _, height := range heights {
+		suite.sequence++
+		raw := &suite.transformer.RawTelemetry
+		raw.SequenceId = suite.sequence
+		*raw.SuspensionHeight = gttelemetry.GranTurismoTelemetry_CornerSet{FrontLeft: height, FrontRight: 0.1, RearLeft: 0.1, RearRight: 0.1}
+		suite.transformer.observeSuspension()
+	}
+}
+
+func (suite *SuspensionTestSuite) TestSuspensionVelocityIsMeasuredBetweenPackets() {
+	testCases := map[string]struct {
+		gap  uint32
+		want float32
+	}{
+		"consecutive packets": {gap: 0, want: -0.6},
+		"dropped packet":      {gap: 1, want: -0.3},
+		"stream restarted":    {gap: suspensionMaxGap, want: 0},
+	}
+
+	for name, tc := range testCases {
+		suite.Run(name, func() {
+			// Arrange
+			suite.SetupTest()
+			suite.feed(0.1)
+			suite.sequence += tc.gap
+
+			// Act
+			suite.feed(0.09)
+
+			// Assert
+			velocity := suite.transformer.SuspensionVelocityMetersPerSecond()
+			suite.InDelta(tc.want, velocity.FrontLeft, 1e-4)
+			suite.Zero(velocity.RearRight)
+		})
+	}
+}
+
+func (suite *SuspensionTestSuite) TestDamperHistogramSplitsBumpAndRebound() {
+	// Arrange
+	heights := []float32{0.1, 0.0999, 0.099, 0.0995, 0.1}
+
+	// Act
+	suite.feed(heights...)
+
+	// Assert
+	dampers := suite.transformer.SuspensionLap().Dampers.FrontLeft
+	suite.Equal([DamperBands]int{1, 0, 1, 0, 0}, dampers.Bump)
+	suite.Equal([DamperBands]int{0, 2, 0, 0, 0}, dampers.Rebound)
+	suite.Equal(float32(0.5), dampers.BumpShare())
+	suite.Zero(suite.transformer.SuspensionLap().Dampers.RearLeft.BumpSamples())
+	suite.Equal(float32(0.025), DamperBandLimit(0))
+	suite.True(math.IsInf(float64(DamperBandLimit(DamperBands-1)), 1))
+}
+
+func (suite *SuspensionTestSuite) TestBottomingOutIsDetected() {
+	// Arrange
+	suite.feed(0.1, 0.09)
+
+	// Act
+	suite.feed(0.09)
+	bottomed := suite.transformer.SuspensionBottomingOut()
+	suite.feed(0.0905)
+	released := suite.transformer.SuspensionBottomingOut()
+
+	// Assert
+	suite.True(bottomed.FrontLeft)
+	suite.False(bottomed.RearRight)
+	suite.False(released.FrontLeft)
+	suite.Equal(1, suite.transformer.SuspensionLap().BottomingOut.FrontLeft)
+}
+
+func (suite *SuspensionTestSuite) TestCompressionStoppingMidTravelIsNotBottomingOut() {
+	// Arrange
+	suite.feed(0.1, 0.05, 0.1, 0.09)
+
+	// Act
+	suite.feed(0.09)
+
+	// Assert
+	suite.False(suite.transformer.SuspensionBottomingOut().FrontLeft)
+	suite.Zero(suite.transformer.SuspensionLap().BottomingOut.FrontLeft)
+}
+
+func (suite *SuspensionTestSuite) TestWheelsOffGroundAreDetected() {
+	// Arrange
+	suite.transformer.RawTelemetry.GroundSpeed = 20
+	suite.feed(0.1, 0.08, 0.09, 0.1)
+
+	// Act
+	suite.feed(0.1, 0.1)
+	hanging := suite.transformer.WheelsOffGround()
+	suite.feed(0.09)
+	landed := suite.transformer.WheelsOffGround()
+
+	// Assert
+	suite.True(hanging.FrontLeft)
+	suite.False(hanging.FrontRight)
+	suite.False(landed.FrontLeft)
+	suite.Equal(1, suite.transformer.SuspensionLap().OffGround.FrontLeft)
+}
+
+func (suite *SuspensionTestSuite) TestBodyAnglesAreTakenFromSuspensionHeights() {
+	// Arrange
+	*suite.transformer.RawTelemetry.SuspensionHeight = gttelemetry.GranTurismoTelemetry_CornerSet{
+		FrontLeft: 0.08, FrontRight: 0.06, RearLeft: 0.10, RearRight: 0.08,
+	}
+
+	// Act
+	roll := suite.transformer.BodyRollDegrees()
+	pitch := suite.transformer.BodyPitchDegrees()
+
+	// Assert
+	suite.InDelta(math.Atan2(0.02, defaultTrackWidth)*180/math.Pi, roll, 1e-4)
+	suite.InDelta(math.Atan2(0.02, defaultWheelbase)*180/math.Pi, pitch, 1e-4)
+}
+
+func (suite *SuspensionTestSuite) TestLapsAreSummarised() {
+	// Arrange
+	suite.feed(0.1, 0.09, 0.1)
+	_, completed := suite.transformer.LastSuspensionLap()
+
+	// Act
+	suite.transformer.RawTelemetry.CurrentLap = 2
+	suite.feed(0.1)
+
+	// Assert
+	suite.False(completed)
+	last, ok := suite.transformer.LastSuspensionLap()
+	suite.Require().True(ok)
+	suite.Equal(int16(1), last.Lap)
+	suite.Equal(2, last.Samples)
+	suite.Equal(1, last.Dampers.FrontLeft.BumpSamples())
+	suite.InDelta(math.Atan2(0.005, defaultTrackWidth)*180/math.Pi, last.MaxRollDegrees, 1e-4)
+	suite.Equal(int16(2), suite.transformer.SuspensionLap().Lap)
+	suite.Equal(1, suite.transformer.SuspensionLap().Samples)
+}
@@ -2,155 +2,257 @@ package telemetry
 
 import (
 	"bytes"
-	"log"
-	"net"
+	"context"
+	"errors"
+	"fmt"
+	"io"
 	"net/url"
 	"os"
-	"strconv"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/kaitai-io/kaitai_struct_go_runtime/kaitai"
 	"github.com/rs/zerolog"
 
 	"github.com/vwhitteron/gt-telemetry/internal/gttelemetry"
-	"github.com/vwhitteron/gt-telemetry/internal/telemetrysrc"
-	"github.com/vwhitteron/gt-telemetry/internal/vehicles"
+	"github.com/vwhitteron/gt-telemetry/setups"
+	"github.com/vwhitteron/gt-telemetry/vehicles"
 )
 
-type statistics struct {
-	enabled           bool
-	decodeTimeLast    time.Duration
-	packetRateLast    time.Time
-	packetIDLast      uint32
-	DecodeTimeAvg     time.Duration
-	DecodeTimeMax     time.Duration
-	PacketRateAvg     int
-	PacketRateCurrent int
-	PacketRateMax     int
-	PacketsDropped    int
-	PacketsInvalid    int
-	PacketsTotal      int
-}
-
 type GTClientOpts struct {
-	Source       string
+	Source string
+	// SourceReader is read instead of the Source URL when set, such as a
+	// PacketSource or FuncSource in tests.
+	SourceReader Source
 	LogLevel     string
 	Logger       *zerolog.Logger
 	StatsEnabled bool
-	VehicleDB    string
+	// VehicleDB is an inventory file merged over the embedded vehicle
+	// inventory, followed by any VehicleOverrides.
+	VehicleDB        string
+	VehicleOverrides []string
+	// VehicleDBWatch is the interval to check the inventory files for
+	// changes and reload them while the client is running, zero disables it.
+	VehicleDBWatch time.Duration
+	// VehicleProfiles is the file that profiles learned from the telemetry
+	// of vehicles missing from the inventory are kept in, they are only
+	// kept in memory when it is empty.
+	VehicleProfiles string
+	// Setups is the file that vehicle setups and the laps driven with them
+	// are kept in, they are only kept in memory when it is empty.
+	Setups string
+	// Units is the unit system used to present values, such as the units
+	// of the client channels.
+	Units UnitSystem
+	// Console connection options for udp:// and relay:// sources, query
+	// parameters in the source URL take precedence.
+	BindAddress       string
+	Interface         string
+	ReceivePort       int
+	HeartbeatInterval time.Duration
+	StallTimeout      time.Duration
+	MaxBackoff        time.Duration
+	// PacketFormat is sent as the heartbeat to select the packet format, A
+	// by default. The energy recovery of electric and hybrid vehicles is
+	// only sent in the ~ format.
+	PacketFormat string
 }
 
 type GTClient struct {
 	log              zerolog.Logger
 	source           string
+	sourceReader     Source
 	DecipheredPacket []byte
-	Finished         bool
-	Statistics       *statistics
-	Telemetry        *transformer
+	// Deprecated: Finished is set without any synchronisation when the client
+	// stops. Use Stopped, which is safe to call at any time.
+	Finished bool
+	finished bool
+	stats    *statistics
+	// Deprecated: Statistics is updated while the client runs without any
+	// synchronisation. Use Stats, which is safe to call at any time.
+	Statistics    *LegacyStatistics
+	Telemetry     *transformer
+	subscribers   []subscriber
+	subscribersMu sync.Mutex
+	// held while a frame is delivered so that Unsubscribe only closes a
+	// channel that is not being sent on
+	publishMu     sync.Mutex
+	lastFrame     Frame
+	events        []chan StreamEvent
+	stalledAt     time.Time
+	runningSource Source
+	closed        bool
+	sourceMu      sync.Mutex
+	stopWatch     context.CancelFunc
+	// closed by Close to release a publish blocked on a subscriber
+	done      chan struct{}
+	profiles  *profileLearner
+	setups    *setupTracker
+	vehicleID uint32
 }
 
-func NewGTClient(opts GTClientOpts) (*GTClient, error) {
-	var log zerolog.Logger
+// newLogger returns the logger of the options, or a logger to stdout with
+// the log level of the options applied.
+func newLogger(opts GTClientOpts) zerolog.Logger {
 	if opts.Logger != nil {
-		log = *opts.Logger
-	} else {
-		log = zerolog.New(os.Stdout).With().Timestamp().Logger()
-
-		switch opts.LogLevel {
-		case "trace":
-			zerolog.SetGlobalLevel(zerolog.TraceLevel)
-		case "debug":
-			zerolog.SetGlobalLevel(zerolog.DebugLevel)
-		case "info":
-			zerolog.SetGlobalLevel(zerolog.InfoLevel)
-		case "warn":
-			zerolog.SetGlobalLevel(zerolog.WarnLevel)
-		case "error":
-			zerolog.SetGlobalLevel(zerolog.ErrorLevel)
-		case "fatal":
-			zerolog.SetGlobalLevel(zerolog.FatalLevel)
-		case "panic":
-			zerolog.SetGlobalLevel(zerolog.PanicLevel)
-		case "off":
-			zerolog.SetGlobalLevel(zerolog.Disabled)
-		case "":
-			zerolog.SetGlobalLevel(zerolog.WarnLevel)
-		default:
-			opts.LogLevel = "warn"
-			zerolog.SetGlobalLevel(zerolog.WarnLevel)
-			log.Warn().Str("log_level", opts.LogLevel).Msg("unknown log level, setting level to warn")
-		}
+		return *opts.Logger
 	}
 
+	log := zerolog.New(os.Stdout).With().Timestamp().Logger()
+
+	switch opts.LogLevel {
+	case "trace":
+		zerolog.SetGlobalLevel(zerolog.TraceLevel)
+	case "debug":
+		zerolog.SetGlobalLevel(zerolog.DebugLevel)
+	case "info":
+		zerolog.SetGlobalLevel(zerolog.InfoLevel)
+	case "warn":
+		zerolog.SetGlobalLevel(zerolog.WarnLevel)
+	case "error":
+		zerolog.SetGlobalLevel(zerolog.ErrorLevel)
+	case "fatal":
+		zerolog.SetGlobalLevel(zerolog.FatalLevel)
+	case "panic":
+		zerolog.SetGlobalLevel(zerolog.PanicLevel)
+	case "off":
+		zerolog.SetGlobalLevel(zerolog.Disabled)
+	case "":
+		zerolog.SetGlobalLevel(zerolog.WarnLevel)
+	default:
+		zerolog.SetGlobalLevel(zerolog.WarnLevel)
+		log.Warn().Str("log_level", opts.LogLevel).Msg("unknown log level, setting level to warn")
+	}
+
+	return log
+}
+
+func NewGTClient(opts GTClientOpts) (*GTClient, error) {
+	log := newLogger(opts)
+
 	if opts.Source == "" {
 		opts.Source = "udp://255.255.255.255:33739"
 	}
 
-	inventory, err := vehicles.NewInventory(opts.VehicleDB)
+	if opts.SourceReader == nil {
+		sourceURL, err := url.Parse(opts.Source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse source URL: %w", err)
+		}
+		if _, err := lookupSource(sourceURL.Scheme); err != nil {
+			return nil, err
+		}
+		opts.Source = withConsoleOptions(sourceURL, opts)
+	}
+
+	inventory, err := vehicles.NewInventory(append([]string{opts.VehicleDB}, opts.VehicleOverrides...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	profiles, err := vehicles.OpenProfileStore(opts.VehicleProfiles)
 	if err != nil {
 		return nil, err
 	}
 
-	return &GTClient{
+	setupStore, err := setups.Open(opts.Setups)
+	if err != nil {
+		return nil, err
+	}
+
+	transformer := NewTransformer(inventory)
+	transformer.units = opts.Units
+	transformer.profiles = profiles
+
+	client := &GTClient{
 		log:              log,
 		source:           opts.Source,
+		sourceReader:     opts.SourceReader,
 		DecipheredPacket: []byte{},
-		Finished:         false,
-		Statistics: &statistics{
-			enabled:           opts.StatsEnabled,
-			decodeTimeLast:    time.Duration(0),
-			packetRateLast:    time.Now(),
-			DecodeTimeAvg:     time.Duration(0),
-			DecodeTimeMax:     time.Duration(0),
-			PacketRateCurrent: 0,
-			PacketRateMax:     0,
-			PacketRateAvg:     0,
-			PacketsTotal:      0,
-			PacketsDropped:    0,
-			PacketsInvalid:    0,
-			packetIDLast:      0,
-		},
-		Telemetry: NewTransformer(inventory),
-	}, nil
-}
-
-func (c *GTClient) Run() {
-	sourceURL, err := url.Parse(c.source)
-	if err != nil {
-		log.Fatal(err)
+		stats:            newStatistics(opts.StatsEnabled),
+		Statistics:       &LegacyStatistics{},
+		Telemetry:        transformer,
+		profiles:         newProfileLearner(profiles),
+		setups:           newSetupTracker(setupStore),
+		done:             make(chan struct{}),
 	}
 
-	var telemetrySource telemetrysrc.Reader
+	if opts.VehicleDBWatch > 0 {
+		client.watchInventory(opts.VehicleDBWatch)
+	}
+
+	return client, nil
+}
+
+// watchInventory reloads the vehicle inventory when its files change until
+// the client is closed.
+func (c *GTClient) watchInventory(interval time.Duration) {
+	ctx, cancel := context.WithCancel(context.Background())
+	c.stopWatch = cancel
 
-	switch sourceURL.Scheme {
-	case "udp":
-		host, portStr, _ := net.SplitHostPort(sourceURL.Host)
-		port, err := strconv.Atoi(portStr)
+	errs := c.Telemetry.inventory.Watch(ctx, interval)
+	go func() {
+		for err := range errs {
+			c.log.Warn().Err(err).Msg("failed to reload vehicle inventory")
+		}
+	}()
+}
+
+// Run reads the telemetry source until it ends or the client is closed. An
+// error is returned when the source can not be opened, subscriber and event
+// channels are closed either way.
+func (c *GTClient) Run() error {
+	telemetrySource := c.sourceReader
+	if telemetrySource == nil {
+		var err error
+		telemetrySource, err = openSource(c.source, c.log)
 		if err != nil {
-			c.log.Fatal().Err(err).Msg("failed to parse port")
+			c.log.Error().Err(err).Msg("failed to open telemetry source")
+			c.finish()
+
+			return fmt.Errorf("failed to open telemetry source: %w", err)
 		}
-		telemetrySource = telemetrysrc.NewNetworkUDPReader(host, port, c.log)
-	case "file":
-		telemetrySource = telemetrysrc.NewFileReader(sourceURL.Host+sourceURL.Path, c.log)
-	default:
-		c.log.Fatal().Msgf("unknown URL scheme %q", sourceURL.Scheme)
+	}
+	if !c.running(telemetrySource) {
+		telemetrySource.Close()
+		c.finish()
+
+		return nil
+	}
+	defer c.closeSource(telemetrySource)
+
+	realtime := isRealtime(c.source)
+	if rs, ok := telemetrySource.(realtimeSource); ok {
+		realtime = rs.Realtime()
 	}
 
 	rawTelemetry := gttelemetry.NewGranTurismoTelemetry()
 
 	for {
+		if c.isClosed() {
+			c.finish()
+
+			return nil
+		}
+
 		bufLen, buffer, err := telemetrySource.Read()
 		if err != nil {
-			if err.Error() == "bufio.Scanner: SplitFunc returns advance count beyond input" {
-				c.Finished = true
+			if errors.Is(err, io.EOF) || c.isClosed() {
+				c.finish()
 
-				continue
+				return nil
+			}
+
+			if errors.Is(err, ErrStreamStalled) {
+				c.streamStalled()
 			}
 
 			c.log.Debug().Err(err).Msg("failed to receive telemetry")
 
 			continue
 		}
+		c.streamResumed()
 
 		if len(buffer[:bufLen]) == 0 {
 			c.log.Debug().Msg("no data received")
@@ -165,63 +267,304 @@ func (c *GTClient) Run() {
 		stream := kaitai.NewStream(reader)
 
 		for {
+			// optional blocks are only set when the packet format has them
+			*rawTelemetry = gttelemetry.GranTurismoTelemetry{}
 			err = rawTelemetry.Read(stream, nil, nil)
 			if err != nil {
 				if err.Error() == "EOF" {
 					break
 				}
 				c.log.Error().Err(err).Msg("failed to parse telemetry")
-				c.Statistics.PacketsInvalid++
+				if c.stats.enabled {
+					c.stats.observeInvalid()
+				}
 			}
 
 			c.Telemetry.RawTelemetry = *rawTelemetry
+			c.Telemetry.observeDrive()
+			c.Telemetry.observeSuspension()
+			c.trackVehicle()
+			c.trackSetup()
 
-			c.Statistics.decodeTimeLast = time.Since(decodeStart)
-			c.collectStats()
+			c.collectStats(time.Since(decodeStart))
+			c.publish()
 
-			timer := time.NewTimer(4 * time.Millisecond)
-			<-timer.C
+			if realtime {
+				timer := time.NewTimer(4 * time.Millisecond)
+				<-timer.C
+			}
 		}
 	}
 }
 
-func (c *GTClient) collectStats() {
-	if !c.Statistics.enabled {
+// Close stops a running client and closes its source. Subscriber and event
+// channels are closed once the client has stopped.
+func (c *GTClient) Close() error {
+	c.sourceMu.Lock()
+	defer c.sourceMu.Unlock()
+
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	close(c.done)
+
+	if c.stopWatch != nil {
+		c.stopWatch()
+	}
+
+	if c.runningSource == nil {
+		return nil
+	}
+
+	return c.runningSource.Close()
+}
+
+// running records the source being read so that Close can interrupt it, and
+// reports false when the client was closed before it started.
+func (c *GTClient) running(source Source) bool {
+	c.sourceMu.Lock()
+	defer c.sourceMu.Unlock()
+
+	c.runningSource = source
+
+	return !c.closed
+}
+
+// closeSource closes the source once Run has finished reading it, unless
+// Close has already closed it.
+func (c *GTClient) closeSource(source Source) {
+	c.sourceMu.Lock()
+	defer c.sourceMu.Unlock()
+
+	c.runningSource = nil
+	if c.closed {
 		return
 	}
 
-	c.Statistics.PacketsTotal++
+	if err := source.Close(); err != nil {
+		c.log.Debug().Err(err).Msg("failed to close telemetry source")
+	}
+}
+
+func (c *GTClient) isClosed() bool {
+	c.sourceMu.Lock()
+	defer c.sourceMu.Unlock()
+
+	return c.closed
+}
+
+func (c *GTClient) finish() {
+	c.closeSubscribers()
+}
 
-	if c.Statistics.packetIDLast != c.Telemetry.SequenceID() {
-		if c.Statistics.packetIDLast == 0 {
-			c.Statistics.packetIDLast = c.Telemetry.SequenceID()
+// Stopped reports whether the client has stopped reading its source, after
+// which the subscriber and event channels are closed.
+func (c *GTClient) Stopped() bool {
+	c.subscribersMu.Lock()
+	defer c.subscribersMu.Unlock()
+
+	return c.finished
+}
+
+type subscriber struct {
+	frames chan Frame
+	// closed by Unsubscribe to release a publish blocked on the subscriber
+	gone chan struct{}
+}
+
+// Subscribe returns a channel that receives a frame for every decoded packet.
+// Frames are never dropped: delivery blocks the client until the frame is
+// received, the subscriber unsubscribes or the client is closed. A subscriber
+// that falls behind the packet rate therefore holds up every other
+// subscriber, the heartbeats and the statistics, and one that stops reading
+// must call Unsubscribe. Each subscriber receives its own copy of the frame.
+// The channel is closed when a replay file ends.
+func (c *GTClient) Subscribe(buffer int) <-chan Frame {
+	c.subscribersMu.Lock()
+	defer c.subscribersMu.Unlock()
+
+	ch := make(chan Frame, buffer)
+	if c.finished {
+		close(ch)
+
+		return ch
+	}
+
+	c.subscribers = append(c.subscribers, subscriber{frames: ch, gone: make(chan struct{})})
+
+	return ch
+}
+
+// Unsubscribe stops the delivery of frames to a channel returned by
+// Subscribe and closes it.
+func (c *GTClient) Unsubscribe(frames <-chan Frame) {
+	c.subscribersMu.Lock()
+	i := slices.IndexFunc(c.subscribers, func(s subscriber) bool { return s.frames == frames })
+	if i < 0 {
+		c.subscribersMu.Unlock()
+		return
+	}
+	s := c.subscribers[i]
+	c.subscribers = slices.Delete(c.subscribers, i, i+1)
+	c.subscribersMu.Unlock()
+
+	close(s.gone)
+
+	c.publishMu.Lock()
+	defer c.publishMu.Unlock()
+
+	close(s.frames)
+}
+
+// Events returns a channel that receives an event when the telemetry stream
+// stalls or resumes, a vehicle missing from the inventory is driven, or the
+// setup of the vehicle changes. Events are dropped when the channel buffer is
+// full and the channel is closed when a replay file ends.
+func (c *GTClient) Events(buffer int) <-chan StreamEvent {
+	c.subscribersMu.Lock()
+	defer c.subscribersMu.Unlock()
+
+	ch := make(chan StreamEvent, buffer)
+	if c.finished {
+		close(ch)
+
+		return ch
+	}
+
+	c.events = append(c.events, ch)
+
+	return ch
+}
+
+// LastFrame returns a copy of the most recently decoded packet that is safe
+// to read while the client is running.
+func (c *GTClient) LastFrame() (Frame, bool) {
+	c.subscribersMu.Lock()
+	defer c.subscribersMu.Unlock()
+
+	if c.lastFrame.transformer == nil {
+		return Frame{}, false
+	}
+
+	return c.lastFrame.copy(), true
+}
+
+// publish sends the frame outside of the lock so that a slow subscriber
+// does not block LastFrame, Subscribe or Events.
+func (c *GTClient) publish() {
+	frame := NewFrame(c.Telemetry, time.Now())
+
+	c.subscribersMu.Lock()
+	c.lastFrame = frame
+	subscribers := slices.Clone(c.subscribers)
+	c.subscribersMu.Unlock()
+
+	c.publishMu.Lock()
+	defer c.publishMu.Unlock()
+
+	for _, s := range subscribers {
+		select {
+		case s.frames <- frame.copy():
+		case <-s.gone:
+		case <-c.done:
 			return
 		}
+	}
+}
 
-		c.Statistics.DecodeTimeAvg = (c.Statistics.DecodeTimeAvg + c.Statistics.decodeTimeLast) / 2
-		if c.Statistics.decodeTimeLast > c.Statistics.DecodeTimeMax {
-			c.Statistics.DecodeTimeMax = c.Statistics.decodeTimeLast
-		}
+func (c *GTClient) closeSubscribers() {
+	c.subscribersMu.Lock()
+	defer c.subscribersMu.Unlock()
 
-		delta := int(c.Telemetry.SequenceID() - c.Statistics.packetIDLast)
-		if delta > 1 {
-			c.log.Warn().Int("count", delta-1).Msg("packets dropped")
-			c.Statistics.PacketsDropped += int(delta - 1)
-		} else if delta < 0 {
-			c.log.Warn().Int("count", 1).Msg("packets delayed")
-		}
+	c.finished = true
+	c.Finished = true
 
-		c.Statistics.packetIDLast = c.Telemetry.SequenceID()
+	for _, s := range c.subscribers {
+		close(s.frames)
+	}
+	c.subscribers = nil
 
-		if c.Telemetry.SequenceID()%10 == 0 {
-			rate := time.Since(c.Statistics.packetRateLast)
-			c.Statistics.PacketRateCurrent = int(10 / rate.Seconds())
-			c.Statistics.packetRateLast = time.Now()
-			c.Statistics.PacketRateAvg = (c.Statistics.PacketRateAvg + c.Statistics.PacketRateCurrent) / 2
-			if c.Statistics.PacketRateCurrent > c.Statistics.PacketRateMax {
-				c.Statistics.PacketRateMax = c.Statistics.PacketRateCurrent
-			}
+	for _, ch := range c.events {
+		close(ch)
+	}
+	c.events = nil
+}
+
+// trackVehicle reports vehicles that are not in the inventory and learns
+// their profile while they are driven.
+func (c *GTClient) trackVehicle() {
+	id := c.Telemetry.RawTelemetry.VehicleId
+	if id != c.vehicleID {
+		c.vehicleID = id
+		c.profiles.stop()
+
+		if _, known := c.Telemetry.CurrentVehicle(); !known && id != 0 {
+			c.unknownVehicle(id)
+			c.profiles.start(int(id))
 		}
 	}
 
+	if err := c.profiles.observe(c.Telemetry); err != nil {
+		c.log.Warn().Err(err).Uint32("vehicle_id", id).Msg("failed to store vehicle profile")
+	}
+}
+
+// trackSetup identifies the setup of the vehicle and records the laps driven
+// with it.
+func (c *GTClient) trackSetup() {
+	changed, created, err := c.setups.observe(c.Telemetry)
+	if err != nil {
+		c.log.Warn().Err(err).Msg("failed to store vehicle setup")
+	}
+	if !changed {
+		return
+	}
+
+	setup := c.Telemetry.setup
+	c.log.Debug().Str("setup_id", setup.ID).Bool("created", created).Msg("vehicle setup changed")
+	c.setupChanged(setup.ID, uint32(setup.VehicleID))
+}
+
+// Setups returns the store of vehicle setups and the laps driven with them.
+func (c *GTClient) Setups() *setups.Store {
+	return c.setups.store
+}
+
+// Profiles returns the profiles learned for vehicles that are not in the
+// inventory.
+func (c *GTClient) Profiles() *vehicles.ProfileStore {
+	return c.Telemetry.profiles
+}
+
+// Inventory returns the vehicle inventory used to identify the vehicle being
+// driven.
+func (c *GTClient) Inventory() *vehicles.Inventory {
+	return c.Telemetry.inventory
+}
+
+// Units returns the unit system the client presents values in.
+func (c *GTClient) Units() UnitSystem {
+	return c.Telemetry.units
+}
+
+// Channels returns every channel in the unit system of the client.
+func (c *GTClient) Channels() []Channel {
+	return Channels(c.Telemetry.units)
+}
+
+// Stats returns a snapshot of the packet statistics that is safe to call
+// while the client is running. The counters stay at zero unless statistics
+// are enabled.
+func (c *GTClient) Stats() Stats {
+	return c.stats.snapshot()
+}
+
+func (c *GTClient) collectStats(decodeTime time.Duration) {
+	if !c.stats.enabled {
+		return
+	}
+
+	c.stats.observe(c.Telemetry.SequenceID(), time.Now(), decodeTime)
+	c.stats.legacy(c.Statistics)
 }
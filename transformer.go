@@ -6,7 +6,8 @@ import (
 
 	"github.com/vwhitteron/gt-telemetry/internal/gttelemetry"
 	"github.com/vwhitteron/gt-telemetry/internal/utils"
-	"github.com/vwhitteron/gt-telemetry/internal/vehicles"
+	"github.com/vwhitteron/gt-telemetry/setups"
+	"github.com/vwhitteron/gt-telemetry/vehicles"
 )
 
 type CornerSet struct {
@@ -67,6 +68,25 @@ type transformer struct {
 	RawTelemetry gttelemetry.GranTurismoTelemetry
 	inventory    *vehicles.Inventory
 	vehicle      vehicles.Vehicle
+	vehicleKnown bool
+	// vehicle ID and inventory version of the last lookup, which is kept for
+	// vehicles missing from the inventory too
+	vehicleID       int
+	vehicleVersion  uint64
+	vehicleResolved bool
+	// learned profile of a vehicle that is not in the inventory
+	profiles     *vehicles.ProfileStore
+	profile      vehicles.Profile
+	profileKnown bool
+	// setup identified by the client from the telemetry
+	setup      setups.Setup
+	setupKnown bool
+	drive      driveEstimate
+	suspension suspensionDynamics
+	units      UnitSystem
+	// frames hold a snapshot with the vehicle already resolved, which is
+	// never updated so that frames can be read from several goroutines
+	frozen bool
 }
 
 func NewTransformer(inventory *vehicles.Inventory) *transformer {
@@ -101,7 +121,10 @@ func (t *transformer) BrakePercent() float32 {
 func (t *transformer) CalculatedVmax() Vmax {
 	vMaxSpeed := t.RawTelemetry.CalculatedMaxSpeed
 	vMaxMetersPerMinute := float32(vMaxSpeed) * 1000 / 60
-	tyreCircumference := t.TyreDiameterMeters().RearLeft * math.Pi
+	tyreCircumference := t.DrivenTyreDiameterMeters() * math.Pi
+	if tyreCircumference == 0 {
+		return Vmax{Speed: vMaxSpeed}
+	}
 
 	return Vmax{
 		Speed: vMaxSpeed,
@@ -133,7 +156,7 @@ func (t *transformer) CurrentGear() int {
 
 func (t *transformer) CurrentGearRatio() float32 {
 	gear := t.CurrentGear()
-	if gear > len(t.Transmission().GearRatios) {
+	if gear < 1 || gear > len(t.Transmission().GearRatios) {
 		return -1
 	}
 
@@ -154,12 +177,9 @@ func (t *transformer) DifferentialRatio() float32 {
 	highestRatio := transmission.GearRatios[transmission.Gears-1]
 	vMax := t.CalculatedVmax()
 
-	rollingDiameter := float32(0)
-	switch t.vehicle.Drivetrain {
-	case "FF":
-		rollingDiameter = t.TyreDiameterMeters().FrontLeft
-	default:
-		rollingDiameter = t.TyreDiameterMeters().RearLeft
+	rollingDiameter := t.DrivenTyreDiameterMeters()
+	if rollingDiameter == 0 || vMax.Speed == 0 {
+		return -1
 	}
 
 	vMaxMetersPerMinute := float32(vMax.Speed) * 1000 / 60
@@ -426,9 +446,35 @@ func (t *transformer) VehicleID() uint32 {
 	return t.RawTelemetry.VehicleId
 }
 
+// CurrentVehicle returns the inventory record of the vehicle being driven,
+// ok is false when the vehicle is not in the inventory.
+func (t *transformer) CurrentVehicle() (vehicles.Vehicle, bool) {
+	t.updateVehicle()
+
+	return t.vehicle, t.vehicleKnown
+}
+
+// VehicleProfile returns the profile learned from the telemetry of a vehicle
+// that is not in the inventory, ok is false when there is none.
+func (t *transformer) VehicleProfile() (vehicles.Profile, bool) {
+	t.updateVehicle()
+
+	return t.profile, t.profileKnown
+}
+
+// ActiveSetup returns the setup the vehicle is being driven with, ok is false
+// until the client has identified it from the telemetry.
+func (t *transformer) ActiveSetup() (setups.Setup, bool) {
+	return t.setup, t.setupKnown
+}
+
 func (t *transformer) VehicleAspiration() string {
 	t.updateVehicle()
 
+	if !t.vehicleKnown && t.profileKnown {
+		return t.profile.Aspiration()
+	}
+
 	return t.vehicle.Aspiration
 }
 
@@ -453,6 +499,10 @@ func (t *transformer) VehicleCategory() string {
 func (t *transformer) VehicleDrivetrain() string {
 	t.updateVehicle()
 
+	if !t.vehicleKnown && t.profileKnown {
+		return t.profile.Drivetrain()
+	}
+
 	return t.vehicle.Drivetrain
 }
 
@@ -480,6 +530,75 @@ func (t *transformer) VehicleYear() int {
 	return t.vehicle.Year
 }
 
+func (t *transformer) VehiclePower() Power {
+	t.updateVehicle()
+
+	return Power(t.vehicle.PowerKW)
+}
+
+// VehicleFuelTank is the capacity of the fuel tank, zero when unknown.
+func (t *transformer) VehicleFuelTank() Volume {
+	t.updateVehicle()
+
+	return Volume(t.vehicle.FuelTankLitres)
+}
+
+func (t *transformer) VehicleTorque() Torque {
+	t.updateVehicle()
+
+	return Torque(t.vehicle.TorqueNM)
+}
+
+func (t *transformer) VehicleWeight() Mass {
+	t.updateVehicle()
+
+	return Mass(t.vehicle.WeightKG)
+}
+
+// VehicleWeightDistribution returns the percentage of the vehicle weight on
+// the front axle.
+func (t *transformer) VehicleWeightDistribution() float32 {
+	t.updateVehicle()
+
+	return t.vehicle.WeightDistribution
+}
+
+func (t *transformer) VehicleWheelbase() Length {
+	t.updateVehicle()
+
+	return Length(t.vehicle.WheelbaseMM / 1000)
+}
+
+func (t *transformer) VehicleTrackWidthFront() Length {
+	t.updateVehicle()
+
+	return Length(t.vehicle.TrackWidthFrontMM / 1000)
+}
+
+func (t *transformer) VehicleTrackWidthRear() Length {
+	t.updateVehicle()
+
+	return Length(t.vehicle.TrackWidthRearMM / 1000)
+}
+
+func (t *transformer) VehiclePerformancePoints() float32 {
+	t.updateVehicle()
+
+	return t.vehicle.PP
+}
+
+func (t *transformer) VehicleEngineLayout() string {
+	t.updateVehicle()
+
+	return t.vehicle.EngineLayout
+}
+
+func (t *transformer) VehicleDisplacement() Volume {
+	t.updateVehicle()
+
+	return Volume(float32(t.vehicle.DisplacementCC) / 1000)
+}
+
 func (t *transformer) WheelSpeedMetersPerSecond() CornerSet {
 	radius := t.TyreRadiusMeters()
 	rps := t.WheelSpeedRadiansPerSecond()
@@ -488,7 +607,7 @@ func (t *transformer) WheelSpeedMetersPerSecond() CornerSet {
 		FrontLeft:  rps.FrontLeft * radius.FrontLeft,
 		FrontRight: rps.FrontRight * radius.FrontRight,
 		RearLeft:   rps.RearLeft * radius.RearLeft,
-		RearRight:  rps.RearRight * radius.RearLeft,
+		RearRight:  rps.RearRight * radius.RearRight,
 	}
 }
 
@@ -524,12 +643,27 @@ func (t *transformer) WaterTemperatureCelsius() float32 {
 }
 
 func (t *transformer) updateVehicle() {
-	if uint32(t.vehicle.ID) != t.RawTelemetry.VehicleId {
-		vehicle, err := t.inventory.GetVehicleByID(int(t.RawTelemetry.VehicleId))
+	if t.frozen {
+		return
+	}
+
+	id := int(t.RawTelemetry.VehicleId)
+	version := t.inventory.Version()
+	if !t.vehicleResolved || t.vehicleID != id || t.vehicleVersion != version {
+		vehicle, err := t.inventory.GetVehicleByID(id)
 		if err != nil {
-			t.vehicle = vehicles.Vehicle{}
+			vehicle = vehicles.Vehicle{}
 		}
-
 		t.vehicle = vehicle
+		t.vehicleKnown = err == nil
+		t.vehicleID = id
+		t.vehicleVersion = version
+		t.vehicleResolved = true
+	}
+
+	// profiles are learned while the vehicle is driven so they are not cached
+	t.profile, t.profileKnown = vehicles.Profile{}, false
+	if !t.vehicleKnown && t.profiles != nil {
+		t.profile, t.profileKnown = t.profiles.Get(id)
 	}
 }
@@ -1,13 +1,15 @@
 package telemetry
 
 import (
+	"os"
+	"path/filepath"
 	"strconv"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/suite"
 	"github.com/vwhitteron/gt-telemetry/internal/gttelemetry"
-	"github.com/vwhitteron/gt-telemetry/internal/vehicles"
+	"github.com/vwhitteron/gt-telemetry/vehicles"
 )
 
 type TransformerTestSuite struct {
@@ -924,3 +926,106 @@ func (suite *TransformerTestSuite) TestTransformerWaterTemperatureCelsiusReports
 	// Assert
 	suite.Equal(wantValue, gotValue)
 }
+
+func (suite *TransformerTestSuite) TestTransformerCurrentVehicleReturnsTheInventoryRecord() {
+	// Arrange
+	inventory, err := vehicles.NewInventory("")
+	suite.Require().NoError(err)
+	transformer := NewTransformer(inventory)
+	transformer.RawTelemetry.VehicleId = 82
+
+	// Act
+	vehicle, ok := transformer.CurrentVehicle()
+
+	// Assert
+	suite.True(ok)
+	suite.Equal(82, vehicle.ID)
+	suite.Equal("Supra RZ '97", vehicle.Model)
+}
+
+func (suite *TransformerTestSuite) TestTransformerCurrentVehicleReportsUnknownVehicles() {
+	// Arrange
+	suite.transformer.RawTelemetry.VehicleId = 82
+
+	// Act
+	vehicle, ok := suite.transformer.CurrentVehicle()
+
+	// Assert
+	suite.False(ok)
+	suite.Equal(vehicles.Vehicle{}, vehicle)
+}
+
+func (suite *TransformerTestSuite) TestTransformerCurrentVehicleFollowsInventoryReloads() {
+	// Arrange
+	file := filepath.Join(suite.T().TempDir(), "override.json")
+	suite.Require().NoError(os.WriteFile(file, []byte(`{"82": {"Drivetrain": "4WD"}}`), 0o644))
+	inventory, err := vehicles.NewInventory(file)
+	suite.Require().NoError(err)
+	transformer := NewTransformer(inventory)
+	transformer.RawTelemetry.VehicleId = 82
+	suite.Equal("4WD", transformer.VehicleDrivetrain())
+
+	// Act
+	suite.Require().NoError(os.WriteFile(file, []byte(`{"82": {"Drivetrain": "MR"}}`), 0o644))
+	suite.Require().NoError(inventory.Reload())
+
+	// Assert
+	suite.Equal("MR", transformer.VehicleDrivetrain())
+}
+
+func (suite *TransformerTestSuite) TestTransformerUnknownVehiclesAreLookedUpAgainAfterReloads() {
+	// Arrange
+	file := filepath.Join(suite.T().TempDir(), "override.json")
+	suite.Require().NoError(os.WriteFile(file, []byte(`{}`), 0o644))
+	inventory, err := vehicles.NewInventory(file)
+	suite.Require().NoError(err)
+	transformer := NewTransformer(inventory)
+	transformer.RawTelemetry.VehicleId = 99999
+	_, knownBefore := transformer.CurrentVehicle()
+
+	// Act
+	suite.Require().NoError(os.WriteFile(file, []byte(`{"99999": {"CarID": 99999, "Model": "Test", "Manufacturer": "Test", "Drivetrain": "MR"}}`), 0o644))
+	suite.Require().NoError(inventory.Reload())
+	_, knownAfter := transformer.CurrentVehicle()
+
+	// Assert
+	suite.False(knownBefore)
+	suite.True(knownAfter)
+	suite.Equal("MR", transformer.VehicleDrivetrain())
+}
+
+func (suite *TransformerTestSuite) TestTransformerVehicleSpecificationsComeFromTheInventory() {
+	// Arrange
+	file := filepath.Join(suite.T().TempDir(), "specs.json")
+	specs := `{"82": {"PowerKW": 206, "TorqueNM": 451, "WeightKG": 1510, "WeightDistribution": 53, "WheelbaseMM": 2550,
+		"TrackWidthFrontMM": 1520, "TrackWidthRearMM": 1525, "PP": 480.5, "EngineLayout": "I6", "DisplacementCC": 2997,
+		"FuelTankLitres": 60}}`
+	suite.Require().NoError(os.WriteFile(file, []byte(specs), 0o644))
+	inventory, err := vehicles.NewInventory(file)
+	suite.Require().NoError(err)
+	transformer := NewTransformer(inventory)
+	transformer.RawTelemetry.VehicleId = 82
+
+	// Assert
+	suite.Equal(Power(206), transformer.VehiclePower())
+	suite.Equal(Torque(451), transformer.VehicleTorque())
+	suite.Equal(Mass(1510), transformer.VehicleWeight())
+	suite.Equal(float32(53), transformer.VehicleWeightDistribution())
+	suite.InDelta(2.55, transformer.VehicleWheelbase().In(Meters), 0.0001)
+	suite.InDelta(1.52, transformer.VehicleTrackWidthFront().In(Meters), 0.0001)
+	suite.InDelta(1.525, transformer.VehicleTrackWidthRear().In(Meters), 0.0001)
+	suite.Equal(float32(480.5), transformer.VehiclePerformancePoints())
+	suite.Equal(Volume(60), transformer.VehicleFuelTank())
+	suite.Equal("I6", transformer.VehicleEngineLayout())
+	suite.InDelta(2.997, transformer.VehicleDisplacement().In(Litres), 0.0001)
+}
+
+func (suite *TransformerTestSuite) TestTransformerUnknownVehicleSpecificationsAreZero() {
+	// Arrange
+	suite.transformer.RawTelemetry.VehicleId = 82
+
+	// Assert
+	suite.Zero(suite.transformer.VehiclePower())
+	suite.Zero(suite.transformer.VehicleWheelbase())
+	suite.Empty(suite.transformer.VehicleEngineLayout())
+}
@@ -144,10 +144,10 @@ func (t *transformer) TyreTemperatureFahrenheit() CornerSet {
 	set := t.TyreTemperatureCelsius()
 
 	return CornerSet{
-		utils.MetersPerSecondToMilesPerHour(set.FrontLeft),
-		utils.MetersPerSecondToMilesPerHour(set.FrontRight),
-		utils.MetersPerSecondToMilesPerHour(set.RearLeft),
-		utils.MetersPerSecondToMilesPerHour(set.RearRight),
+		utils.CelsiusToFahrenheit(set.FrontLeft),
+		utils.CelsiusToFahrenheit(set.FrontRight),
+		utils.CelsiusToFahrenheit(set.RearLeft),
+		utils.CelsiusToFahrenheit(set.RearRight),
 	}
 }
 
@@ -187,3 +187,68 @@ func (t *transformer) WheelSpeedRPM() CornerSet {
 func (t *transformer) WaterTemperatureFahrenheit() float32 {
 	return utils.CelsiusToFahrenheit(t.RawTelemetry.WaterTemperature)
 }
+
+// Units returns the unit system preferred by the client.
+func (t *transformer) Units() UnitSystem {
+	return t.units
+}
+
+// FuelLevel is the fuel remaining in the tank. The game only sends the share
+// of the tank that is left, so ok is false when the size of the tank is not
+// in the inventory or the vehicle has no combustion engine.
+func (t *transformer) FuelLevel() (Volume, bool) {
+	tank := t.VehicleFuelTank()
+	if tank <= 0 || !t.HasCombustionEngine() {
+		return 0, false
+	}
+
+	return tank * Volume(t.FuelLevelPercent()/100), true
+}
+
+func (t *transformer) GroundSpeed() Speed {
+	return Speed(t.GroundSpeedMetersPerSecond())
+}
+
+func (t *transformer) OilPressure() Pressure {
+	return Pressure(t.OilPressureKPA() / 100)
+}
+
+func (t *transformer) OilTemperature() Temperature {
+	return Temperature(t.OilTemperatureCelsius())
+}
+
+func (t *transformer) RideHeight() Length {
+	return Length(t.RideHeightMeters())
+}
+
+func (t *transformer) SuspensionHeight() Corners[Length] {
+	return cornersOf[Length](t.SuspensionHeightMeters())
+}
+
+func (t *transformer) TurboBoost() Pressure {
+	return Pressure(t.TurboBoostBar())
+}
+
+func (t *transformer) TyreDiameter() Corners[Length] {
+	return cornersOf[Length](t.TyreDiameterMeters())
+}
+
+func (t *transformer) TyreRadius() Corners[Length] {
+	return cornersOf[Length](t.TyreRadiusMeters())
+}
+
+func (t *transformer) TyreTemperature() Corners[Temperature] {
+	return cornersOf[Temperature](t.TyreTemperatureCelsius())
+}
+
+func (t *transformer) VmaxSpeed() Speed {
+	return Speed(utils.KilometersPerHourToMetersPerSecond(float32(t.RawTelemetry.CalculatedMaxSpeed)))
+}
+
+func (t *transformer) WaterTemperature() Temperature {
+	return Temperature(t.WaterTemperatureCelsius())
+}
+
+func (t *transformer) WheelSpeed() Corners[Speed] {
+	return cornersOf[Speed](t.WheelSpeedMetersPerSecond())
+}
@@ -6,7 +6,7 @@ import (
 
 	"github.com/stretchr/testify/suite"
 	"github.com/vwhitteron/gt-telemetry/internal/gttelemetry"
-	"github.com/vwhitteron/gt-telemetry/internal/vehicles"
+	"github.com/vwhitteron/gt-telemetry/vehicles"
 )
 
 type UnitAlternatesTestSuite struct {
@@ -310,10 +310,10 @@ func (suite *TransformerTestSuite) TestUnitAlternatesTyreTemperatureFahrenheitRe
 	gotValue := suite.transformer.TyreTemperatureFahrenheit()
 
 	// Assert
-	suite.Equal(float32(143.835), gotValue.FrontLeft)
-	suite.Equal(float32(143.38762), gotValue.FrontRight)
-	suite.Equal(float32(152.55905), gotValue.RearLeft)
-	suite.Equal(float32(151.66429), gotValue.RearRight)
+	suite.InDelta(147.74, gotValue.FrontLeft, 0.001)
+	suite.InDelta(147.38, gotValue.FrontRight, 0.001)
+	suite.InDelta(154.76, gotValue.RearLeft, 0.001)
+	suite.InDelta(154.04, gotValue.RearRight, 0.001)
 }
 
 func (suite *TransformerTestSuite) TestUnitAlternatesWheelSpeedKPHReturnsCorrectValue() {
@@ -0,0 +1,387 @@
+package telemetry
+
+import (
+	"strconv"
+
+	"github.com/vwhitteron/gt-telemetry/internal/utils"
+)
+
+// Speed is a speed in meters per second.
+type Speed float32
+
+// Pressure is a pressure in bar.
+type Pressure float32
+
+// Temperature is a temperature in degrees Celsius.
+type Temperature float32
+
+// Length is a length in meters.
+type Length float32
+
+// Volume is a volume in litres.
+type Volume float32
+
+// Power is a power in kilowatts.
+type Power float32
+
+// Torque is a torque in newton meters.
+type Torque float32
+
+// Mass is a mass in kilograms.
+type Mass float32
+
+type SpeedUnit int
+
+const (
+	MetersPerSecond SpeedUnit = iota
+	KilometersPerHour
+	MilesPerHour
+)
+
+type PressureUnit int
+
+const (
+	Bar PressureUnit = iota
+	KiloPascals
+	PoundsPerSquareInch
+	InchesOfMercury
+)
+
+type TemperatureUnit int
+
+const (
+	Celsius TemperatureUnit = iota
+	Fahrenheit
+	Kelvin
+)
+
+type LengthUnit int
+
+const (
+	Meters LengthUnit = iota
+	Millimeters
+	Inches
+	Feet
+)
+
+type VolumeUnit int
+
+const (
+	Litres VolumeUnit = iota
+	USGallons
+	ImperialGallons
+)
+
+type PowerUnit int
+
+const (
+	Kilowatts PowerUnit = iota
+	Horsepower
+	MetricHorsepower
+)
+
+type TorqueUnit int
+
+const (
+	NewtonMeters TorqueUnit = iota
+	PoundFeet
+	KilogramForceMeters
+)
+
+type MassUnit int
+
+const (
+	Kilograms MassUnit = iota
+	Pounds
+)
+
+// Corners holds a quantity for each wheel of the vehicle.
+type Corners[Q any] struct {
+	FrontLeft  Q
+	FrontRight Q
+	RearLeft   Q
+	RearRight  Q
+}
+
+// Unit symbols and the conversions from the base unit, the conversion
+// factors are kept with the unit alternates in the utils package
+var (
+	speedUnits = map[SpeedUnit]struct {
+		symbol  string
+		convert func(float32) float32
+	}{
+		MetersPerSecond:   {"m/s", same},
+		KilometersPerHour: {"km/h", utils.MetersPerSecondToKilometersPerHour},
+		MilesPerHour:      {"mph", utils.MetersPerSecondToMilesPerHour},
+	}
+	pressureUnits = map[PressureUnit]struct {
+		symbol  string
+		convert func(float32) float32
+	}{
+		Bar:                 {"bar", same},
+		KiloPascals:         {"kPa", utils.BarToKPA},
+		PoundsPerSquareInch: {"psi", utils.BarToPSI},
+		InchesOfMercury:     {"inHg", utils.BarToInHg},
+	}
+	lengthUnits = map[LengthUnit]struct {
+		symbol  string
+		convert func(float32) float32
+	}{
+		Meters:      {"m", same},
+		Millimeters: {"mm", utils.MetersToMillimeters},
+		Inches:      {"in", utils.MetersToInches},
+		Feet:        {"ft", utils.MetersToFeet},
+	}
+	volumeUnits = map[VolumeUnit]struct {
+		symbol  string
+		convert func(float32) float32
+	}{
+		Litres:          {"L", same},
+		USGallons:       {"gal", utils.LitresToUSGallons},
+		ImperialGallons: {"imp gal", utils.LitresToImperialGallons},
+	}
+	powerUnits = map[PowerUnit]struct {
+		symbol  string
+		convert func(float32) float32
+	}{
+		Kilowatts:        {"kW", same},
+		Horsepower:       {"hp", utils.KilowattsToHorsepower},
+		MetricHorsepower: {"PS", utils.KilowattsToMetricHorsepower},
+	}
+	torqueUnits = map[TorqueUnit]struct {
+		symbol  string
+		convert func(float32) float32
+	}{
+		NewtonMeters:        {"Nm", same},
+		PoundFeet:           {"lb-ft", utils.NewtonMetersToPoundFeet},
+		KilogramForceMeters: {"kgf-m", utils.NewtonMetersToKilogramForceMeters},
+	}
+	massUnits = map[MassUnit]struct {
+		symbol  string
+		convert func(float32) float32
+	}{
+		Kilograms: {"kg", same},
+		Pounds:    {"lb", utils.KilogramsToPounds},
+	}
+)
+
+func same(v float32) float32 {
+	return v
+}
+
+func (u SpeedUnit) String() string {
+	return speedUnits[u].symbol
+}
+
+func (u PressureUnit) String() string {
+	return pressureUnits[u].symbol
+}
+
+func (u TemperatureUnit) String() string {
+	switch u {
+	case Fahrenheit:
+		return "F"
+	case Kelvin:
+		return "K"
+	default:
+		return "C"
+	}
+}
+
+func (u LengthUnit) String() string {
+	return lengthUnits[u].symbol
+}
+
+func (u VolumeUnit) String() string {
+	return volumeUnits[u].symbol
+}
+
+func (u PowerUnit) String() string {
+	return powerUnits[u].symbol
+}
+
+func (u TorqueUnit) String() string {
+	return torqueUnits[u].symbol
+}
+
+func (u MassUnit) String() string {
+	return massUnits[u].symbol
+}
+
+// In returns the speed in the given unit.
+func (s Speed) In(unit SpeedUnit) float32 {
+	return speedUnits[unit].convert(float32(s))
+}
+
+// Format returns the speed in the given unit with its symbol.
+func (s Speed) Format(unit SpeedUnit, precision int) string {
+	return formatQuantity(s.In(unit), precision, unit.String())
+}
+
+// In returns the pressure in the given unit.
+func (p Pressure) In(unit PressureUnit) float32 {
+	return pressureUnits[unit].convert(float32(p))
+}
+
+// Format returns the pressure in the given unit with its symbol.
+func (p Pressure) Format(unit PressureUnit, precision int) string {
+	return formatQuantity(p.In(unit), precision, unit.String())
+}
+
+// In returns the temperature in the given unit.
+func (t Temperature) In(unit TemperatureUnit) float32 {
+	switch unit {
+	case Fahrenheit:
+		return utils.CelsiusToFahrenheit(float32(t))
+	case Kelvin:
+		return utils.CelsiusToKelvin(float32(t))
+	default:
+		return float32(t)
+	}
+}
+
+// Format returns the temperature in the given unit with its symbol.
+func (t Temperature) Format(unit TemperatureUnit, precision int) string {
+	symbol := unit.String()
+	if unit != Kelvin {
+		symbol = "°" + symbol
+	}
+
+	return formatQuantity(t.In(unit), precision, symbol)
+}
+
+// In returns the length in the given unit.
+func (l Length) In(unit LengthUnit) float32 {
+	return lengthUnits[unit].convert(float32(l))
+}
+
+// Format returns the length in the given unit with its symbol.
+func (l Length) Format(unit LengthUnit, precision int) string {
+	return formatQuantity(l.In(unit), precision, unit.String())
+}
+
+// In returns the volume in the given unit.
+func (v Volume) In(unit VolumeUnit) float32 {
+	return volumeUnits[unit].convert(float32(v))
+}
+
+// Format returns the volume in the given unit with its symbol.
+func (v Volume) Format(unit VolumeUnit, precision int) string {
+	return formatQuantity(v.In(unit), precision, unit.String())
+}
+
+// In returns the power in the given unit.
+func (p Power) In(unit PowerUnit) float32 {
+	return powerUnits[unit].convert(float32(p))
+}
+
+// Format returns the power in the given unit with its symbol.
+func (p Power) Format(unit PowerUnit, precision int) string {
+	return formatQuantity(p.In(unit), precision, unit.String())
+}
+
+// In returns the torque in the given unit.
+func (t Torque) In(unit TorqueUnit) float32 {
+	return torqueUnits[unit].convert(float32(t))
+}
+
+// Format returns the torque in the given unit with its symbol.
+func (t Torque) Format(unit TorqueUnit, precision int) string {
+	return formatQuantity(t.In(unit), precision, unit.String())
+}
+
+// In returns the mass in the given unit.
+func (m Mass) In(unit MassUnit) float32 {
+	return massUnits[unit].convert(float32(m))
+}
+
+// Format returns the mass in the given unit with its symbol.
+func (m Mass) Format(unit MassUnit, precision int) string {
+	return formatQuantity(m.In(unit), precision, unit.String())
+}
+
+// Speed returns the preferred speed unit of the unit system.
+func (u UnitSystem) Speed() SpeedUnit {
+	if u == UnitSystemImperial {
+		return MilesPerHour
+	}
+
+	return KilometersPerHour
+}
+
+// Pressure returns the preferred pressure unit of the unit system.
+func (u UnitSystem) Pressure() PressureUnit {
+	if u == UnitSystemImperial {
+		return PoundsPerSquareInch
+	}
+
+	return Bar
+}
+
+// Temperature returns the preferred temperature unit of the unit system.
+func (u UnitSystem) Temperature() TemperatureUnit {
+	if u == UnitSystemImperial {
+		return Fahrenheit
+	}
+
+	return Celsius
+}
+
+// Length returns the preferred unit of the unit system for short lengths
+// such as ride heights and tyre sizes.
+func (u UnitSystem) Length() LengthUnit {
+	if u == UnitSystemImperial {
+		return Inches
+	}
+
+	return Millimeters
+}
+
+// Volume returns the preferred volume unit of the unit system.
+func (u UnitSystem) Volume() VolumeUnit {
+	if u == UnitSystemImperial {
+		return USGallons
+	}
+
+	return Litres
+}
+
+// Power returns the preferred power unit of the unit system.
+func (u UnitSystem) Power() PowerUnit {
+	if u == UnitSystemImperial {
+		return Horsepower
+	}
+
+	return Kilowatts
+}
+
+// Torque returns the preferred torque unit of the unit system.
+func (u UnitSystem) Torque() TorqueUnit {
+	if u == UnitSystemImperial {
+		return PoundFeet
+	}
+
+	return NewtonMeters
+}
+
+// Mass returns the preferred mass unit of the unit system.
+func (u UnitSystem) Mass() MassUnit {
+	if u == UnitSystemImperial {
+		return Pounds
+	}
+
+	return Kilograms
+}
+
+func formatQuantity(value float32, precision int, symbol string) string {
+	return strconv.FormatFloat(float64(value), 'f', precision, 32) + " " + symbol
+}
+
+func cornersOf[Q ~float32](set CornerSet) Corners[Q] {
+	return Corners[Q]{
+		FrontLeft:  Q(set.FrontLeft),
+		FrontRight: Q(set.FrontRight),
+		RearLeft:   Q(set.RearLeft),
+		RearRight:  Q(set.RearRight),
+	}
+}
@@ -0,0 +1,128 @@
+package telemetry
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/vwhitteron/gt-telemetry/internal/gttelemetry"
+	"github.com/vwhitteron/gt-telemetry/vehicles"
+)
+
+type UnitsTestSuite struct {
+	suite.Suite
+}
+
+func TestUnitsTestSuite(t *testing.T) {
+	suite.Run(t, new(UnitsTestSuite))
+}
+
+func (suite *UnitsTestSuite) TestQuantitiesConvertToEachUnit() {
+	testCases := map[string]struct {
+		got  float32
+		want float32
+	}{
+		"m/s":     {Speed(25).In(MetersPerSecond), 25},
+		"km/h":    {Speed(25).In(KilometersPerHour), 90},
+		"mph":     {Speed(25).In(MilesPerHour), 55.9234},
+		"bar":     {Pressure(1.5).In(Bar), 1.5},
+		"kPa":     {Pressure(1.5).In(KiloPascals), 150},
+		"psi":     {Pressure(1.5).In(PoundsPerSquareInch), 21.7557},
+		"inHg":    {Pressure(1.5).In(InchesOfMercury), 44.2950},
+		"C":       {Temperature(90).In(Celsius), 90},
+		"F":       {Temperature(90).In(Fahrenheit), 194},
+		"K":       {Temperature(90).In(Kelvin), 363.15},
+		"m":       {Length(0.1).In(Meters), 0.1},
+		"mm":      {Length(0.1).In(Millimeters), 100},
+		"in":      {Length(0.1).In(Inches), 3.93701},
+		"ft":      {Length(0.1).In(Feet), 0.328084},
+		"L":       {Volume(50).In(Litres), 50},
+		"gal":     {Volume(50).In(USGallons), 13.2086},
+		"imp gal": {Volume(50).In(ImperialGallons), 10.9985},
+		"kW":      {Power(200).In(Kilowatts), 200},
+		"hp":      {Power(200).In(Horsepower), 268.204},
+		"PS":      {Power(200).In(MetricHorsepower), 271.924},
+		"Nm":      {Torque(400).In(NewtonMeters), 400},
+		"lb-ft":   {Torque(400).In(PoundFeet), 295.025},
+		"kgf-m":   {Torque(400).In(KilogramForceMeters), 40.789},
+		"kg":      {Mass(1500).In(Kilograms), 1500},
+		"lb":      {Mass(1500).In(Pounds), 3306.934},
+	}
+
+	for name, tc := range testCases {
+		suite.Run(name, func() {
+			// Assert
+			suite.InDelta(tc.want, tc.got, 0.001)
+		})
+	}
+}
+
+func (suite *UnitsTestSuite) TestQuantitiesFormatWithTheUnitSymbol() {
+	// Assert
+	suite.Equal("90 km/h", Speed(25).Format(KilometersPerHour, 0))
+	suite.Equal("21.76 psi", Pressure(1.5).Format(PoundsPerSquareInch, 2))
+	suite.Equal("194.0 °F", Temperature(90).Format(Fahrenheit, 1))
+	suite.Equal("363 K", Temperature(90).Format(Kelvin, 0))
+	suite.Equal("3.9 in", Length(0.1).Format(Inches, 1))
+	suite.Equal("13.2 gal", Volume(50).Format(USGallons, 1))
+}
+
+func (suite *UnitsTestSuite) TestUnitSystemsPreferTheirUnits() {
+	// Assert
+	suite.Equal(KilometersPerHour, UnitSystemMetric.Speed())
+	suite.Equal(MilesPerHour, UnitSystemImperial.Speed())
+	suite.Equal(Bar, UnitSystemMetric.Pressure())
+	suite.Equal(PoundsPerSquareInch, UnitSystemImperial.Pressure())
+	suite.Equal(Celsius, UnitSystemMetric.Temperature())
+	suite.Equal(Fahrenheit, UnitSystemImperial.Temperature())
+	suite.Equal(Millimeters, UnitSystemMetric.Length())
+	suite.Equal(Inches, UnitSystemImperial.Length())
+	suite.Equal(Litres, UnitSystemMetric.Volume())
+	suite.Equal(USGallons, UnitSystemImperial.Volume())
+	suite.Equal(Kilowatts, UnitSystemMetric.Power())
+	suite.Equal(Horsepower, UnitSystemImperial.Power())
+	suite.Equal(NewtonMeters, UnitSystemMetric.Torque())
+	suite.Equal(PoundFeet, UnitSystemImperial.Torque())
+	suite.Equal(Kilograms, UnitSystemMetric.Mass())
+	suite.Equal(Pounds, UnitSystemImperial.Mass())
+}
+
+func (suite *UnitsTestSuite) TestClientUnitsAreCarriedByFrames() {
+	// Arrange
+	client, err := NewGTClient(GTClientOpts{LogLevel: "off", Units: UnitSystemImperial})
+	suite.Require().NoError(err)
+	client.Telemetry.RawTelemetry.TyreTemperature = &gttelemetry.GranTurismoTelemetry_CornerSet{FrontLeft: 80}
+
+	// Act
+	frame := NewFrame(client.Telemetry, time.Now())
+
+	// Assert
+	suite.Equal(UnitSystemImperial, client.Units())
+	suite.Equal(UnitSystemImperial, frame.Units())
+	suite.Equal("176 °F", frame.TyreTemperature().FrontLeft.Format(frame.Units().Temperature(), 0))
+}
+
+func (suite *UnitsTestSuite) TestImperialChannelsUseImperialUnits() {
+	// Arrange
+	file := filepath.Join(suite.T().TempDir(), "tank.json")
+	suite.Require().NoError(os.WriteFile(file, []byte(`{"82": {"FuelTankLitres": 60}}`), 0o644))
+	inventory, err := vehicles.NewInventory(file)
+	suite.Require().NoError(err)
+	transformer := NewTransformer(inventory)
+	transformer.RawTelemetry.VehicleId = 82
+	transformer.RawTelemetry.OilPressure = 400
+	transformer.RawTelemetry.FuelLevel = 50
+	frame := NewFrame(transformer, time.Now())
+
+	// Act
+	channels, err := LookupChannels(UnitSystemImperial, []string{"oil_pressure", "fuel_volume"})
+	suite.Require().NoError(err)
+
+	// Assert
+	suite.Equal("psi", channels[0].Unit)
+	suite.InDelta(58.015, channels[0].Value(frame).(float32), 0.001)
+	suite.Equal("gal", channels[1].Unit)
+	suite.InDelta(7.9252, channels[1].Value(frame).(float32), 0.001)
+}
@@ -0,0 +1,134 @@
+package telemetry
+
+import (
+	"slices"
+	"time"
+
+	"github.com/vwhitteron/gt-telemetry/vehicles"
+)
+
+// packets under power needed to infer the driven axle, 5 seconds at 60Hz
+const profileMinSamples = 300
+
+// profileLearner builds a profile of a vehicle that is not in the inventory
+// from its telemetry. The driven axle is the one whose wheels turn faster
+// than the ground speed while accelerating.
+type profileLearner struct {
+	store     *vehicles.ProfileStore
+	profile   vehicles.Profile
+	active    bool
+	changed   bool
+	frontSlip float64
+	rearSlip  float64
+	samples   int
+}
+
+func newProfileLearner(store *vehicles.ProfileStore) *profileLearner {
+	return &profileLearner{store: store}
+}
+
+// start begins learning a vehicle, continuing from its stored profile.
+func (l *profileLearner) start(id int) {
+	profile, ok := l.store.Get(id)
+	if !ok {
+		profile = vehicles.Profile{ID: id}
+	}
+
+	l.profile = profile
+	l.active = true
+	l.changed = false
+	l.frontSlip, l.rearSlip, l.samples = 0, 0, 0
+}
+
+func (l *profileLearner) stop() {
+	l.active = false
+}
+
+// observe learns from a packet and stores the profile when something new
+// about the vehicle has been found.
+func (l *profileLearner) observe(t *transformer) error {
+	if !l.active {
+		return nil
+	}
+
+	flags := t.Flags()
+	if flags.GamePaused || flags.Loading {
+		return nil
+	}
+
+	transmission := t.Transmission()
+	if transmission.Gears > 0 && (transmission.Gears != l.profile.Gears || !slices.Equal(transmission.GearRatios, l.profile.GearRatios)) {
+		l.profile.Gears = transmission.Gears
+		l.profile.GearRatios = slices.Clone(transmission.GearRatios)
+		l.changed = true
+	}
+
+	if flags.HasTurbo && !l.profile.HasTurbo {
+		l.profile.HasTurbo = true
+		l.changed = true
+	}
+
+	radius := t.TyreRadiusMeters()
+	front := (radius.FrontLeft + radius.FrontRight) / 2 * 1000
+	rear := (radius.RearLeft + radius.RearRight) / 2 * 1000
+	if front > 0 && rear > 0 && (front != l.profile.TyreRadiusFrontMM || rear != l.profile.TyreRadiusRearMM) {
+		l.profile.TyreRadiusFrontMM = front
+		l.profile.TyreRadiusRearMM = rear
+		l.changed = true
+	}
+
+	l.observeDrivenAxle(t)
+
+	if !l.changed {
+		return nil
+	}
+
+	return l.save()
+}
+
+func (l *profileLearner) observeDrivenAxle(t *transformer) {
+	if l.profile.DrivenAxle != "" {
+		return
+	}
+
+	front, rear, ok := powerSlip(t)
+	if !ok {
+		return
+	}
+
+	l.frontSlip += float64(front)
+	l.rearSlip += float64(rear)
+	l.samples++
+	if l.samples < profileMinSamples {
+		return
+	}
+
+	drivetrain := Drivetrain{}
+	if !slipAxles(l.frontSlip/float64(l.samples), l.rearSlip/float64(l.samples), &drivetrain) {
+		// the wheels barely slipped, try again with fresh samples
+		l.frontSlip, l.rearSlip, l.samples = 0, 0, 0
+
+		return
+	}
+
+	switch {
+	case drivetrain.FrontAxle && drivetrain.RearAxle:
+		l.profile.DrivenAxle = vehicles.BothAxles
+	case drivetrain.FrontAxle:
+		l.profile.DrivenAxle = vehicles.FrontAxle
+	default:
+		l.profile.DrivenAxle = vehicles.RearAxle
+	}
+	l.profile.Samples = l.samples
+	l.changed = true
+}
+
+func (l *profileLearner) save() error {
+	if !l.changed {
+		return nil
+	}
+	l.changed = false
+	l.profile.Updated = time.Now()
+
+	return l.store.Put(l.profile)
+}
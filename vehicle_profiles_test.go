@@ -0,0 +1,182 @@
+package telemetry
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/vwhitteron/gt-telemetry/vehicles"
+)
+
+type VehicleProfilesTestSuite struct {
+	suite.Suite
+}
+
+func TestVehicleProfilesTestSuite(t *testing.T) {
+	suite.Run(t, new(VehicleProfilesTestSuite))
+}
+
+// accelerating sets up a packet of a vehicle under full throttle at 20 m/s
+// with the wheels of the driven axles slipping by 5%.
+func accelerating(frame *Frame, id uint32, front bool, rear bool) {
+	const groundSpeed, radius = 20, 0.3
+
+	raw := &frame.RawTelemetry
+	raw.VehicleId = id
+	raw.GroundSpeed = groundSpeed
+	raw.Throttle = 255
+	raw.Flags.InGear = true
+	raw.Flags.HasTurbo = true
+	raw.TransmissionGear.Current = 3
+	copy(raw.TransmissionGearRatio.Gear, []float32{3.2, 2.1, 1.5, 1.2, 1.0, 0.8})
+	raw.TyreRadius.FrontLeft, raw.TyreRadius.FrontRight = radius, radius
+	raw.TyreRadius.RearLeft, raw.TyreRadius.RearRight = radius, radius
+
+	wheel := func(driven bool) float32 {
+		if driven {
+			return groundSpeed * 1.05 / radius
+		}
+		return groundSpeed / radius
+	}
+	raw.WheelRadiansPerSecond.FrontLeft = wheel(front)
+	raw.WheelRadiansPerSecond.FrontRight = wheel(front)
+	raw.WheelRadiansPerSecond.RearLeft = wheel(rear)
+	raw.WheelRadiansPerSecond.RearRight = wheel(rear)
+}
+
+func (suite *VehicleProfilesTestSuite) TestUnknownVehiclesAreReportedWhenTheyAreDriven() {
+	// Arrange
+	ids := []uint32{99999, 99999, 82, 82, 99999, 0}
+	source := NewFuncSource(func(i int, frame *Frame) bool {
+		if i >= len(ids) {
+			return false
+		}
+		frame.RawTelemetry.VehicleId = ids[i]
+
+		return true
+	})
+	client, err := NewGTClient(GTClientOpts{SourceReader: source, LogLevel: "off"})
+	suite.Require().NoError(err)
+	events := client.Events(8)
+
+	// Act
+	client.Run()
+
+	// Assert
+	received := []StreamEvent{}
+	for event := range events {
+		received = append(received, event)
+	}
+	suite.Require().Len(received, 2)
+	for _, event := range received {
+		suite.Equal(UnknownVehicle, event.Type)
+		suite.Equal(uint32(99999), event.VehicleID)
+		suite.Equal("unknown vehicle", event.Type.String())
+	}
+}
+
+func (suite *VehicleProfilesTestSuite) TestProfilesAreLearnedFromTelemetry() {
+	testCases := map[string]struct {
+		front      bool
+		rear       bool
+		drivenAxle string
+		drivetrain string
+	}{
+		"front wheel drive": {front: true, drivenAxle: vehicles.FrontAxle, drivetrain: "FF"},
+		"rear wheel drive":  {rear: true, drivenAxle: vehicles.RearAxle, drivetrain: "FR"},
+		"all wheel drive":   {front: true, rear: true, drivenAxle: vehicles.BothAxles, drivetrain: "4WD"},
+	}
+
+	for name, tc := range testCases {
+		suite.Run(name, func() {
+			// Arrange
+			file := filepath.Join(suite.T().TempDir(), "profiles.json")
+			source := NewFuncSource(func(i int, frame *Frame) bool {
+				accelerating(frame, 99999, tc.front, tc.rear)
+
+				return i < profileMinSamples+10
+			})
+			client, err := NewGTClient(GTClientOpts{SourceReader: source, LogLevel: "off", VehicleProfiles: file})
+			suite.Require().NoError(err)
+			frames := client.Subscribe(profileMinSamples + 20)
+
+			// Act
+			client.Run()
+
+			// Assert
+			store, err := vehicles.OpenProfileStore(file)
+			suite.Require().NoError(err)
+			profile, ok := store.Get(99999)
+			suite.Require().True(ok)
+			suite.Equal(99999, profile.ID)
+			suite.Equal(tc.drivenAxle, profile.DrivenAxle)
+			suite.Equal(profileMinSamples, profile.Samples)
+			suite.Equal(6, profile.Gears)
+			suite.Equal(float32(3.2), profile.GearRatios[0])
+			suite.True(profile.HasTurbo)
+			suite.InDelta(300, profile.TyreRadiusFrontMM, 0.01)
+			suite.InDelta(300, profile.TyreRadiusRearMM, 0.01)
+
+			var last Frame
+			for frame := range frames {
+				last = frame
+			}
+			_, known := last.CurrentVehicle()
+			suite.False(known)
+			suite.Equal(tc.drivetrain, last.VehicleDrivetrain())
+			suite.Equal("TC", last.VehicleAspiration())
+		})
+	}
+}
+
+func (suite *VehicleProfilesTestSuite) TestDrivenAxleIsNotLearnedWhenCoasting() {
+	// Arrange
+	source := NewFuncSource(func(i int, frame *Frame) bool {
+		accelerating(frame, 99999, false, true)
+		frame.RawTelemetry.Throttle = 0
+
+		return i < profileMinSamples+10
+	})
+	client, err := NewGTClient(GTClientOpts{SourceReader: source, LogLevel: "off"})
+	suite.Require().NoError(err)
+
+	// Act
+	client.Run()
+
+	// Assert
+	profile, ok := client.Profiles().Get(99999)
+	suite.Require().True(ok)
+	suite.Empty(profile.DrivenAxle)
+	suite.Equal(6, profile.Gears)
+}
+
+func (suite *VehicleProfilesTestSuite) TestLearnedProfilesAreNotUsedForKnownVehicles() {
+	// Arrange
+	inventory, err := vehicles.NewInventory()
+	suite.Require().NoError(err)
+	store, err := vehicles.OpenProfileStore("")
+	suite.Require().NoError(err)
+	suite.Require().NoError(store.Put(vehicles.Profile{ID: 82, DrivenAxle: vehicles.FrontAxle}))
+	suite.Require().NoError(store.Put(vehicles.Profile{ID: 99999, DrivenAxle: vehicles.FrontAxle}))
+
+	transformer := NewTransformer(inventory)
+	transformer.profiles = store
+	transformer.RawTelemetry = newRawTelemetry()
+
+	// Act
+	transformer.RawTelemetry.VehicleId = 82
+	knownDrivetrain := transformer.VehicleDrivetrain()
+	_, knownProfile := transformer.VehicleProfile()
+
+	transformer.RawTelemetry.VehicleId = 99999
+	unknownDrivetrain := transformer.VehicleDrivetrain()
+	profile, unknownProfile := transformer.VehicleProfile()
+
+	// Assert
+	suite.Equal("FR", knownDrivetrain)
+	suite.False(knownProfile)
+	suite.Equal("FF", unknownDrivetrain)
+	suite.True(unknownProfile)
+	suite.Equal(99999, profile.ID)
+}
@@ -0,0 +1,275 @@
+package vehicles
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// field describes a column of the CSV format, named after the JSON field of
+// the vehicle.
+type field struct {
+	name     string
+	index    int
+	optional bool
+}
+
+var fields = vehicleFields()
+
+func vehicleFields() []field {
+	vehicleType := reflect.TypeOf(Vehicle{})
+	fields := make([]field, 0, vehicleType.NumField())
+
+	for i := 0; i < vehicleType.NumField(); i++ {
+		structField := vehicleType.Field(i)
+		name, options, _ := strings.Cut(structField.Tag.Get("json"), ",")
+		if name == "" {
+			name = structField.Name
+		}
+
+		fields = append(fields, field{
+			name:     name,
+			index:    i,
+			optional: options == "omitempty",
+		})
+	}
+
+	return fields
+}
+
+func lookupField(name string) (field, bool) {
+	for _, f := range fields {
+		if strings.EqualFold(f.name, name) {
+			return f, true
+		}
+	}
+
+	return field{}, false
+}
+
+func (f field) get(vehicle *Vehicle) string {
+	value := reflect.ValueOf(vehicle).Elem().Field(f.index)
+	if f.optional && value.IsZero() {
+		return ""
+	}
+
+	switch value.Kind() {
+	case reflect.Float32:
+		return strconv.FormatFloat(value.Float(), 'g', -1, 32)
+	default:
+		return fmt.Sprint(value.Interface())
+	}
+}
+
+func (f field) set(vehicle *Vehicle, text string) error {
+	value := reflect.ValueOf(vehicle).Elem().Field(f.index)
+
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(text)
+	case reflect.Int:
+		n, err := strconv.Atoi(text)
+		if err != nil {
+			return fmt.Errorf("%s %q is not a whole number", f.name, text)
+		}
+		value.SetInt(int64(n))
+	case reflect.Float32:
+		n, err := strconv.ParseFloat(text, 32)
+		if err != nil {
+			return fmt.Errorf("%s %q is not a number", f.name, text)
+		}
+		value.SetFloat(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(text)
+		if err != nil {
+			return fmt.Errorf("%s %q is not true or false", f.name, text)
+		}
+		value.SetBool(b)
+	}
+
+	return nil
+}
+
+// Update is a partial vehicle record read from a CSV file. Only the fields
+// with a value in the file are merged into the inventory.
+type Update struct {
+	ID     int
+	Line   int
+	values map[string]string
+}
+
+// Conflict is a field of an existing vehicle that an update sets to a
+// different value.
+type Conflict struct {
+	ID       int
+	Field    string
+	Current  string
+	Imported string
+}
+
+func (c Conflict) String() string {
+	return fmt.Sprintf("vehicle %d: %s is %q, import has %q", c.ID, c.Field, c.Current, c.Imported)
+}
+
+// ReadCSV reads vehicle updates from a CSV file with a header row naming the
+// columns. The CarID column is required and the other columns use the field
+// names of the JSON inventory in any order, empty cells are left unchanged.
+func ReadCSV(r io.Reader) ([]Update, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	columns := make([]field, len(header))
+	idColumn := -1
+	for i, name := range header {
+		f, ok := lookupField(strings.TrimSpace(name))
+		if !ok {
+			return nil, fmt.Errorf("unknown CSV column %q", name)
+		}
+		columns[i] = f
+		if f.name == "CarID" {
+			idColumn = i
+		}
+	}
+	if idColumn < 0 {
+		return nil, errors.New("CSV has no CarID column")
+	}
+
+	updates := []Update{}
+	errs := []error{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		id, err := strconv.Atoi(strings.TrimSpace(record[idColumn]))
+		if err != nil || id < 0 {
+			errs = append(errs, fmt.Errorf("line %d: CarID %q is not a vehicle ID", line, record[idColumn]))
+			continue
+		}
+
+		update := Update{ID: id, Line: line, values: map[string]string{}}
+		for i, value := range record {
+			value = strings.TrimSpace(value)
+			if i == idColumn || value == "" {
+				continue
+			}
+
+			if err := columns[i].set(&Vehicle{}, value); err != nil {
+				errs = append(errs, fmt.Errorf("line %d: %w", line, err))
+				continue
+			}
+			update.values[columns[i].name] = value
+		}
+		updates = append(updates, update)
+	}
+
+	return updates, errors.Join(errs...)
+}
+
+// Merge applies the updates to the vehicles and returns the merged vehicles
+// ordered by ID. A field that already has a different value is reported as a
+// conflict and only replaced when overwrite is set.
+func Merge(vehicles []Vehicle, updates []Update, overwrite bool) ([]Vehicle, []Conflict, error) {
+	merged := map[int]Vehicle{}
+	for _, vehicle := range vehicles {
+		merged[vehicle.ID] = vehicle
+	}
+
+	conflicts := []Conflict{}
+	errs := []error{}
+	for _, update := range updates {
+		vehicle, exists := merged[update.ID]
+		vehicle.ID = update.ID
+
+		names := make([]string, 0, len(update.values))
+		for name := range update.values {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			f, _ := lookupField(name)
+			imported := update.values[name]
+			current := f.get(&vehicle)
+
+			if exists && current != "" && !sameValue(f, current, imported) {
+				conflicts = append(conflicts, Conflict{ID: update.ID, Field: name, Current: current, Imported: imported})
+				if !overwrite {
+					continue
+				}
+			}
+
+			f.set(&vehicle, imported)
+		}
+
+		if err := vehicle.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("line %d: vehicle %d: %w", update.Line, update.ID, err))
+			continue
+		}
+		merged[update.ID] = vehicle
+	}
+
+	result := make([]Vehicle, 0, len(merged))
+	for _, vehicle := range merged {
+		result = append(result, vehicle)
+	}
+	sort.Slice(result, func(a, b int) bool {
+		return result[a].ID < result[b].ID
+	})
+
+	return result, conflicts, errors.Join(errs...)
+}
+
+// sameValue compares values by their parsed form so that "1" and "1.0" or
+// "true" and "TRUE" do not conflict.
+func sameValue(f field, a string, b string) bool {
+	var first, second Vehicle
+	if f.set(&first, a) != nil || f.set(&second, b) != nil {
+		return false
+	}
+
+	return f.get(&first) == f.get(&second)
+}
+
+// WriteCSV writes the vehicles with a header row, unknown specifications are
+// left empty.
+func WriteCSV(w io.Writer, vehicles []Vehicle) error {
+	writer := csv.NewWriter(w)
+
+	header := make([]string, len(fields))
+	for i, f := range fields {
+		header[i] = f.name
+	}
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+
+	for _, vehicle := range vehicles {
+		record := make([]string, len(fields))
+		for i, f := range fields {
+			record[i] = f.get(&vehicle)
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write CSV: %w", err)
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
@@ -0,0 +1,126 @@
+package vehicles
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type CSVTestSuite struct {
+	suite.Suite
+	current []Vehicle
+}
+
+func TestCSVTestSuite(t *testing.T) {
+	suite.Run(t, new(CSVTestSuite))
+}
+
+func (suite *CSVTestSuite) SetupTest() {
+	suite.current = []Vehicle{
+		{ID: 24, Manufacturer: "Nissan", Model: "180SX Type X '96", Year: 1996, Drivetrain: "FR", Aspiration: "TC"},
+		{ID: 82, Manufacturer: "Toyota", Model: "Supra RZ '97", Year: 1997, Drivetrain: "FR", Aspiration: "TC"},
+	}
+}
+
+func (suite *CSVTestSuite) TestReadCSVKeepsOnlyTheCellsWithValues() {
+	// Arrange
+	input := "CarID, model, Drivetrain, PowerKW\n82,, 4WD, 206\n99001,Prototype,MR,\n"
+
+	// Act
+	updates, err := ReadCSV(strings.NewReader(input))
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Require().Len(updates, 2)
+	suite.Equal(82, updates[0].ID)
+	suite.Equal(2, updates[0].Line)
+	suite.Equal(map[string]string{"Drivetrain": "4WD", "PowerKW": "206"}, updates[0].values)
+	suite.Equal(map[string]string{"Model": "Prototype", "Drivetrain": "MR"}, updates[1].values)
+}
+
+func (suite *CSVTestSuite) TestReadCSVReportsInvalidRows() {
+	testCases := map[string]struct {
+		input string
+		want  string
+	}{
+		"unknown column": {"CarID,Colour\n82,red\n", `unknown CSV column "Colour"`},
+		"missing ID":     {"Model\nSupra\n", "CSV has no CarID column"},
+		"invalid ID":     {"CarID,Model\nabc,Supra\n", `line 2: CarID "abc" is not a vehicle ID`},
+		"invalid number": {"CarID,Year\n82,new\n", `line 2: Year "new" is not a whole number`},
+		"invalid bool":   {"CarID,OpenCockpit\n82,maybe\n", `line 2: OpenCockpit "maybe" is not true or false`},
+	}
+
+	for name, tc := range testCases {
+		suite.Run(name, func() {
+			// Act
+			_, err := ReadCSV(strings.NewReader(tc.input))
+
+			// Assert
+			suite.Require().Error(err)
+			suite.Contains(err.Error(), tc.want)
+		})
+	}
+}
+
+func (suite *CSVTestSuite) TestMergeAddsVehiclesAndReportsConflicts() {
+	// Arrange
+	updates, err := ReadCSV(strings.NewReader("CarID,Manufacturer,Model,Year,Drivetrain,PowerKW\n82,,,1997,4WD,206\n99001,Custom,Prototype,2025,MR,\n"))
+	suite.Require().NoError(err)
+
+	// Act
+	merged, conflicts, err := Merge(suite.current, updates, false)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Require().Len(merged, 3)
+	suite.Equal([]Conflict{{ID: 82, Field: "Drivetrain", Current: "FR", Imported: "4WD"}}, conflicts)
+	suite.Equal("FR", merged[1].Drivetrain)
+	suite.Equal(float32(206), merged[1].PowerKW)
+	suite.Equal(Vehicle{ID: 99001, Manufacturer: "Custom", Model: "Prototype", Year: 2025, Drivetrain: "MR"}, merged[2])
+}
+
+func (suite *CSVTestSuite) TestMergeWithOverwriteReplacesConflicts() {
+	// Arrange
+	updates, err := ReadCSV(strings.NewReader("CarID,Drivetrain\n82,4WD\n"))
+	suite.Require().NoError(err)
+
+	// Act
+	merged, conflicts, err := Merge(suite.current, updates, true)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Len(conflicts, 1)
+	suite.Equal("4WD", merged[1].Drivetrain)
+}
+
+func (suite *CSVTestSuite) TestMergeRejectsIncompleteNewVehicles() {
+	// Arrange
+	updates, err := ReadCSV(strings.NewReader("CarID,Model\n99001,Prototype\n"))
+	suite.Require().NoError(err)
+
+	// Act
+	merged, _, err := Merge(suite.current, updates, false)
+
+	// Assert
+	suite.EqualError(err, "line 2: vehicle 99001: manufacturer is missing")
+	suite.Len(merged, 2)
+}
+
+func (suite *CSVTestSuite) TestWrittenCSVCanBeImportedWithoutConflicts() {
+	// Arrange
+	suite.current[1].PowerKW = 206
+	var out bytes.Buffer
+	suite.Require().NoError(WriteCSV(&out, suite.current))
+
+	// Act
+	updates, err := ReadCSV(&out)
+	suite.Require().NoError(err)
+	merged, conflicts, err := Merge(suite.current, updates, false)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Empty(conflicts)
+	suite.Equal(suite.current, merged)
+}
@@ -0,0 +1,101 @@
+package vehicles
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// WriteJSON writes the vehicles in the inventory file format, keyed by ID in
+// numeric order.
+func WriteJSON(w io.Writer, vehicles []Vehicle) error {
+	sorted := append([]Vehicle{}, vehicles...)
+	sort.Slice(sorted, func(a, b int) bool {
+		return sorted[a].ID < sorted[b].ID
+	})
+
+	if _, err := io.WriteString(w, "{\n"); err != nil {
+		return fmt.Errorf("failed to write JSON: %w", err)
+	}
+
+	for i, vehicle := range sorted {
+		entry, err := json.MarshalIndent(vehicle, "  ", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal vehicle %d: %w", vehicle.ID, err)
+		}
+
+		separator := ",\n"
+		if i == len(sorted)-1 {
+			separator = "\n"
+		}
+
+		if _, err := fmt.Fprintf(w, "  %q: %s%s", strconv.Itoa(vehicle.ID), entry, separator); err != nil {
+			return fmt.Errorf("failed to write JSON: %w", err)
+		}
+	}
+
+	if _, err := io.WriteString(w, "}\n"); err != nil {
+		return fmt.Errorf("failed to write JSON: %w", err)
+	}
+
+	return nil
+}
+
+// Check reports every entry of an inventory file whose key does not match
+// its CarID, or that would fail to load.
+func Check(name string, data []byte) error {
+	entries := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return fmt.Errorf("failed to unmarshal vehicle inventory %s: %w", name, err)
+	}
+
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	errs := []error{}
+	for _, key := range keys {
+		var carID struct {
+			CarID *int
+		}
+		if err := json.Unmarshal(entries[key], &carID); err == nil && carID.CarID == nil {
+			errs = append(errs, fmt.Errorf("%s: vehicle %s: CarID is missing", name, key))
+			continue
+		}
+
+		if _, err := mergeVehicle(Vehicle{}, key, entries[key]); err != nil {
+			errs = append(errs, fmt.Errorf("%s: vehicle %s: %w", name, key, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// ReadJSON reads the vehicles from an inventory file without merging it over
+// the embedded inventory.
+func ReadJSON(r io.Reader) ([]Vehicle, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vehicle inventory: %w", err)
+	}
+
+	db := map[string]Vehicle{}
+	if err := mergeLayer(db, "inventory", data); err != nil {
+		return nil, err
+	}
+
+	vehicles := make([]Vehicle, 0, len(db))
+	for _, vehicle := range db {
+		vehicles = append(vehicles, vehicle)
+	}
+	sort.Slice(vehicles, func(a, b int) bool {
+		return vehicles[a].ID < vehicles[b].ID
+	})
+
+	return vehicles, nil
+}
@@ -0,0 +1,56 @@
+package vehicles
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type JSONTestSuite struct {
+	suite.Suite
+}
+
+func TestJSONTestSuite(t *testing.T) {
+	suite.Run(t, new(JSONTestSuite))
+}
+
+func (suite *JSONTestSuite) TestEmbeddedInventoryIsConsistent() {
+	// Act
+	err := Check(baseInventoryName, baseInventoryJSON)
+
+	// Assert
+	suite.NoError(err)
+}
+
+func (suite *JSONTestSuite) TestCheckReportsKeysThatDoNotMatchTheCarID() {
+	// Arrange
+	data := []byte(`{
+		"24": {"CarID": 24, "Manufacturer": "Nissan", "Model": "180SX"},
+		"30": {"CarID": 31, "Manufacturer": "Chevrolet", "Model": "Chevelle"},
+		"36": {"Manufacturer": "Chevrolet", "Model": "Chevelle Coupé"}
+	}`)
+
+	// Act
+	err := Check("inventory.json", data)
+
+	// Assert
+	suite.EqualError(err, "inventory.json: vehicle 30: CarID 31 does not match the key\ninventory.json: vehicle 36: CarID is missing")
+}
+
+func (suite *JSONTestSuite) TestWrittenJSONReadsBackInIDOrder() {
+	// Arrange
+	inventory, err := NewInventory()
+	suite.Require().NoError(err)
+	var out bytes.Buffer
+
+	// Act
+	suite.Require().NoError(WriteJSON(&out, inventory.All()))
+	data := out.Bytes()
+	vehicles, err := ReadJSON(bytes.NewReader(data))
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(inventory.All(), vehicles)
+	suite.NoError(Check("export", data))
+}
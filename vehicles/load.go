@@ -0,0 +1,186 @@
+package vehicles
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const baseInventoryName = "embedded inventory"
+
+// loadLayers builds the inventory from the embedded vehicles followed by each
+// override file.
+func loadLayers(files []string) (map[string]Vehicle, error) {
+	db := map[string]Vehicle{}
+
+	if err := mergeLayer(db, baseInventoryName, baseInventoryJSON); err != nil {
+		return nil, err
+	}
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read vehicle inventory: %w", err)
+		}
+
+		if err := mergeLayer(db, file, data); err != nil {
+			return nil, err
+		}
+	}
+
+	return db, nil
+}
+
+// mergeLayer merges the entries of an inventory file into the vehicles loaded
+// so far. Every invalid entry is reported, named by the file and vehicle ID.
+func mergeLayer(db map[string]Vehicle, name string, data []byte) error {
+	entries := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return fmt.Errorf("failed to unmarshal vehicle inventory %s: %w", name, err)
+	}
+
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	errs := []error{}
+	for _, key := range keys {
+		vehicle, err := mergeVehicle(db[key], key, entries[key])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: vehicle %s: %w", name, key, err))
+			continue
+		}
+
+		db[key] = vehicle
+	}
+
+	return errors.Join(errs...)
+}
+
+func mergeVehicle(base Vehicle, key string, entry json.RawMessage) (Vehicle, error) {
+	id, err := strconv.Atoi(key)
+	if err != nil || id < 0 {
+		return base, errors.New("key is not a vehicle ID")
+	}
+
+	vehicle := base
+	decoder := json.NewDecoder(bytes.NewReader(entry))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&vehicle); err != nil {
+		return base, fmt.Errorf("invalid entry: %w", err)
+	}
+
+	if vehicle.ID == 0 {
+		vehicle.ID = id
+	}
+	if vehicle.ID != id {
+		return base, fmt.Errorf("CarID %d does not match the key", vehicle.ID)
+	}
+
+	return vehicle, vehicle.validate()
+}
+
+func (v *Vehicle) validate() error {
+	errs := []error{}
+
+	if v.Manufacturer == "" {
+		errs = append(errs, errors.New("manufacturer is missing"))
+	}
+	if v.Model == "" {
+		errs = append(errs, errors.New("model is missing"))
+	}
+	if v.Year < 0 {
+		errs = append(errs, fmt.Errorf("year %d is negative", v.Year))
+	}
+	switch v.Powertrain {
+	case "", PowertrainElectric, PowertrainHybrid:
+	default:
+		errs = append(errs, fmt.Errorf("powertrain %q is not %s or %s", v.Powertrain, PowertrainElectric, PowertrainHybrid))
+	}
+
+	specs := []struct {
+		name  string
+		value float32
+	}{
+		{"PowerKW", v.PowerKW},
+		{"TorqueNM", v.TorqueNM},
+		{"WeightKG", v.WeightKG},
+		{"WheelbaseMM", v.WheelbaseMM},
+		{"TrackWidthFrontMM", v.TrackWidthFrontMM},
+		{"TrackWidthRearMM", v.TrackWidthRearMM},
+		{"PP", v.PP},
+		{"DisplacementCC", float32(v.DisplacementCC)},
+		{"FuelTankLitres", v.FuelTankLitres},
+	}
+	for _, spec := range specs {
+		if spec.value < 0 {
+			errs = append(errs, fmt.Errorf("%s %g is negative", spec.name, spec.value))
+		}
+	}
+	if v.WeightDistribution < 0 || v.WeightDistribution > 100 {
+		errs = append(errs, fmt.Errorf("WeightDistribution %g is not a percentage", v.WeightDistribution))
+	}
+
+	return errors.Join(errs...)
+}
+
+// Watch checks the override files for changes at every interval and reloads
+// the inventory when one has been modified. Reload failures are sent on the
+// returned channel, which is closed when the context is done.
+func (i *Inventory) Watch(ctx context.Context, interval time.Duration) <-chan error {
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			if !i.modified() {
+				continue
+			}
+
+			if err := i.Reload(); err != nil {
+				select {
+				case errs <- err:
+				default:
+				}
+			}
+		}
+	}()
+
+	return errs
+}
+
+func (i *Inventory) modified() bool {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	for _, file := range i.files {
+		info, err := os.Stat(file)
+		if err != nil {
+			continue
+		}
+
+		if !info.ModTime().Equal(i.modTimes[file]) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,171 @@
+package vehicles
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type LoadTestSuite struct {
+	suite.Suite
+	dir string
+}
+
+func TestLoadTestSuite(t *testing.T) {
+	suite.Run(t, new(LoadTestSuite))
+}
+
+func (suite *LoadTestSuite) SetupTest() {
+	suite.dir = suite.T().TempDir()
+}
+
+func (suite *LoadTestSuite) writeFile(name string, content string) string {
+	path := filepath.Join(suite.dir, name)
+	suite.Require().NoError(os.WriteFile(path, []byte(content), 0o644))
+
+	return path
+}
+
+func (suite *LoadTestSuite) TestOverridesAreMergedOverTheEmbeddedInventory() {
+	// Arrange
+	base, err := NewInventory()
+	suite.Require().NoError(err)
+	added := suite.writeFile("added.json", `{"99001": {"Manufacturer": "Custom", "Model": "Prototype '25", "Year": 2025}}`)
+	changed := suite.writeFile("changed.json", `{"82": {"Drivetrain": "4WD"}, "99001": {"Aspiration": "EV"}}`)
+
+	// Act
+	inventory, err := NewInventory(added, changed)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(base.Len()+1, inventory.Len())
+
+	supra, err := inventory.GetVehicleByID(82)
+	suite.Require().NoError(err)
+	suite.Equal("4WD", supra.Drivetrain)
+	suite.Equal("Supra RZ '97", supra.Model)
+
+	prototype, err := inventory.GetVehicleByID(99001)
+	suite.Require().NoError(err)
+	suite.Equal(99001, prototype.ID)
+	suite.Equal("Prototype '25", prototype.Model)
+	suite.Equal("EV", prototype.Aspiration)
+}
+
+func (suite *LoadTestSuite) TestInvalidEntriesAreNamedInTheError() {
+	testCases := map[string]struct {
+		content string
+		want    string
+	}{
+		"missing model":    {`{"99001": {"Manufacturer": "Custom"}}`, "bad.json: vehicle 99001: model is missing"},
+		"mismatched CarID": {`{"99001": {"CarID": 99002, "Manufacturer": "Custom", "Model": "X"}}`, "bad.json: vehicle 99001: CarID 99002 does not match the key"},
+		"key not an ID":    {`{"supra": {"Manufacturer": "Toyota", "Model": "Supra"}}`, "bad.json: vehicle supra: key is not a vehicle ID"},
+		"misspelled field": {`{"82": {"Drivetrian": "4WD"}}`, `bad.json: vehicle 82: invalid entry: json: unknown field "Drivetrian"`},
+		"negative spec":    {`{"82": {"WeightKG": -1}}`, "bad.json: vehicle 82: WeightKG -1 is negative"},
+		"distribution":     {`{"82": {"WeightDistribution": 120}}`, "bad.json: vehicle 82: WeightDistribution 120 is not a percentage"},
+		"powertrain":       {`{"82": {"Powertrain": "Steam"}}`, "bad.json: vehicle 82: powertrain \"Steam\" is not EV or Hybrid"},
+		"malformed file":   {`{"82": `, "failed to unmarshal vehicle inventory"},
+	}
+
+	for name, tc := range testCases {
+		suite.Run(name, func() {
+			// Arrange
+			file := suite.writeFile("bad.json", tc.content)
+
+			// Act
+			_, err := NewInventory(file)
+
+			// Assert
+			suite.Require().Error(err)
+			suite.Contains(err.Error(), tc.want)
+		})
+	}
+}
+
+func (suite *LoadTestSuite) TestSpecificationsAreOptional() {
+	// Arrange
+	file := suite.writeFile("specs.json", `{
+		"82": {"PowerKW": 206, "TorqueNM": 451, "WeightKG": 1510, "WeightDistribution": 53, "EngineLayout": "I6", "DisplacementCC": 2997},
+		"24": {"PowerKW": null, "PP": 0}
+	}`)
+
+	// Act
+	inventory, err := NewInventory(file)
+
+	// Assert
+	suite.Require().NoError(err)
+	supra, err := inventory.GetVehicleByID(82)
+	suite.Require().NoError(err)
+	suite.Equal(float32(206), supra.PowerKW)
+	suite.Equal(float32(53), supra.WeightDistribution)
+	suite.Equal("I6", supra.EngineLayout)
+	suite.Equal(2997, supra.DisplacementCC)
+	suite.Zero(supra.WheelbaseMM)
+
+	silvia, err := inventory.GetVehicleByID(24)
+	suite.Require().NoError(err)
+	suite.Zero(silvia.PowerKW)
+	suite.Equal("Nissan", silvia.Manufacturer)
+}
+
+func (suite *LoadTestSuite) TestMissingOverrideFileFails() {
+	// Act
+	_, err := NewInventory(filepath.Join(suite.dir, "missing.json"))
+
+	// Assert
+	suite.ErrorIs(err, os.ErrNotExist)
+}
+
+func (suite *LoadTestSuite) TestFailedReloadKeepsTheCurrentVehicles() {
+	// Arrange
+	file := suite.writeFile("override.json", `{"82": {"Drivetrain": "4WD"}}`)
+	inventory, err := NewInventory(file)
+	suite.Require().NoError(err)
+	version := inventory.Version()
+	suite.writeFile("override.json", `{"82": {"Model": ""}}`)
+
+	// Act
+	err = inventory.Reload()
+
+	// Assert
+	suite.Error(err)
+	suite.Equal(version, inventory.Version())
+	supra, err := inventory.GetVehicleByID(82)
+	suite.Require().NoError(err)
+	suite.Equal("4WD", supra.Drivetrain)
+}
+
+func (suite *LoadTestSuite) TestWatchReloadsModifiedFiles() {
+	// Arrange
+	file := suite.writeFile("override.json", `{"82": {"Drivetrain": "4WD"}}`)
+	inventory, err := NewInventory(file)
+	suite.Require().NoError(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errs := inventory.Watch(ctx, 10*time.Millisecond)
+
+	// Act
+	suite.writeFile("override.json", `{"82": {"Drivetrain": "MR"}}`)
+	modified := time.Now().Add(time.Minute)
+	suite.Require().NoError(os.Chtimes(file, modified, modified))
+
+	// Assert
+	suite.Eventually(func() bool {
+		supra, err := inventory.GetVehicleByID(82)
+		return err == nil && supra.Drivetrain == "MR"
+	}, time.Second, 10*time.Millisecond)
+
+	suite.writeFile("override.json", `{"82": {"Model": ""}}`)
+	modified = modified.Add(time.Minute)
+	suite.Require().NoError(os.Chtimes(file, modified, modified))
+	suite.Contains((<-errs).Error(), "model is missing")
+
+	cancel()
+	for range errs {
+	}
+}
@@ -0,0 +1,159 @@
+package vehicles
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/vwhitteron/gt-telemetry/internal/utils"
+)
+
+// Driven axles of a learned profile.
+const (
+	FrontAxle = "front"
+	RearAxle  = "rear"
+	BothAxles = "all"
+)
+
+// Profile is what has been learned about a vehicle from its telemetry. It is
+// used in place of an inventory record for vehicles the inventory does not
+// know about.
+type Profile struct {
+	ID         int `json:"CarID"`
+	Gears      int
+	GearRatios []float32
+	HasTurbo   bool
+	// DrivenAxle is empty until enough telemetry under power has been seen.
+	DrivenAxle        string `json:",omitempty"`
+	TyreRadiusFrontMM float32
+	TyreRadiusRearMM  float32
+	// Samples is the number of packets under power the driven axle was
+	// inferred from.
+	Samples int
+	Updated time.Time
+}
+
+// Drivetrain returns the inventory drivetrain matching the driven axle. A
+// rear driven vehicle is reported as FR since the engine position can not
+// be told from the telemetry.
+func (p Profile) Drivetrain() string {
+	switch p.DrivenAxle {
+	case FrontAxle:
+		return "FF"
+	case RearAxle:
+		return "FR"
+	case BothAxles:
+		return "4WD"
+	default:
+		return ""
+	}
+}
+
+// Aspiration returns the inventory aspiration matching the turbo flag.
+func (p Profile) Aspiration() string {
+	if p.HasTurbo {
+		return "TC"
+	}
+
+	return "NA"
+}
+
+// ProfileStore keeps learned vehicle profiles in a JSON file keyed by
+// vehicle ID. A store without a file keeps the profiles in memory only.
+type ProfileStore struct {
+	file     string
+	mu       sync.RWMutex
+	profiles map[string]Profile
+}
+
+// OpenProfileStore loads the profiles in the file, a missing file is created
+// when the first profile is stored.
+func OpenProfileStore(file string) (*ProfileStore, error) {
+	store := &ProfileStore{
+		file:     file,
+		profiles: map[string]Profile{},
+	}
+	if file == "" {
+		return store, nil
+	}
+
+	data, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vehicle profiles: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &store.profiles); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal vehicle profiles %s: %w", file, err)
+	}
+
+	for key, profile := range store.profiles {
+		id, err := strconv.Atoi(key)
+		if err != nil || id != profile.ID {
+			return nil, fmt.Errorf("%s: vehicle %s: CarID %d does not match the key", file, key, profile.ID)
+		}
+	}
+
+	return store, nil
+}
+
+func (s *ProfileStore) Get(id int) (Profile, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	profile, ok := s.profiles[strconv.Itoa(id)]
+
+	return profile, ok
+}
+
+// All returns every profile ordered by vehicle ID.
+func (s *ProfileStore) All() []Profile {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	profiles := make([]Profile, 0, len(s.profiles))
+	for _, profile := range s.profiles {
+		profiles = append(profiles, profile)
+	}
+	sort.Slice(profiles, func(a, b int) bool {
+		return profiles[a].ID < profiles[b].ID
+	})
+
+	return profiles
+}
+
+// Put stores the profile, replacing any earlier profile of the vehicle, and
+// writes the store to its file.
+func (s *ProfileStore) Put(profile Profile) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.profiles[strconv.Itoa(profile.ID)] = profile
+
+	return s.save()
+}
+
+// save writes the store to its file. It must be called with the lock held.
+func (s *ProfileStore) save() error {
+	if s.file == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(s.profiles, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal vehicle profiles: %w", err)
+	}
+
+	if err := utils.WriteFileAtomic(s.file, append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write vehicle profiles: %w", err)
+	}
+
+	return nil
+}
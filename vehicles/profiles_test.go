@@ -0,0 +1,116 @@
+package vehicles
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ProfilesTestSuite struct {
+	suite.Suite
+	file string
+}
+
+func TestProfilesTestSuite(t *testing.T) {
+	suite.Run(t, new(ProfilesTestSuite))
+}
+
+func (suite *ProfilesTestSuite) SetupTest() {
+	suite.file = filepath.Join(suite.T().TempDir(), "profiles.json")
+}
+
+func (suite *ProfilesTestSuite) TestAMissingFileOpensAnEmptyStore() {
+	// Act
+	store, err := OpenProfileStore(suite.file)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Empty(store.All())
+	_, ok := store.Get(82)
+	suite.False(ok)
+}
+
+func (suite *ProfilesTestSuite) TestProfilesArePersistedByVehicleID() {
+	// Arrange
+	store, err := OpenProfileStore(suite.file)
+	suite.Require().NoError(err)
+	profile := Profile{
+		ID:                99001,
+		Gears:             6,
+		GearRatios:        []float32{3.2, 2.1, 1.5, 1.2, 1, 0.8, 0, 0},
+		HasTurbo:          true,
+		DrivenAxle:        RearAxle,
+		TyreRadiusFrontMM: 310,
+		TyreRadiusRearMM:  325,
+		Samples:           300,
+	}
+
+	// Act
+	suite.Require().NoError(store.Put(Profile{ID: 99002}))
+	suite.Require().NoError(store.Put(profile))
+	reopened, err := OpenProfileStore(suite.file)
+
+	// Assert
+	suite.Require().NoError(err)
+	stored, ok := reopened.Get(99001)
+	suite.Require().True(ok)
+	suite.Equal(profile, stored)
+
+	all := reopened.All()
+	suite.Require().Len(all, 2)
+	suite.Equal(99001, all[0].ID)
+	suite.Equal(99002, all[1].ID)
+}
+
+func (suite *ProfilesTestSuite) TestAStoreWithoutAFileIsKeptInMemory() {
+	// Arrange
+	store, err := OpenProfileStore("")
+	suite.Require().NoError(err)
+
+	// Act
+	err = store.Put(Profile{ID: 99001, DrivenAxle: FrontAxle})
+
+	// Assert
+	suite.Require().NoError(err)
+	profile, ok := store.Get(99001)
+	suite.True(ok)
+	suite.Equal(FrontAxle, profile.DrivenAxle)
+}
+
+func (suite *ProfilesTestSuite) TestMismatchedKeysAreRejected() {
+	// Arrange
+	suite.Require().NoError(os.WriteFile(suite.file, []byte(`{"99001": {"CarID": 99002}}`), 0o644))
+
+	// Act
+	_, err := OpenProfileStore(suite.file)
+
+	// Assert
+	suite.ErrorContains(err, "vehicle 99001: CarID 99002 does not match the key")
+}
+
+func (suite *ProfilesTestSuite) TestProfilesMapToInventoryValues() {
+	testCases := map[string]struct {
+		profile    Profile
+		drivetrain string
+		aspiration string
+	}{
+		"unknown axle":   {profile: Profile{}, drivetrain: "", aspiration: "NA"},
+		"front axle":     {profile: Profile{DrivenAxle: FrontAxle}, drivetrain: "FF", aspiration: "NA"},
+		"rear axle":      {profile: Profile{DrivenAxle: RearAxle, HasTurbo: true}, drivetrain: "FR", aspiration: "TC"},
+		"all wheel axle": {profile: Profile{DrivenAxle: BothAxles}, drivetrain: "4WD", aspiration: "NA"},
+	}
+
+	for name, tc := range testCases {
+		suite.Run(name, func() {
+			// Act
+			drivetrain := tc.profile.Drivetrain()
+			aspiration := tc.profile.Aspiration()
+
+			// Assert
+			suite.Equal(tc.drivetrain, drivetrain)
+			suite.Equal(tc.aspiration, aspiration)
+		})
+	}
+}
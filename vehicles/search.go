@@ -0,0 +1,142 @@
+package vehicles
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// accents folds the accented letters used in model names so that a search
+// for "coupe" finds "Coupé".
+var accents = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ä", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "î", "i", "ï", "i",
+	"ó", "o", "ô", "o", "ö", "o",
+	"ú", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n", "ß", "ss",
+)
+
+type searchResult struct {
+	vehicle Vehicle
+	score   int
+}
+
+// Search looks up vehicles by a model name that may be incomplete or
+// misspelled, such as "supra 97" or "skyline gtr r34". The manufacturer can be
+// included in the query. Results are ordered from the closest match and at
+// most limit vehicles are returned, or every match when limit is zero.
+func (i *Inventory) Search(query string, limit int) []Vehicle {
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return []Vehicle{}
+	}
+
+	results := []searchResult{}
+	for _, vehicle := range i.vehicles() {
+		score, ok := matchTerms(terms, searchTerms(vehicle.Name()))
+		if !ok {
+			continue
+		}
+		results = append(results, searchResult{vehicle: vehicle, score: score})
+	}
+
+	sort.Slice(results, func(a, b int) bool {
+		if results[a].score != results[b].score {
+			return results[a].score < results[b].score
+		}
+
+		return results[a].vehicle.ID < results[b].vehicle.ID
+	})
+
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+
+	vehicles := make([]Vehicle, len(results))
+	for n, result := range results {
+		vehicles[n] = result.vehicle
+	}
+
+	return vehicles
+}
+
+// searchTerms splits a name into lower case words without accents or
+// punctuation.
+func searchTerms(name string) []string {
+	name = accents.Replace(strings.ToLower(name))
+
+	return strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// matchTerms scores how well the query terms match the words of a vehicle
+// name, lower scores are closer. Every query term must match a word exactly,
+// as a prefix, or within a small edit distance.
+func matchTerms(query []string, words []string) (int, bool) {
+	score := len(words) - len(query)
+	if score < 0 {
+		score = 0
+	}
+
+	for _, term := range query {
+		best := -1
+		for _, word := range words {
+			cost, ok := matchWord(term, word)
+			if ok && (best < 0 || cost < best) {
+				best = cost
+			}
+		}
+		if best < 0 {
+			return 0, false
+		}
+		score += best * 4
+	}
+
+	return score, true
+}
+
+func matchWord(term string, word string) (int, bool) {
+	switch {
+	case term == word:
+		return 0, true
+	case strings.HasPrefix(word, term):
+		return 1, true
+	}
+
+	allowed := len([]rune(term)) / 4
+	if allowed == 0 {
+		return 0, false
+	}
+
+	distance := levenshtein(term, word)
+	if distance > allowed {
+		return 0, false
+	}
+
+	return 1 + distance, true
+}
+
+func levenshtein(a string, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(rb)]
+}
@@ -0,0 +1,250 @@
+package vehicles
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrVehicleNotFound is returned when a vehicle ID is not in the inventory.
+var ErrVehicleNotFound = errors.New("vehicle not found")
+
+type Vehicle struct {
+	ID           int `json:"CarID"`
+	Model        string
+	Manufacturer string
+	Year         int
+	Category     string
+	CarType      string
+	Drivetrain   string
+	Aspiration   string
+	OpenCockpit  bool
+	// Powertrain is EV or Hybrid for vehicles that are not driven by a
+	// combustion engine alone, and empty otherwise.
+	Powertrain string `json:",omitempty"`
+
+	// Specifications are optional and zero when unknown.
+	PowerKW  float32 `json:",omitempty"`
+	TorqueNM float32 `json:",omitempty"`
+	WeightKG float32 `json:",omitempty"`
+	// WeightDistribution is the percentage of the weight on the front axle.
+	WeightDistribution float32 `json:",omitempty"`
+	WheelbaseMM        float32 `json:",omitempty"`
+	TrackWidthFrontMM  float32 `json:",omitempty"`
+	TrackWidthRearMM   float32 `json:",omitempty"`
+	// PP is the performance points rating of the vehicle in its stock form.
+	PP             float32 `json:",omitempty"`
+	EngineLayout   string  `json:",omitempty"`
+	DisplacementCC int     `json:",omitempty"`
+	FuelTankLitres float32 `json:",omitempty"`
+}
+
+const (
+	PowertrainElectric = "EV"
+	PowertrainHybrid   = "Hybrid"
+)
+
+// Inventory holds the vehicles known to the client. It is built from the
+// embedded inventory with any override files merged over it in order.
+type Inventory struct {
+	files    []string
+	mu       sync.RWMutex
+	db       map[string]Vehicle
+	version  uint64
+	modTimes map[string]time.Time
+}
+
+//go:embed inventory.json
+var baseInventoryJSON []byte
+
+// NewInventory loads the embedded inventory and merges the override files
+// over it by vehicle ID. Fields missing from an override entry keep the value
+// from the earlier layers so an override only needs the fields it changes.
+func NewInventory(files ...string) (*Inventory, error) {
+	inventory := Inventory{}
+	for _, file := range files {
+		if file != "" {
+			inventory.files = append(inventory.files, file)
+		}
+	}
+
+	if err := inventory.Reload(); err != nil {
+		return &Inventory{}, err
+	}
+
+	return &inventory, nil
+}
+
+// Reload reads the inventory layers again. The current vehicles are kept
+// when any layer fails to load.
+func (i *Inventory) Reload() error {
+	modTimes := map[string]time.Time{}
+	for _, file := range i.files {
+		if info, err := os.Stat(file); err == nil {
+			modTimes[file] = info.ModTime()
+		}
+	}
+
+	db, err := loadLayers(i.files)
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.modTimes = modTimes
+	if err != nil {
+		return err
+	}
+
+	i.db = db
+	i.version++
+
+	return nil
+}
+
+// Version changes every time the inventory is reloaded.
+func (i *Inventory) Version() uint64 {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	return i.version
+}
+
+func (i *Inventory) vehicles() map[string]Vehicle {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	return i.db
+}
+
+func (i *Inventory) GetVehicleByID(id int) (Vehicle, error) {
+	vehicle, ok := i.vehicles()[strconv.Itoa(id)]
+	if !ok {
+		return Vehicle{}, fmt.Errorf("%w: vehicle with id %d not found", ErrVehicleNotFound, id)
+	}
+
+	return vehicle, nil
+}
+
+// Len returns the number of vehicles in the inventory.
+func (i *Inventory) Len() int {
+	return len(i.vehicles())
+}
+
+// All returns every vehicle in the inventory ordered by ID.
+func (i *Inventory) All() []Vehicle {
+	return i.Filter(Filter{})
+}
+
+// Manufacturers returns the name of every manufacturer in the inventory in
+// alphabetical order.
+func (i *Inventory) Manufacturers() []string {
+	seen := map[string]bool{}
+	manufacturers := []string{}
+
+	for _, vehicle := range i.vehicles() {
+		if seen[vehicle.Manufacturer] {
+			continue
+		}
+		seen[vehicle.Manufacturer] = true
+		manufacturers = append(manufacturers, vehicle.Manufacturer)
+	}
+	sort.Strings(manufacturers)
+
+	return manufacturers
+}
+
+// Filter selects vehicles from the inventory, fields left at their zero
+// value match every vehicle. Text fields are compared without regard to case.
+type Filter struct {
+	Manufacturer string
+	// MinYear and MaxYear bound the model year, inclusive.
+	MinYear     int
+	MaxYear     int
+	Drivetrain  string
+	Aspiration  string
+	Powertrain  string
+	Category    string
+	CarType     string
+	OpenCockpit *bool
+}
+
+func (f Filter) matches(vehicle Vehicle) bool {
+	switch {
+	case f.Manufacturer != "" && !strings.EqualFold(f.Manufacturer, vehicle.Manufacturer):
+		return false
+	case f.MinYear != 0 && vehicle.Year < f.MinYear:
+		return false
+	case f.MaxYear != 0 && vehicle.Year > f.MaxYear:
+		return false
+	case f.Drivetrain != "" && !strings.EqualFold(f.Drivetrain, vehicle.Drivetrain):
+		return false
+	case f.Aspiration != "" && !strings.EqualFold(f.Aspiration, vehicle.Aspiration):
+		return false
+	case f.Powertrain != "" && !strings.EqualFold(f.Powertrain, vehicle.Powertrain):
+		return false
+	case f.Category != "" && !strings.EqualFold(f.Category, vehicle.Category):
+		return false
+	case f.CarType != "" && !strings.EqualFold(f.CarType, vehicle.CarType):
+		return false
+	case f.OpenCockpit != nil && *f.OpenCockpit != vehicle.OpenCockpit:
+		return false
+	}
+
+	return true
+}
+
+// Filter returns the vehicles matching the filter ordered by ID.
+func (i *Inventory) Filter(filter Filter) []Vehicle {
+	matches := []Vehicle{}
+	for _, vehicle := range i.vehicles() {
+		if filter.matches(vehicle) {
+			matches = append(matches, vehicle)
+		}
+	}
+
+	sort.Slice(matches, func(a, b int) bool {
+		return matches[a].ID < matches[b].ID
+	})
+
+	return matches
+}
+
+func (v *Vehicle) ExpandedAspiration() string {
+	switch v.Aspiration {
+	case "NA":
+		return "Naturally Aspirated"
+	case "TC":
+		return "Turbocharged"
+	case "SC":
+		return "Supercharged"
+	case "TC+SC":
+		return "Compound Charged"
+	case "EV":
+		return "Electric"
+	default:
+		return v.Aspiration
+	}
+}
+
+// Electric reports whether the vehicle is driven by electric motors alone.
+// Older inventories flag these with an EV aspiration instead.
+func (v *Vehicle) Electric() bool {
+	return v.Powertrain == PowertrainElectric || v.Aspiration == "EV"
+}
+
+// Hybrid reports whether the vehicle combines a combustion engine with
+// electric motors.
+func (v *Vehicle) Hybrid() bool {
+	return v.Powertrain == PowertrainHybrid
+}
+
+// Name returns the manufacturer and model of the vehicle.
+func (v *Vehicle) Name() string {
+	return strings.TrimSpace(v.Manufacturer + " " + v.Model)
+}
@@ -0,0 +1,187 @@
+package vehicles
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type InventoryTestSuite struct {
+	suite.Suite
+	inventory *Inventory
+}
+
+func TestInventoryTestSuite(t *testing.T) {
+	suite.Run(t, new(InventoryTestSuite))
+}
+
+func (suite *InventoryTestSuite) SetupSuite() {
+	inventory, err := NewInventory("")
+	suite.Require().NoError(err)
+
+	suite.inventory = inventory
+}
+
+func (suite *InventoryTestSuite) TestGetVehicleByIDReturnsTheRecordWithItsID() {
+	// Act
+	vehicle, err := suite.inventory.GetVehicleByID(82)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(82, vehicle.ID)
+	suite.Equal("Toyota", vehicle.Manufacturer)
+	suite.Equal("Supra RZ '97", vehicle.Model)
+	suite.Equal("Toyota Supra RZ '97", vehicle.Name())
+}
+
+func (suite *InventoryTestSuite) TestGetVehicleByIDReportsUnknownVehicles() {
+	// Act
+	_, err := suite.inventory.GetVehicleByID(-1)
+
+	// Assert
+	suite.ErrorIs(err, ErrVehicleNotFound)
+}
+
+func (suite *InventoryTestSuite) TestAllListsEveryVehicleByID() {
+	// Act
+	vehicles := suite.inventory.All()
+
+	// Assert
+	suite.Len(vehicles, suite.inventory.Len())
+	for i := 1; i < len(vehicles); i++ {
+		suite.Less(vehicles[i-1].ID, vehicles[i].ID)
+	}
+}
+
+func (suite *InventoryTestSuite) TestManufacturersAreUniqueAndSorted() {
+	// Act
+	manufacturers := suite.inventory.Manufacturers()
+
+	// Assert
+	suite.Contains(manufacturers, "Toyota")
+	suite.IsNonDecreasing(manufacturers)
+	for i := 1; i < len(manufacturers); i++ {
+		suite.NotEqual(manufacturers[i-1], manufacturers[i])
+	}
+}
+
+func (suite *InventoryTestSuite) TestFilterMatchesEveryField() {
+	// Arrange
+	openCockpit := false
+	filter := Filter{
+		Manufacturer: "nissan",
+		MinYear:      1990,
+		MaxYear:      1999,
+		Drivetrain:   "4wd",
+		Aspiration:   "tc",
+		CarType:      "street",
+		OpenCockpit:  &openCockpit,
+	}
+
+	// Act
+	vehicles := suite.inventory.Filter(filter)
+
+	// Assert
+	suite.NotEmpty(vehicles)
+	for _, vehicle := range vehicles {
+		suite.Equal("Nissan", vehicle.Manufacturer)
+		suite.GreaterOrEqual(vehicle.Year, 1990)
+		suite.LessOrEqual(vehicle.Year, 1999)
+		suite.Equal("4WD", vehicle.Drivetrain)
+		suite.Equal("TC", vehicle.Aspiration)
+		suite.False(vehicle.OpenCockpit)
+	}
+}
+
+func (suite *InventoryTestSuite) TestFilterByCategoryIgnoresCase() {
+	// Act
+	vehicles := suite.inventory.Filter(Filter{Category: "gr.3"})
+
+	// Assert
+	suite.NotEmpty(vehicles)
+	for _, vehicle := range vehicles {
+		suite.Contains([]string{"Gr.3", "GR.3"}, vehicle.Category)
+	}
+}
+
+func (suite *InventoryTestSuite) TestPowertrainsAreFlagged() {
+	testCases := map[string]struct {
+		id       int
+		electric bool
+		hybrid   bool
+	}{
+		"combustion": {id: 82},
+		"electric":   {id: 3390, electric: true},
+		"hybrid":     {id: 3459, hybrid: true},
+	}
+
+	for name, tc := range testCases {
+		suite.Run(name, func() {
+			// Act
+			vehicle, err := suite.inventory.GetVehicleByID(tc.id)
+
+			// Assert
+			suite.Require().NoError(err)
+			suite.Equal(tc.electric, vehicle.Electric())
+			suite.Equal(tc.hybrid, vehicle.Hybrid())
+		})
+	}
+}
+
+func (suite *InventoryTestSuite) TestElectricAspirationMarksAnElectricVehicle() {
+	// Arrange
+	vehicle := Vehicle{Aspiration: "EV"}
+
+	// Assert
+	suite.True(vehicle.Electric())
+	suite.Equal("Electric", vehicle.ExpandedAspiration())
+}
+
+func (suite *InventoryTestSuite) TestFilterByPowertrain() {
+	// Act
+	vehicles := suite.inventory.Filter(Filter{Powertrain: "hybrid"})
+
+	// Assert
+	suite.NotEmpty(vehicles)
+	for _, vehicle := range vehicles {
+		suite.True(vehicle.Hybrid())
+	}
+}
+
+func (suite *InventoryTestSuite) TestSearchFindsModels() {
+	testCases := map[string]struct {
+		query string
+		want  int
+	}{
+		"partial name":    {"supra 97", 82},
+		"with make":       {"toyota supra gt500", 1470},
+		"misspelled":      {"suppra rz 97", 82},
+		"without accents": {"chevelle sport coupe", 36},
+		"different case":  {"CHEVELLE SS 454 '70", 30},
+	}
+
+	for name, tc := range testCases {
+		suite.Run(name, func() {
+			// Act
+			vehicles := suite.inventory.Search(tc.query, 1)
+
+			// Assert
+			suite.Require().Len(vehicles, 1)
+			suite.Equal(tc.want, vehicles[0].ID)
+		})
+	}
+}
+
+func (suite *InventoryTestSuite) TestSearchWithoutMatchesIsEmpty() {
+	// Assert
+	suite.Empty(suite.inventory.Search("zzzz qqqq", 0))
+	suite.Empty(suite.inventory.Search("  ", 0))
+}
+
+func (suite *InventoryTestSuite) TestSearchLimitsResults() {
+	// Act
+	vehicles := suite.inventory.Search("supra", 3)
+
+	// Assert
+	suite.Len(vehicles, 3)
+}